@@ -0,0 +1,226 @@
+package notgogithub
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/joho/godotenv"
+	"golang.org/x/oauth2"
+)
+
+// DefaultTokenEnv - переменная окружения, из которой NewGitHubService по умолчанию берет токен
+const DefaultTokenEnv = "GITHUB_TOKEN"
+
+// ErrEmptyToken возвращается, если источник учетных данных не предоставил токен
+var ErrEmptyToken = errors.New("empty access token")
+
+// Authenticator предоставляет учетные данные для запросов к GitHub API.
+//
+// TokenSource вызывается один раз при создании сервиса. client - неаутентифицированный
+// клиент, настроенный на тот же адрес API, что и сервис; он нужен провайдерам,
+// которые сами обращаются к GitHub за токеном. Nil-источник означает анонимные запросы
+type Authenticator interface {
+	TokenSource(ctx context.Context, client *github.Client) (oauth2.TokenSource, error)
+}
+
+// WithAuth задает способ аутентификации. По умолчанию используется EnvToken(DefaultTokenEnv).
+// Для nil NewGitHubService возвращает ошибку
+func WithAuth(auth Authenticator) Option {
+	return func(o *options) {
+		o.auth = auth
+	}
+}
+
+// AuthFunc позволяет использовать обычную функцию в качестве Authenticator
+type AuthFunc func(ctx context.Context, client *github.Client) (oauth2.TokenSource, error)
+
+func (f AuthFunc) TokenSource(ctx context.Context, client *github.Client) (oauth2.TokenSource, error) {
+	return f(ctx, client)
+}
+
+// FromTokenSource использует готовый oauth2.TokenSource
+func FromTokenSource(ts oauth2.TokenSource) Authenticator {
+	return AuthFunc(func(context.Context, *github.Client) (oauth2.TokenSource, error) {
+		return ts, nil
+	})
+}
+
+// StaticToken использует заранее известный токен доступа (например, personal access token).
+// Токен проверяется только на пустоту, без запроса к GitHub
+func StaticToken(token string) Authenticator {
+	return AuthFunc(func(context.Context, *github.Client) (oauth2.TokenSource, error) {
+		if token == "" {
+			return nil, ErrEmptyToken
+		}
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}), nil
+	})
+}
+
+// EnvToken берет токен из переменной окружения name
+func EnvToken(name string) Authenticator {
+	return AuthFunc(func(context.Context, *github.Client) (oauth2.TokenSource, error) {
+		token := os.Getenv(name)
+		if token == "" {
+			return nil, fmt.Errorf("environment variable %s: %w", name, ErrEmptyToken)
+		}
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}), nil
+	})
+}
+
+// DotEnvToken берет токен из переменной name, объявленной в .env-файле path.
+// Переменные окружения процесса при этом не изменяются
+func DotEnvToken(path, name string) Authenticator {
+	return AuthFunc(func(context.Context, *github.Client) (oauth2.TokenSource, error) {
+		env, err := godotenv.Read(path)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		}
+
+		token := env[name]
+		if token == "" {
+			return nil, fmt.Errorf("%s in %s: %w", name, path, ErrEmptyToken)
+		}
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}), nil
+	})
+}
+
+// Unauthenticated отправляет запросы анонимно (лимит - 60 запросов в час)
+func Unauthenticated() Authenticator {
+	return AuthFunc(func(context.Context, *github.Client) (oauth2.TokenSource, error) {
+		return nil, nil
+	})
+}
+
+// AppInstallationToken аутентифицирует сервис как установку GitHub App.
+// privateKeyPEM - закрытый ключ приложения в формате PEM (PKCS#1 или PKCS#8).
+// Токены установки выпускаются по JWT, подписанному этим ключом, и обновляются по истечении срока
+func AppInstallationToken(appID, installationID int64, privateKeyPEM []byte) Authenticator {
+	return AuthFunc(func(ctx context.Context, client *github.Client) (oauth2.TokenSource, error) {
+		key, err := parseRSAPrivateKey(privateKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("parse app private key: %w", err)
+		}
+
+		// Клиент, подписывающий запросы JWT приложения, с тем же адресом API
+		base := client.Client()
+		appClient := github.NewClient(&http.Client{
+			Transport: &appJWTTransport{appID: appID, key: key, base: base.Transport},
+			Timeout:   installationTokenTimeout,
+		})
+		appClient.BaseURL = client.BaseURL
+		appClient.UploadURL = client.UploadURL
+
+		return &installationTokenSource{client: appClient, installationID: installationID}, nil
+	})
+}
+
+// installationTokenTimeout ограничивает выпуск токена установки: oauth2 не передает в Token
+// контекст запроса, поэтому зависший выпуск иначе нельзя было бы прервать
+const installationTokenTimeout = 30 * time.Second
+
+// installationTokenSource выпускает токены установки GitHub App. Токен обновляется
+// во время запросов сервиса, уже после создания, поэтому контекст конструктора не используется,
+// а время выпуска ограничено таймаутом клиента (installationTokenTimeout)
+type installationTokenSource struct {
+	client         *github.Client
+	installationID int64
+}
+
+func (s *installationTokenSource) Token() (*oauth2.Token, error) {
+	token, _, err := s.client.Apps.CreateInstallationToken(context.Background(), s.installationID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create installation token: %w", err)
+	}
+
+	return &oauth2.Token{
+		AccessToken: token.GetToken(),
+		TokenType:   "token",
+		Expiry:      token.GetExpiresAt(),
+	}, nil
+}
+
+// appJWTTransport подписывает запросы JWT от имени GitHub App
+type appJWTTransport struct {
+	appID int64
+	key   *rsa.PrivateKey
+	base  http.RoundTripper
+}
+
+func (t *appJWTTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	jwt, err := appJWT(t.appID, t.key, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// RoundTrip не должен изменять исходный запрос
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+jwt)
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// appJWT создает JWT (RS256), которым GitHub App подтверждает свою подлинность.
+// Время выпуска сдвинуто на минуту назад на случай расхождения часов, срок жизни - не более 10 минут
+func appJWT(appID int64, key *rsa.PrivateKey, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]int64{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": appID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	signingInput := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+
+	hash := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", fmt.Errorf("sign jwt: %w", err)
+	}
+
+	return signingInput + "." + enc.EncodeToString(signature), nil
+}
+
+func parseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not RSA")
+	}
+	return key, nil
+}
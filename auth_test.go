@@ -0,0 +1,140 @@
+package notgogithub
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v45/github"
+)
+
+func TestTokenProviders(t *testing.T) {
+	t.Setenv("TEST_GITHUB_TOKEN", "env-token")
+	t.Setenv("EMPTY_GITHUB_TOKEN", "")
+
+	dotenv := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(dotenv, []byte("GITHUB_TOKEN=dotenv-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Arrange
+	testTable := []struct {
+		name     string
+		auth     Authenticator
+		expected string
+		wantErr  bool
+	}{
+		{name: "static", auth: StaticToken("static-token"), expected: "static-token"},
+		{name: "static empty", auth: StaticToken(""), wantErr: true},
+		{name: "env", auth: EnvToken("TEST_GITHUB_TOKEN"), expected: "env-token"},
+		{name: "env empty", auth: EnvToken("EMPTY_GITHUB_TOKEN"), wantErr: true},
+		{name: "dotenv", auth: DotEnvToken(dotenv, "GITHUB_TOKEN"), expected: "dotenv-token"},
+		{name: "dotenv missing key", auth: DotEnvToken(dotenv, "OTHER"), wantErr: true},
+		{name: "dotenv missing file", auth: DotEnvToken(dotenv+".missing", "GITHUB_TOKEN"), wantErr: true},
+	}
+
+	for _, testCase := range testTable {
+		// Act
+		ts, err := testCase.auth.TokenSource(context.Background(), github.NewClient(nil))
+
+		// Assert
+		if testCase.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error", testCase.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", testCase.name, err)
+			continue
+		}
+
+		token, err := ts.Token()
+		if err != nil || token.AccessToken != testCase.expected {
+			t.Errorf("%s: expected token %q, got %v (err %v)", testCase.name, testCase.expected, token, err)
+		}
+	}
+}
+
+func TestNewGitHubServiceValidatesCredentials(t *testing.T) {
+	t.Setenv(DefaultTokenEnv, "")
+
+	if _, err := NewGitHubService(context.Background()); !errors.Is(err, ErrEmptyToken) {
+		t.Errorf("expected ErrEmptyToken without GITHUB_TOKEN, got %v", err)
+	}
+
+	if _, err := NewGitHubService(context.Background(), WithAuth(Unauthenticated())); err != nil {
+		t.Errorf("unauthenticated mode must not require a token: %v", err)
+	}
+
+	if _, err := NewGitHubService(context.Background(), WithAuth(nil)); err == nil {
+		t.Errorf("expected error for nil Authenticator")
+	}
+}
+
+func TestAppInstallationToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/app/installations/42/access_tokens" {
+			http.NotFound(w, r)
+			return
+		}
+
+		// JWT из трех частей, подписанный ключом приложения
+		jwt := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if len(strings.Split(jwt, ".")) != 3 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{
+			"token":      "installation-token",
+			"expires_at": "2099-01-01T00:00:00Z",
+		})
+	}))
+	defer server.Close()
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ts, err := AppInstallationToken(7, 42, keyPEM).TokenSource(ctx, client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Токен обновляется и после того, как контекст конструктора отменен
+	cancel()
+
+	token, err := ts.Token()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token.AccessToken != "installation-token" || token.Expiry.Year() != 2099 {
+		t.Errorf("unexpected token %+v", token)
+	}
+	// Выпуск токена ограничен по времени, хотя контекст запроса в Token не передается
+	if timeout := ts.(*installationTokenSource).client.Client().Timeout; timeout != installationTokenTimeout {
+		t.Errorf("unexpected token client timeout %v", timeout)
+	}
+
+	if _, err := AppInstallationToken(7, 42, []byte("not a key")).TokenSource(context.Background(), client); err == nil {
+		t.Errorf("expected error for invalid private key")
+	}
+}
@@ -0,0 +1,250 @@
+// Package cassette записывает HTTP-взаимодействия с GitHub API в файл ("кассету")
+// и воспроизводит их без сети.
+//
+// Recorder реализует http.RoundTripper и подключается к сервису опцией WithTransport.
+// В режиме ModeRecord запросы уходят в сеть, а пары запрос-ответ сохраняются методом Save.
+// Учетные данные (заголовок Authorization, токены в адресах и ответах) в кассету не попадают.
+// В режиме ModeReplay ответы берутся из кассеты в порядке записи, а запрос,
+// которого в ней нет, завершается ошибкой ErrNoInteraction
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Mode - режим работы Recorder
+type Mode int
+
+const (
+	ModeReplay Mode = iota // Ответы берутся из кассеты, сеть не используется
+	ModeRecord             // Запросы уходят в сеть, взаимодействия записываются в кассету
+)
+
+// redacted заменяет учетные данные в записанных взаимодействиях
+const redacted = "REDACTED"
+
+// ErrNoInteraction возвращается в режиме ModeReplay, если запроса нет в кассете
+var ErrNoInteraction = errors.New("cassette: no recorded interaction")
+
+// Cassette - записанные взаимодействия в порядке их выполнения
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction - запрос и полученный на него ответ
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request - записанный запрос. Заголовки запроса не сохраняются
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response - записанный ответ
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// Recorder записывает или воспроизводит взаимодействия одной кассеты
+type Recorder struct {
+	path string
+	mode Mode
+	real http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// New создает Recorder для кассеты в файле path.
+// В режиме ModeReplay кассета читается сразу, в режиме ModeRecord запросы
+// выполняет real (nil - http.DefaultTransport)
+func New(path string, mode Mode, real http.RoundTripper) (*Recorder, error) {
+	if real == nil {
+		real = http.DefaultTransport
+	}
+	r := &Recorder{path: path, mode: mode, real: real}
+
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("cassette: parse %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// Mode возвращает режим работы Recorder
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// RoundTrip выполняет запрос через сеть или воспроизводит ответ из кассеты
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	secret := credential(req.Header.Get("Authorization"))
+	recorded := Request{
+		Method: req.Method,
+		URL:    scrubURL(req.URL),
+		Body:   scrub(string(body), secret),
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+	return r.record(req, recorded, secret)
+}
+
+// Save записывает кассету в файл. В режиме ModeReplay ничего не делает
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("cassette: %w", err)
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("cassette: %w", err)
+	}
+	return nil
+}
+
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Одинаковые запросы воспроизводятся в порядке записи
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] || in.Request != recorded {
+			continue
+		}
+		r.used[i] = true
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          io.NopCloser(strings.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, recorded.Method, recorded.URL)
+}
+
+func (r *Recorder) record(req *http.Request, recorded Request, secret string) (*http.Response, error) {
+	resp, err := r.real.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("cassette: read response: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// Длина тела вычисляется при воспроизведении, а куки могут содержать учетные данные
+	header := resp.Header.Clone()
+	header.Del("Content-Length")
+	header.Del("Set-Cookie")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: recorded,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       scrub(string(body), secret),
+		},
+	})
+	return resp, nil
+}
+
+// readBody читает тело запроса и восстанавливает его для дальнейшей отправки
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("cassette: read request: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// credential извлекает секрет из заголовка Authorization вида "token ..." или "Bearer ..."
+func credential(authorization string) string {
+	if i := strings.IndexByte(authorization, ' '); i >= 0 {
+		return authorization[i+1:]
+	}
+	return authorization
+}
+
+// secretParams - параметры адреса, значения которых не записываются
+var secretParams = []string{"access_token", "client_id", "client_secret"}
+
+func scrubURL(u *url.URL) string {
+	scrubbed := *u
+	q := scrubbed.Query()
+	for _, p := range secretParams {
+		if q.Has(p) {
+			q.Set(p, redacted)
+		}
+	}
+	scrubbed.RawQuery = q.Encode()
+	return scrubbed.String()
+}
+
+// tokenField находит токены в ответах, например при выпуске токена установки GitHub App
+var tokenField = regexp.MustCompile(`"token"\s*:\s*"[^"]*"`)
+
+func scrub(s, secret string) string {
+	if secret != "" {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	return tokenField.ReplaceAllString(s, `"token":"`+redacted+`"`)
+}
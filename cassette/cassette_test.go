@@ -0,0 +1,102 @@
+package cassette
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	// Arrange
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Set-Cookie", "session=secret")
+		io.WriteString(w, `{"path":"`+r.URL.Path+`","token":"ghs_installation","echo":"`+r.Header.Get("Authorization")+`"}`)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "test.json")
+	rec, err := New(path, ModeRecord, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	// Act: запись
+	first := get(t, rec, srv.URL+"/first?access_token=ghp_query", "token ghp_secret")
+	get(t, rec, srv.URL+"/second", "token ghp_secret")
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// Assert: учетные данные не попали в кассету
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read cassette: %v", err)
+	}
+	for _, secret := range []string{"ghp_secret", "ghp_query", "ghs_installation", "session=secret"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains %q", secret)
+		}
+	}
+	// Ответ вызывающему коду не изменяется
+	if !strings.Contains(first, "ghs_installation") {
+		t.Errorf("recorded response was altered: %v", first)
+	}
+
+	// Act: воспроизведение без сервера
+	srv.Close()
+	replay, err := New(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	replayedSecond := get(t, replay, srv.URL+"/second", "token other")
+
+	// Assert
+	if calls != 2 {
+		t.Errorf("expected 2 calls to server, got %v", calls)
+	}
+	if !strings.Contains(replayedSecond, `"path":"/second"`) || !strings.Contains(replayedSecond, `"echo":"token REDACTED"`) {
+		t.Errorf("Incorrect replayed response: %v", replayedSecond)
+	}
+
+	// Каждое взаимодействие воспроизводится один раз
+	req, _ := http.NewRequest("GET", srv.URL+"/second", nil)
+	if _, err := replay.RoundTrip(req); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("expected ErrNoInteraction, got %v", err)
+	}
+}
+
+func TestReplayMissingCassette(t *testing.T) {
+	_, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay, nil)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected os.ErrNotExist, got %v", err)
+	}
+}
+
+func get(t *testing.T, rt http.RoundTripper, url, authorization string) string {
+	t.Helper()
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.Header.Set("Authorization", authorization)
+
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	return string(body)
+}
@@ -0,0 +1,186 @@
+// Команда demo выводит данные, которые возвращают методы GitServiceIFace для репозиториев авторов.
+// Токен берется из переменной окружения GITHUB_TOKEN или файла .env.
+// С флагом -write дополнительно создает и удаляет ветку, тег, репозиторий и запрос на слияние
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/joho/godotenv"
+
+	notgogithub "github.com/jellybebra/not-go-github"
+)
+
+func checkGetBranchCommits(ctx context.Context, ghs notgogithub.GitServiceIFace) {
+	fmt.Println("GetBranchCommits:")
+	commits, _ := ghs.GetBranchCommits(ctx, "jostanise", "rsa_encrypted_local_chat", "main")
+	for _, commit := range commits {
+		fmt.Printf("\tTitle:\t\t %v\n", commit.Title)
+		fmt.Printf("\tHash:\t\t %v\n", commit.Hash)
+		fmt.Printf("\tCreatedAt:\t %v\n", commit.CreatedAt)
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+func checkGetUserInfo(ctx context.Context, ghs notgogithub.GitServiceIFace) {
+	user, _ := ghs.GetUserInfo(ctx, "jostanise")
+	fmt.Println("GetUserInfo:")
+	fmt.Println("\tUserName:\t", user.UserName)
+	fmt.Println("\tFullName:\t", user.FullName)
+	fmt.Println("\tFollowersCount:\t", user.FollowersCount)
+	fmt.Println("\tFollowingCount:\t", user.FollowingCount)
+	fmt.Println()
+}
+
+func checkGetUserRepositories(ctx context.Context, ghs notgogithub.GitServiceIFace) {
+	fmt.Println("GetUserRepositories:")
+	repos, _ := ghs.GetUserRepositories(ctx, "")
+	for _, repo := range repos {
+		fmt.Println("\tName:\t\t\t", repo.Name)
+		fmt.Println("\tDescription:\t\t", repo.Description)
+		fmt.Println("\tIsPrivate:\t\t", repo.IsPrivate)
+		fmt.Println("\tStarsCount:\t\t", repo.StarsCount)
+		fmt.Println("\tForksCount:\t\t", repo.ForksCount)
+		fmt.Println("\tLastUpdatedTime:\t", repo.LastUpdatedTime)
+		fmt.Println("\tLanguages:\t", repo.Languages)
+		fmt.Println("\tLink:\t\t\t", repo.Link)
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+func checkGetRepositoryByName(ctx context.Context, ghs notgogithub.GitServiceIFace) {
+	fmt.Println("GetRepositoryByName:")
+	repo, _ := ghs.GetRepositoryByName(ctx, "jostanise", "rsa_encrypted_local_chat")
+	fmt.Println("\tName:\t\t\t", repo.Name)
+	fmt.Println("\tLastUpdatedTime:\t", repo.LastUpdatedTime)
+	fmt.Println("\tLanguages:\t", repo.Languages)
+	fmt.Println()
+}
+
+func checkGetRepositoryBranches(ctx context.Context, ghs notgogithub.GitServiceIFace) {
+	fmt.Println("GetRepositoryBranches:")
+	b, _ := ghs.GetRepositoryBranches(ctx, "PeakIntegral", "cppLessons")
+	for i := 0; i < len(b); i++ {
+		fmt.Println("\tBranch:", b[i].Name, "\tLast update:", b[i].UpdatedAt)
+	}
+	fmt.Println()
+}
+
+func checkGetRepositoryPullRequests(ctx context.Context, ghs notgogithub.GitServiceIFace) {
+	fmt.Println("GetRepositoryPullRequests:")
+	prs, _ := ghs.GetRepositoryPullRequests(ctx, "google", "go-github")
+	for _, pr := range prs {
+		fmt.Println("\tNumber:\t\t", pr.Number)
+		fmt.Println("\tTitle:\t\t", pr.Title)
+		fmt.Println("\tSourceBranch:\t", pr.SourceBranch)
+		fmt.Println("\tTargetBranch:\t", pr.TargetBranch)
+		fmt.Println("\tState:\t\t", pr.State)
+		fmt.Println("\tAuthor:\t\t", pr.Author)
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+func checkGetIssues(ctx context.Context, ghs notgogithub.GitServiceIFace) {
+	fmt.Println("GetIssues")
+	issues, _ := ghs.GetIssues(ctx, "google", "go-github")
+	for _, issue := range issues {
+		fmt.Println("\tTitle:\t\t\t", issue.Title)
+		fmt.Println("\tState:\t\t\t", issue.State)
+		fmt.Println("\tCreatedAt:\t\t", issue.CreatedAt)
+		fmt.Println("\tUpdatedAt:\t\t", issue.UpdatedAt)
+		fmt.Println("\tPullRequestLink:\t", issue.PullRequestLink)
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+func checkGetRepositoryContributors(ctx context.Context, ghs notgogithub.GitServiceIFace) {
+	fmt.Println("GetRepositoryContributors:")
+	contributors, _ := ghs.GetRepositoryContributors(ctx, "google", "go-github")
+	for _, contributor := range contributors {
+		fmt.Println("\tUsername:\t", contributor.UserName)
+		fmt.Println("\tFullname:\t", contributor.FullName)
+		fmt.Println("\tFollowersCount:\t", contributor.FollowersCount)
+		fmt.Println("\tFollowingCount:\t", contributor.FollowingCount)
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+func checkGetRepositoryTags(ctx context.Context, ghs notgogithub.GitServiceIFace) {
+	fmt.Println("GetRepositoryTags:")
+	tags, _ := ghs.GetRepositoryTags(ctx, "jostanise", "rsa_encrypted_local_chat")
+	for _, tag := range tags {
+		fmt.Println("\tTitle:\t\t", tag.Title)
+		fmt.Println("\tHash:\t\t", tag.Hash)
+		fmt.Println("\tZipLink:\t", tag.ZipLink)
+		fmt.Println("\tAnnotated:\t", tag.Annotated)
+		if tag.Release != nil {
+			fmt.Println("\tRelease:\t", tag.Release.Name)
+			fmt.Println("\tDescription:\t", tag.Release.Body)
+			fmt.Println("\tCreatedAt:\t", tag.Release.CreatedAt)
+		}
+		fmt.Println()
+	}
+}
+
+func checkGetThreadsInfo(ctx context.Context, ghs notgogithub.GitServiceIFace) {
+	fmt.Println("GetThreadsInfo:")
+	threads, _ := ghs.GetThreadsInfo(ctx, "google", "go-github", 2403)
+	for _, thread := range threads {
+		fmt.Println("\tFilename:\t\t", thread.Filename)
+		fmt.Println("\tLineOfCode:\t\t", thread.LineOfCode)
+		fmt.Println("\tResolved:\t\t", thread.Resolved)
+		for i, comment := range thread.Conversation {
+			fmt.Printf("\tComment %v:\t\t%v (%v, %v)\n", i, comment.Body, comment.Author, comment.CreatedAt)
+		}
+		fmt.Println()
+	}
+}
+
+func main() {
+	write := flag.Bool("write", false, "изменять репозитории: создать и удалить ветку, тег, репозиторий и запрос на слияние")
+	flag.Parse()
+
+	// Load token
+	godotenv.Load(".env")
+
+	// Authorizing a client
+	ctx := context.Background()
+	ghs, err := notgogithub.NewGitHubService(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Get info
+	checkGetUserRepositories(ctx, ghs)
+	checkGetBranchCommits(ctx, ghs)
+	checkGetUserInfo(ctx, ghs)
+	checkGetRepositoryByName(ctx, ghs)
+	checkGetRepositoryBranches(ctx, ghs)
+	checkGetRepositoryPullRequests(ctx, ghs)
+	checkGetIssues(ctx, ghs)
+	checkGetRepositoryContributors(ctx, ghs)
+	checkGetRepositoryTags(ctx, ghs)
+	checkGetThreadsInfo(ctx, ghs)
+
+	if !*write {
+		return
+	}
+
+	// No output
+	ghs.CreateBranch(ctx, "jostanise", "rsa_encrypted_local_chat", "tessst", "0480a292df58ba0bb4851bf828ed25efc56da813")
+	ghs.DeleteBranch(ctx, "jostanise", "rsa_encrypted_local_chat", "tessst")
+	ghs.CreateTag(ctx, "jostanise", "rsa_encrypted_local_chat", "tessst", "0480a292df58ba0bb4851bf828ed25efc56da813")
+	ghs.DeleteTag(ctx, "jostanise", "rsa_encrypted_local_chat", "tessst")
+	ghs.CreateRepository(ctx, "tessst")
+	ghs.SetAccessToRepository(ctx, "jostanise", "bruevich", "PeakIntegral")
+	ghs.DenyAccessToRepository(ctx, "jostanise", "bruevich", "PeakIntegral")
+	ghs.CreatePullRequest(ctx, "jostanise", "rsa_encrypted_local_chat", "tessst", "main", "tesst_to_main")
+}
@@ -0,0 +1,979 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	notgogithub "github.com/jellybebra/not-go-github"
+)
+
+// command - подкоманда, соответствующая методу GitServiceIFace
+type command struct {
+	name string // Имя, например "branch create"
+	args string // Описание аргументов для справки
+
+	// flags объявляет флаги команды в fs; может быть nil
+	flags func(fs *flag.FlagSet, opts *commandOptions)
+	// nargs - количество позиционных аргументов; -1 - от 0 до 1
+	nargs int
+	exec  func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, opts *commandOptions) (*result, error)
+}
+
+// commandOptions хранит значения флагов команды
+type commandOptions struct {
+	states   string
+	since    string
+	until    string
+	path     string
+	author   string
+	maxDepth int
+
+	releaseName string
+	notes       string
+	target      string
+	draft       bool
+	prerelease  bool
+	latest      bool
+	contentType string
+
+	body       string
+	startLine  int
+	side       string
+	suggestion string
+
+	reviewers   string
+	labels      string
+	mergeMethod string
+	sha         string
+
+	title     string
+	assignees string
+	milestone int
+	reason    string
+}
+
+// listOptions преобразует флаги в опции списочного метода
+func (o *commandOptions) listOptions() ([]notgogithub.ListOption, error) {
+	var opts []notgogithub.ListOption
+	if o.states != "" {
+		var states []notgogithub.State
+		for _, s := range strings.Split(o.states, ",") {
+			states = append(states, notgogithub.State(strings.TrimSpace(s)))
+		}
+		opts = append(opts, notgogithub.WithState(states...))
+	}
+	if o.since != "" {
+		t, err := time.Parse(time.RFC3339, o.since)
+		if err != nil {
+			return nil, fmt.Errorf("-since: %w", err)
+		}
+		opts = append(opts, notgogithub.WithSince(t))
+	}
+	if o.until != "" {
+		t, err := time.Parse(time.RFC3339, o.until)
+		if err != nil {
+			return nil, fmt.Errorf("-until: %w", err)
+		}
+		opts = append(opts, notgogithub.WithUntil(t))
+	}
+	if o.path != "" {
+		opts = append(opts, notgogithub.WithPath(o.path))
+	}
+	if o.author != "" {
+		opts = append(opts, notgogithub.WithAuthor(o.author))
+	}
+	if o.maxDepth > 0 {
+		opts = append(opts, notgogithub.WithMaxDepth(o.maxDepth))
+	}
+	return opts, nil
+}
+
+func stateFlag(fs *flag.FlagSet, opts *commandOptions) {
+	fs.StringVar(&opts.states, "state", "", "состояния через запятую: open, closed, merged, draft")
+}
+
+func historyFlags(fs *flag.FlagSet, opts *commandOptions) {
+	fs.StringVar(&opts.since, "since", "", "коммиты не раньше момента в формате RFC 3339")
+	fs.StringVar(&opts.until, "until", "", "коммиты не позже момента в формате RFC 3339")
+	fs.StringVar(&opts.path, "path", "", "только коммиты, изменившие файл или каталог")
+	fs.StringVar(&opts.author, "author", "", "только коммиты автора (логин GitHub или email)")
+	fs.IntVar(&opts.maxDepth, "max-depth", 0, "не больше n последних коммитов")
+}
+
+func releaseFlags(fs *flag.FlagSet, opts *commandOptions) {
+	fs.StringVar(&opts.releaseName, "name", "", "название релиза")
+	fs.StringVar(&opts.notes, "notes", "", "описание релиза")
+	fs.StringVar(&opts.target, "target", "", "ветка или SHA коммита для нового тега (по умолчанию - ветка по умолчанию)")
+	fs.BoolVar(&opts.draft, "draft", false, "создать черновик")
+	fs.BoolVar(&opts.prerelease, "prerelease", false, "отметить релиз как предварительный")
+	fs.BoolVar(&opts.latest, "latest", false, "сделать релиз последним независимо от даты")
+}
+
+// releaseOptions преобразует флаги в опции CreateRelease
+func (o *commandOptions) releaseOptions() []notgogithub.ReleaseOption {
+	opts := []notgogithub.ReleaseOption{notgogithub.WithReleaseDraft(o.draft), notgogithub.WithReleasePrerelease(o.prerelease)}
+	if o.releaseName != "" {
+		opts = append(opts, notgogithub.WithReleaseName(o.releaseName))
+	}
+	if o.notes != "" {
+		opts = append(opts, notgogithub.WithReleaseNotes(o.notes))
+	}
+	if o.target != "" {
+		opts = append(opts, notgogithub.WithTargetCommitish(o.target))
+	}
+	if o.latest {
+		opts = append(opts, notgogithub.WithReleaseLatest(true))
+	}
+	return opts
+}
+
+func issueFlags(fs *flag.FlagSet, opts *commandOptions) {
+	fs.StringVar(&opts.body, "body", "", "описание проблемы")
+	fs.StringVar(&opts.labels, "labels", "", "метки через запятую")
+	fs.StringVar(&opts.assignees, "assignees", "", "логины исполнителей через запятую")
+	fs.IntVar(&opts.milestone, "milestone", 0, "номер вехи")
+}
+
+// issueOptions преобразует флаги в опции CreateIssue и EditIssue: пустые флаги поля не меняют
+func (o *commandOptions) issueOptions() []notgogithub.IssueOption {
+	var opts []notgogithub.IssueOption
+	if o.title != "" {
+		opts = append(opts, notgogithub.WithIssueTitle(o.title))
+	}
+	if o.body != "" {
+		opts = append(opts, notgogithub.WithIssueBody(o.body))
+	}
+	if o.labels != "" {
+		opts = append(opts, notgogithub.WithIssueLabels(splitList(o.labels)...))
+	}
+	if o.assignees != "" {
+		opts = append(opts, notgogithub.WithIssueAssignees(splitList(o.assignees)...))
+	}
+	if o.milestone > 0 {
+		opts = append(opts, notgogithub.WithIssueMilestone(o.milestone))
+	}
+	return opts
+}
+
+func parseReleaseID(s string) (int64, error) {
+	return parseID("release id", s)
+}
+
+// parseID разбирает идентификатор what (релиза, комментария, ревью) из аргумента s
+func parseID(what, s string) (int64, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", what, err)
+	}
+	return id, nil
+}
+
+func parsePullNumber(s string) (int, error) {
+	number, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("pull request number: %w", err)
+	}
+	return number, nil
+}
+
+func parseIssueNumber(s string) (int, error) {
+	number, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("issue number: %w", err)
+	}
+	return number, nil
+}
+
+// splitList разбирает значение флага со списком через запятую
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// pullRequestCommand - команда <owner> <repo> <number>, которая выводит запрос на слияние,
+// полученный методом call, например notgogithub.GitServiceIFace.ClosePullRequest
+func pullRequestCommand(name string, call func(ghs notgogithub.GitServiceIFace, ctx context.Context, owner, repositoryName string, pullRequestID int) (*notgogithub.PullRequest, error)) *command {
+	return &command{
+		name: name, args: "<owner> <repo> <number>", nargs: 3,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			number, err := parsePullNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			pr, err := call(ghs, ctx, args[0], args[1], number)
+			if err != nil {
+				return nil, err
+			}
+			return pullRequestsResult([]*notgogithub.PullRequest{pr}), nil
+		},
+	}
+}
+
+// issueCommand - команда <owner> <repo> <number>, которая выводит проблему,
+// полученную методом call, например notgogithub.GitServiceIFace.ReopenIssue
+func issueCommand(name string, call func(ghs notgogithub.GitServiceIFace, ctx context.Context, owner, repositoryName string, issueNumber int) (*notgogithub.Issue, error)) *command {
+	return &command{
+		name: name, args: "<owner> <repo> <number>", nargs: 3,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			number, err := parseIssueNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			issue, err := call(ghs, ctx, args[0], args[1], number)
+			if err != nil {
+				return nil, err
+			}
+			return issuesResult([]*notgogithub.Issue{issue}), nil
+		},
+	}
+}
+
+// parseReviewEvent разбирает решение ревью: approve, request-changes или comment
+func parseReviewEvent(s string) (notgogithub.ReviewEvent, error) {
+	event := notgogithub.ReviewEvent(strings.ToUpper(strings.ReplaceAll(s, "-", "_")))
+	switch event {
+	case notgogithub.ReviewApprove, notgogithub.ReviewRequestChanges, notgogithub.ReviewComment:
+		return event, nil
+	}
+	return "", fmt.Errorf("review event %q: expected approve, request-changes or comment", s)
+}
+
+// commands - все подкоманды в порядке вывода в справке
+var commands = []*command{
+	{
+		name: "user info", args: "<user>", nargs: 1,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			user, err := ghs.GetUserInfo(ctx, args[0])
+			if err != nil {
+				return nil, err
+			}
+			return usersResult([]*notgogithub.User{user}), nil
+		},
+	},
+	{
+		name: "repo list", args: "[user]", nargs: -1,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			userName := ""
+			if len(args) > 0 {
+				userName = args[0]
+			}
+			repos, err := ghs.GetUserRepositories(ctx, userName)
+			if err != nil {
+				return nil, err
+			}
+			return repositoriesResult(repos), nil
+		},
+	},
+	{
+		name: "repo get", args: "<owner> <repo>", nargs: 2,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			repo, err := ghs.GetRepositoryByName(ctx, args[0], args[1])
+			if err != nil {
+				return nil, err
+			}
+			return repositoriesResult([]*notgogithub.Repository{repo}), nil
+		},
+	},
+	{
+		name: "repo create", args: "<name>", nargs: 1,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			return nil, ghs.CreateRepository(ctx, args[0])
+		},
+	},
+	{
+		name: "branch list", args: "<owner> <repo>", nargs: 2,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			branches, err := ghs.GetRepositoryBranches(ctx, args[0], args[1])
+			if err != nil {
+				return nil, err
+			}
+			return branchesResult(branches), nil
+		},
+	},
+	{
+		name: "branch create", args: "<owner> <repo> <branch> <sha>", nargs: 4,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			return nil, ghs.CreateBranch(ctx, args[0], args[1], args[2], args[3])
+		},
+	},
+	{
+		name: "branch delete", args: "<owner> <repo> <branch>", nargs: 3,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			return nil, ghs.DeleteBranch(ctx, args[0], args[1], args[2])
+		},
+	},
+	{
+		name: "commits", args: "[-since t] [-until t] [-path p] [-author a] [-max-depth n] <owner> <repo> <branch>", nargs: 3,
+		flags: historyFlags,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, opts *commandOptions) (*result, error) {
+			listOpts, err := opts.listOptions()
+			if err != nil {
+				return nil, err
+			}
+			commits, err := ghs.GetBranchCommits(ctx, args[0], args[1], args[2], listOpts...)
+			if err != nil {
+				return nil, err
+			}
+			return commitsResult(commits), nil
+		},
+	},
+	{
+		name: "pr list", args: "[-state s] <owner> <repo>", nargs: 2,
+		flags: stateFlag,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, opts *commandOptions) (*result, error) {
+			listOpts, err := opts.listOptions()
+			if err != nil {
+				return nil, err
+			}
+			prs, err := ghs.GetRepositoryPullRequests(ctx, args[0], args[1], listOpts...)
+			if err != nil {
+				return nil, err
+			}
+			return pullRequestsResult(prs), nil
+		},
+	},
+	{
+		name: "pr create", args: "[-body text] [-draft] [-reviewers a,b] [-labels a,b] <owner> <repo> <source> <target> <title>", nargs: 5,
+		flags: func(fs *flag.FlagSet, opts *commandOptions) {
+			fs.StringVar(&opts.body, "body", "", "описание запроса на слияние")
+			fs.BoolVar(&opts.draft, "draft", false, "создать черновик")
+			fs.StringVar(&opts.reviewers, "reviewers", "", "логины рецензентов через запятую")
+			fs.StringVar(&opts.labels, "labels", "", "метки через запятую")
+		},
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, opts *commandOptions) (*result, error) {
+			pr, err := ghs.CreatePullRequest(ctx, args[0], args[1], args[2], args[3], args[4],
+				notgogithub.WithPullRequestBody(opts.body), notgogithub.WithPullRequestDraft(opts.draft),
+				notgogithub.WithReviewers(splitList(opts.reviewers)...), notgogithub.WithPullRequestLabels(splitList(opts.labels)...))
+			if err != nil && pr != nil {
+				return nil, fmt.Errorf("pull request #%d created: %w", pr.Number, err)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return pullRequestsResult([]*notgogithub.PullRequest{pr}), nil
+		},
+	},
+	pullRequestCommand("pr get", notgogithub.GitServiceIFace.GetPullRequest),
+	{
+		name: "pr merge", args: "[-method merge|squash|rebase] [-sha sha] <owner> <repo> <number>", nargs: 3,
+		flags: func(fs *flag.FlagSet, opts *commandOptions) {
+			fs.StringVar(&opts.mergeMethod, "method", "", "способ слияния: merge, squash или rebase (по умолчанию merge)")
+			fs.StringVar(&opts.sha, "sha", "", "слить, только если последний коммит ветки-источника - этот")
+		},
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, opts *commandOptions) (*result, error) {
+			number, err := parsePullNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			sha, err := ghs.MergePullRequest(ctx, args[0], args[1], number, notgogithub.MergeMethod(opts.mergeMethod), opts.sha)
+			if err != nil {
+				return nil, err
+			}
+			return mergeResult(sha), nil
+		},
+	},
+	pullRequestCommand("pr close", notgogithub.GitServiceIFace.ClosePullRequest),
+	pullRequestCommand("pr reopen", notgogithub.GitServiceIFace.ReopenPullRequest),
+	pullRequestCommand("pr draft", notgogithub.GitServiceIFace.ConvertPullRequestToDraft),
+	pullRequestCommand("pr ready", notgogithub.GitServiceIFace.MarkPullRequestReadyForReview),
+	{
+		name: "pr edit", args: "<owner> <repo> <number> <title> <body>", nargs: 5,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			number, err := parsePullNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			pr, err := ghs.UpdatePullRequestTitleBody(ctx, args[0], args[1], number, args[3], args[4])
+			if err != nil {
+				return nil, err
+			}
+			return pullRequestsResult([]*notgogithub.PullRequest{pr}), nil
+		},
+	},
+	{
+		name: "pr base", args: "<owner> <repo> <number> <target>", nargs: 4,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			number, err := parsePullNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			pr, err := ghs.ChangePullRequestBase(ctx, args[0], args[1], number, args[3])
+			if err != nil {
+				return nil, err
+			}
+			return pullRequestsResult([]*notgogithub.PullRequest{pr}), nil
+		},
+	},
+	{
+		name: "pr files", args: "<owner> <repo> <number>", nargs: 3,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			number, err := parsePullNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			files, err := ghs.GetPullRequestFiles(ctx, args[0], args[1], number)
+			if err != nil {
+				return nil, err
+			}
+			return filesResult(files), nil
+		},
+	},
+	{
+		name: "pr diff", args: "<owner> <repo> <number>", nargs: 3,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			number, err := parsePullNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			diff, err := ghs.GetPullRequestDiff(ctx, args[0], args[1], number)
+			if err != nil {
+				return nil, err
+			}
+			return textResult(diff), nil
+		},
+	},
+	{
+		name: "pr patch", args: "<owner> <repo> <number>", nargs: 3,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			number, err := parsePullNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			patch, err := ghs.GetPullRequestPatch(ctx, args[0], args[1], number)
+			if err != nil {
+				return nil, err
+			}
+			return textResult(patch), nil
+		},
+	},
+	{
+		name: "pr commits", args: "<owner> <repo> <number>", nargs: 3,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			number, err := parsePullNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			commits, err := ghs.GetPullRequestCommits(ctx, args[0], args[1], number)
+			if err != nil {
+				return nil, err
+			}
+			return commitsResult(commits), nil
+		},
+	},
+	{
+		name: "pr mergeable", args: "<owner> <repo> <number>", nargs: 3,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			number, err := parsePullNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			m, err := ghs.GetPullRequestMergeability(ctx, args[0], args[1], number)
+			if err != nil {
+				return nil, err
+			}
+			return mergeabilityResult(m), nil
+		},
+	},
+	{
+		name: "pr threads", args: "<owner> <repo> <number>", nargs: 3,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			number, err := parsePullNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			threads, err := ghs.GetThreadsInfo(ctx, args[0], args[1], number)
+			if err != nil {
+				return nil, err
+			}
+			return threadsResult(threads), nil
+		},
+	},
+	{
+		name: "pr reviews", args: "<owner> <repo> <number>", nargs: 3,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			number, err := parsePullNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			reviews, err := ghs.GetReviews(ctx, args[0], args[1], number)
+			if err != nil {
+				return nil, err
+			}
+			return reviewsResult(reviews), nil
+		},
+	},
+	{
+		name: "pr review", args: "[-body text] <owner> <repo> <number> <approve|request-changes|comment>", nargs: 4,
+		flags: func(fs *flag.FlagSet, opts *commandOptions) {
+			fs.StringVar(&opts.body, "body", "", "текст ревью")
+		},
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, opts *commandOptions) (*result, error) {
+			number, err := parsePullNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			event, err := parseReviewEvent(args[3])
+			if err != nil {
+				return nil, err
+			}
+			review, err := ghs.CreateReview(ctx, args[0], args[1], number, event, opts.body)
+			if err != nil {
+				return nil, err
+			}
+			return reviewsResult([]*notgogithub.Review{review}), nil
+		},
+	},
+	{
+		name: "pr dismiss", args: "<owner> <repo> <number> <review id> <message>", nargs: 5,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			number, err := parsePullNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			id, err := parseID("review id", args[3])
+			if err != nil {
+				return nil, err
+			}
+			review, err := ghs.DismissReview(ctx, args[0], args[1], number, id, args[4])
+			if err != nil {
+				return nil, err
+			}
+			return reviewsResult([]*notgogithub.Review{review}), nil
+		},
+	},
+	{
+		name: "pr comment", args: "[-start line] [-side LEFT|RIGHT] [-suggest code] <owner> <repo> <number> <path> <line> <body>", nargs: 6,
+		flags: func(fs *flag.FlagSet, opts *commandOptions) {
+			fs.IntVar(&opts.startLine, "start", 0, "первая строка диапазона (по умолчанию - одна строка line)")
+			fs.StringVar(&opts.side, "side", "", "сторона diff: LEFT или RIGHT (по умолчанию RIGHT)")
+			fs.StringVar(&opts.suggestion, "suggest", "", "предложить замену строк на этот код")
+		},
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, opts *commandOptions) (*result, error) {
+			number, err := parsePullNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			line, err := strconv.Atoi(args[4])
+			if err != nil {
+				return nil, fmt.Errorf("line: %w", err)
+			}
+			comment := notgogithub.LineComment{
+				Path:      args[3],
+				Line:      line,
+				StartLine: opts.startLine,
+				Side:      notgogithub.Side(strings.ToUpper(opts.side)),
+				Body:      args[5],
+			}
+			if opts.suggestion != "" {
+				comment.Body += "\n\n" + notgogithub.Suggestion(opts.suggestion)
+			}
+			c, err := ghs.CreateLineComment(ctx, args[0], args[1], number, comment)
+			if err != nil {
+				return nil, err
+			}
+			return commentsResult([]notgogithub.Comment{*c}), nil
+		},
+	},
+	{
+		name: "pr reply", args: "<owner> <repo> <number> <thread id> <body>", nargs: 5,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			number, err := parsePullNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			id, err := parseID("thread id", args[3])
+			if err != nil {
+				return nil, err
+			}
+			c, err := ghs.ReplyToThread(ctx, args[0], args[1], number, id, args[4])
+			if err != nil {
+				return nil, err
+			}
+			return commentsResult([]notgogithub.Comment{*c}), nil
+		},
+	},
+	{
+		name: "pr edit-comment", args: "<owner> <repo> <number> <comment id> <body>", nargs: 5,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			number, err := parsePullNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			id, err := parseID("comment id", args[3])
+			if err != nil {
+				return nil, err
+			}
+			c, err := ghs.EditComment(ctx, args[0], args[1], number, id, args[4])
+			if err != nil {
+				return nil, err
+			}
+			return commentsResult([]notgogithub.Comment{*c}), nil
+		},
+	},
+	{
+		name: "pr delete-comment", args: "<owner> <repo> <number> <comment id>", nargs: 4,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			number, err := parsePullNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			id, err := parseID("comment id", args[3])
+			if err != nil {
+				return nil, err
+			}
+			return nil, ghs.DeleteComment(ctx, args[0], args[1], number, id)
+		},
+	},
+	{
+		name: "issues", args: "[-state s] <owner> <repo>", nargs: 2,
+		flags: stateFlag,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, opts *commandOptions) (*result, error) {
+			listOpts, err := opts.listOptions()
+			if err != nil {
+				return nil, err
+			}
+			issues, err := ghs.GetIssues(ctx, args[0], args[1], listOpts...)
+			if err != nil {
+				return nil, err
+			}
+			return issuesResult(issues), nil
+		},
+	},
+	{
+		name: "issue create", args: "[-body text] [-labels a,b] [-assignees a,b] [-milestone n] <owner> <repo> <title>", nargs: 3,
+		flags: issueFlags,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, opts *commandOptions) (*result, error) {
+			issue, err := ghs.CreateIssue(ctx, args[0], args[1], args[2], opts.issueOptions()...)
+			if err != nil {
+				return nil, err
+			}
+			return issuesResult([]*notgogithub.Issue{issue}), nil
+		},
+	},
+	issueCommand("issue get", notgogithub.GitServiceIFace.GetIssue),
+	{
+		name: "issue edit", args: "[-title t] [-body text] [-labels a,b] [-assignees a,b] [-milestone n] <owner> <repo> <number>", nargs: 3,
+		flags: func(fs *flag.FlagSet, opts *commandOptions) {
+			fs.StringVar(&opts.title, "title", "", "тема проблемы")
+			issueFlags(fs, opts)
+		},
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, opts *commandOptions) (*result, error) {
+			number, err := parseIssueNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			issue, err := ghs.EditIssue(ctx, args[0], args[1], number, opts.issueOptions()...)
+			if err != nil {
+				return nil, err
+			}
+			return issuesResult([]*notgogithub.Issue{issue}), nil
+		},
+	},
+	{
+		name: "issue close", args: "[-reason completed|not_planned] <owner> <repo> <number>", nargs: 3,
+		flags: func(fs *flag.FlagSet, opts *commandOptions) {
+			fs.StringVar(&opts.reason, "reason", "", "причина закрытия: completed или not_planned (по умолчанию completed)")
+		},
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, opts *commandOptions) (*result, error) {
+			number, err := parseIssueNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			issue, err := ghs.CloseIssue(ctx, args[0], args[1], number, notgogithub.IssueStateReason(opts.reason))
+			if err != nil {
+				return nil, err
+			}
+			return issuesResult([]*notgogithub.Issue{issue}), nil
+		},
+	},
+	issueCommand("issue reopen", notgogithub.GitServiceIFace.ReopenIssue),
+	{
+		name: "issue lock", args: "[-reason off-topic|'too heated'|resolved|spam] <owner> <repo> <number>", nargs: 3,
+		flags: func(fs *flag.FlagSet, opts *commandOptions) {
+			fs.StringVar(&opts.reason, "reason", "", "причина блокировки: off-topic, too heated, resolved или spam")
+		},
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, opts *commandOptions) (*result, error) {
+			number, err := parseIssueNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			return nil, ghs.LockIssue(ctx, args[0], args[1], number, notgogithub.LockReason(opts.reason))
+		},
+	},
+	{
+		name: "issue unlock", args: "<owner> <repo> <number>", nargs: 3,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			number, err := parseIssueNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			return nil, ghs.UnlockIssue(ctx, args[0], args[1], number)
+		},
+	},
+	{
+		name: "issue comments", args: "<owner> <repo> <number>", nargs: 3,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			number, err := parseIssueNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			comments, err := ghs.GetIssueComments(ctx, args[0], args[1], number)
+			if err != nil {
+				return nil, err
+			}
+			return issueCommentsResult(comments), nil
+		},
+	},
+	{
+		name: "issue comment", args: "<owner> <repo> <number> <body>", nargs: 4,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			number, err := parseIssueNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			c, err := ghs.CreateIssueComment(ctx, args[0], args[1], number, args[3])
+			if err != nil {
+				return nil, err
+			}
+			return issueCommentsResult([]*notgogithub.Comment{c}), nil
+		},
+	},
+	{
+		name: "issue edit-comment", args: "<owner> <repo> <number> <comment id> <body>", nargs: 5,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			number, err := parseIssueNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			id, err := parseID("comment id", args[3])
+			if err != nil {
+				return nil, err
+			}
+			c, err := ghs.EditIssueComment(ctx, args[0], args[1], number, id, args[4])
+			if err != nil {
+				return nil, err
+			}
+			return issueCommentsResult([]*notgogithub.Comment{c}), nil
+		},
+	},
+	{
+		name: "issue delete-comment", args: "<owner> <repo> <number> <comment id>", nargs: 4,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			number, err := parseIssueNumber(args[2])
+			if err != nil {
+				return nil, err
+			}
+			id, err := parseID("comment id", args[3])
+			if err != nil {
+				return nil, err
+			}
+			return nil, ghs.DeleteIssueComment(ctx, args[0], args[1], number, id)
+		},
+	},
+	{
+		name: "contributors", args: "<owner> <repo>", nargs: 2,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			users, err := ghs.GetRepositoryContributors(ctx, args[0], args[1])
+			if err != nil {
+				return nil, err
+			}
+			return usersResult(users), nil
+		},
+	},
+	{
+		name: "tag list", args: "<owner> <repo>", nargs: 2,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			tags, err := ghs.GetRepositoryTags(ctx, args[0], args[1])
+			if err != nil {
+				return nil, err
+			}
+			return tagsResult(tags), nil
+		},
+	},
+	{
+		name: "tag create", args: "<owner> <repo> <tag> <sha>", nargs: 4,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			return nil, ghs.CreateTag(ctx, args[0], args[1], args[2], args[3])
+		},
+	},
+	{
+		name: "tag delete", args: "<owner> <repo> <tag>", nargs: 3,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			return nil, ghs.DeleteTag(ctx, args[0], args[1], args[2])
+		},
+	},
+	{
+		name: "release list", args: "<owner> <repo>", nargs: 2,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			releases, err := ghs.GetRepositoryReleases(ctx, args[0], args[1])
+			if err != nil {
+				return nil, err
+			}
+			return releasesResult(releases), nil
+		},
+	},
+	{
+		name: "release get", args: "<owner> <repo> <tag>", nargs: 3,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			release, err := ghs.GetReleaseByTag(ctx, args[0], args[1], args[2])
+			if err != nil {
+				return nil, err
+			}
+			return releasesResult([]*notgogithub.Release{release}), nil
+		},
+	},
+	{
+		name: "release create", args: "[-name n] [-notes text] [-target commitish] [-draft] [-prerelease] [-latest] <owner> <repo> <tag>", nargs: 3,
+		flags: releaseFlags,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, opts *commandOptions) (*result, error) {
+			release, err := ghs.CreateRelease(ctx, args[0], args[1], args[2], opts.releaseOptions()...)
+			if err != nil {
+				return nil, err
+			}
+			return releasesResult([]*notgogithub.Release{release}), nil
+		},
+	},
+	{
+		name: "release publish", args: "<owner> <repo> <id>", nargs: 3,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			id, err := parseReleaseID(args[2])
+			if err != nil {
+				return nil, err
+			}
+			release, err := ghs.PublishRelease(ctx, args[0], args[1], id)
+			if err != nil {
+				return nil, err
+			}
+			return releasesResult([]*notgogithub.Release{release}), nil
+		},
+	},
+	{
+		name: "release delete", args: "<owner> <repo> <id>", nargs: 3,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			id, err := parseReleaseID(args[2])
+			if err != nil {
+				return nil, err
+			}
+			return nil, ghs.DeleteRelease(ctx, args[0], args[1], id)
+		},
+	},
+	{
+		name: "release upload", args: "[-content-type t] <owner> <repo> <id> <file>", nargs: 4,
+		flags: func(fs *flag.FlagSet, opts *commandOptions) {
+			fs.StringVar(&opts.contentType, "content-type", "", "MIME-тип файла (по умолчанию - по расширению)")
+		},
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, opts *commandOptions) (*result, error) {
+			id, err := parseReleaseID(args[2])
+			if err != nil {
+				return nil, err
+			}
+			contentType := opts.contentType
+			if contentType == "" {
+				contentType = mime.TypeByExtension(filepath.Ext(args[3]))
+			}
+			if contentType == "" {
+				contentType = "application/octet-stream"
+			}
+
+			file, err := os.Open(args[3])
+			if err != nil {
+				return nil, err
+			}
+			defer file.Close()
+
+			asset, err := ghs.UploadReleaseAsset(ctx, args[0], args[1], id, filepath.Base(args[3]), contentType, file)
+			if err != nil {
+				return nil, err
+			}
+			return assetsResult([]notgogithub.ReleaseAsset{*asset}), nil
+		},
+	},
+	{
+		name: "release download", args: "<owner> <repo> <id> <asset> <file>", nargs: 5,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			id, err := parseReleaseID(args[2])
+			if err != nil {
+				return nil, err
+			}
+			rc, err := ghs.DownloadReleaseAsset(ctx, args[0], args[1], id, args[3])
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+
+			file, err := os.Create(args[4])
+			if err != nil {
+				return nil, err
+			}
+			if _, err := io.Copy(file, rc); err != nil {
+				file.Close()
+				return nil, err
+			}
+			return nil, file.Close()
+		},
+	},
+	{
+		name: "access grant", args: "<owner> <repo> <user>", nargs: 3,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			return nil, ghs.SetAccessToRepository(ctx, args[0], args[1], args[2])
+		},
+	},
+	{
+		name: "access revoke", args: "<owner> <repo> <user>", nargs: 3,
+		exec: func(ctx context.Context, ghs notgogithub.GitServiceIFace, args []string, _ *commandOptions) (*result, error) {
+			return nil, ghs.DenyAccessToRepository(ctx, args[0], args[1], args[2])
+		},
+	},
+}
+
+// findCommand находит команду по первым одному или двум словам args
+// и возвращает ее вместе с оставшимися аргументами
+func findCommand(args []string) (*command, []string) {
+	for _, cmd := range commands {
+		words := strings.Fields(cmd.name)
+		if len(args) < len(words) {
+			continue
+		}
+		if strings.Join(args[:len(words)], " ") == cmd.name {
+			return cmd, args[len(words):]
+		}
+	}
+	return nil, nil
+}
+
+// parse разбирает флаги и позиционные аргументы команды
+func (cmd *command) parse(args []string, stderr io.Writer) ([]string, *commandOptions, error) {
+	var opts commandOptions
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Использование: not-go-github %s %s\n", cmd.name, cmd.args)
+		fs.PrintDefaults()
+	}
+	if cmd.flags != nil {
+		cmd.flags(fs, &opts)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, errUsage
+	}
+
+	n := fs.NArg()
+	if (cmd.nargs >= 0 && n != cmd.nargs) || (cmd.nargs < 0 && n > 1) {
+		fs.Usage()
+		return nil, nil, errUsage
+	}
+	return fs.Args(), &opts, nil
+}
@@ -0,0 +1,113 @@
+// Команда not-go-github выполняет операции GitServiceIFace из командной строки.
+//
+// Использование:
+//
+//	not-go-github [глобальные флаги] <команда> [флаги команды] [аргументы]
+//
+// Токен берется из флага -token, переменной окружения GITHUB_TOKEN или файла .env (флаг -env-file),
+// в этом порядке. Результат выводится таблицей, в JSON или YAML (флаг -format).
+// Список команд выводит not-go-github help
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	notgogithub "github.com/jellybebra/not-go-github"
+)
+
+// errUsage означает неверные аргументы команды; справка к этому моменту уже выведена
+var errUsage = errors.New("usage")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run выполняет команду args и возвращает код завершения:
+// 0 - успех, 1 - ошибка выполнения, 2 - неверные аргументы
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("not-go-github", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	token := fs.String("token", "", "токен доступа GitHub (по умолчанию из "+notgogithub.DefaultTokenEnv+" или файла -env-file)")
+	envFile := fs.String("env-file", ".env", "файл с переменной "+notgogithub.DefaultTokenEnv)
+	format := fs.String("format", formatTable, "формат вывода: table, json или yaml")
+	apiURL := fs.String("api-url", "", "адрес API GitHub Enterprise Server, например https://github.example.com/")
+	fs.Usage = func() { usage(fs) }
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if !validFormat(*format) {
+		fmt.Fprintf(stderr, "not-go-github: unknown format %q\n", *format)
+		return 2
+	}
+
+	if fs.NArg() == 1 && fs.Arg(0) == "help" {
+		fs.SetOutput(stdout)
+		usage(fs)
+		return 0
+	}
+	cmd, cmdArgs := findCommand(fs.Args())
+	if cmd == nil {
+		usage(fs)
+		return 2
+	}
+	cmdArgs, cmdOpts, err := cmd.parse(cmdArgs, stderr)
+	if err != nil {
+		return 2
+	}
+
+	opts := []notgogithub.Option{notgogithub.WithAuth(authenticator(*token, *envFile))}
+	if *apiURL != "" {
+		opts = append(opts, notgogithub.WithEnterpriseURLs(*apiURL, ""))
+	}
+	ghs, err := notgogithub.NewGitHubService(ctx, opts...)
+	if err != nil {
+		fmt.Fprintf(stderr, "not-go-github: %v\n", err)
+		return 1
+	}
+
+	res, err := cmd.exec(ctx, ghs, cmdArgs, cmdOpts)
+	if err != nil {
+		fmt.Fprintf(stderr, "not-go-github: %s: %v\n", cmd.name, err)
+		return 1
+	}
+
+	if err := write(stdout, *format, res); err != nil {
+		fmt.Fprintf(stderr, "not-go-github: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// authenticator выбирает источник токена: флаг -token, переменная окружения, затем .env-файл
+func authenticator(token, envFile string) notgogithub.Authenticator {
+	if token != "" {
+		return notgogithub.StaticToken(token)
+	}
+	if os.Getenv(notgogithub.DefaultTokenEnv) != "" {
+		return notgogithub.EnvToken(notgogithub.DefaultTokenEnv)
+	}
+	return notgogithub.DotEnvToken(envFile, notgogithub.DefaultTokenEnv)
+}
+
+func usage(fs *flag.FlagSet) {
+	out := fs.Output()
+	fmt.Fprintln(out, "Использование: not-go-github [глобальные флаги] <команда> [флаги команды] [аргументы]")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Команды:")
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %-16s %s\n", cmd.name, cmd.args)
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Глобальные флаги:")
+	fs.PrintDefaults()
+}
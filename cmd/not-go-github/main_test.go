@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jellybebra/not-go-github/ghsim"
+)
+
+// runSim выполняет команду против симулятора GitHub API с данными из testdata/fixtures.json
+func runSim(t *testing.T, args ...string) (code int, stdout, stderr string, sim *ghsim.Server) {
+	t.Helper()
+
+	f, err := ghsim.LoadFixtures("../../testdata/fixtures.json")
+	if err != nil {
+		t.Fatalf("LoadFixtures: %v", err)
+	}
+	sim = ghsim.New(f)
+	t.Cleanup(sim.Close)
+
+	var out, errOut bytes.Buffer
+	args = append([]string{"-token", "test", "-api-url", sim.URL}, args...)
+	code = run(context.Background(), args, &out, &errOut)
+	return code, out.String(), errOut.String(), sim
+}
+
+func TestRunTable(t *testing.T) {
+	testTable := []struct {
+		args     []string
+		expected []string // Строки, которые должны быть в выводе
+	}{
+		{
+			args:     []string{"user", "info", "jostanise"},
+			expected: []string{"LOGIN", "jostanise", "Mikhail Chestneyshy"},
+		},
+		{
+			args:     []string{"repo", "get", "jostanise", "rsa_encrypted_local_chat"},
+			expected: []string{"rsa_encrypted_local_chat", "Python (99.0%)"},
+		},
+		{
+			args:     []string{"branch", "list", "PeakIntegral", "cppLessons"},
+			expected: []string{"create-sec-hero", "2022-03-05T22:52:20Z", "yura"},
+		},
+		{
+			args:     []string{"pr", "list", "-state", "merged", "PeakIntegral", "cppLessons"},
+			expected: []string{"NUMBER", "merged"},
+		},
+		{
+			args:     []string{"issues", "-state", "closed", "PeakIntegral", "cppLessons"},
+			expected: []string{"closed"},
+		},
+		{
+			args:     []string{"pr", "threads", "PeakIntegral", "cppLessons", "3"},
+			expected: []string{"RESOLVED", "820011001", "Опечатка в заголовке", "Готово к ревью"},
+		},
+		{
+			args:     []string{"pr", "files", "PeakIntegral", "cppLessons", "3"},
+			expected: []string{"ADDITIONS", "modified", "README.md"},
+		},
+		{
+			args:     []string{"pr", "diff", "PeakIntegral", "cppLessons", "3"},
+			expected: []string{"diff --git a/README.md b/README.md", "+++ b/README.md"},
+		},
+		{
+			args:     []string{"pr", "mergeable", "PeakIntegral", "cppLessons", "3"},
+			expected: []string{"CONFLICTS", "clean", "true"},
+		},
+		{
+			args:     []string{"pr", "reviews", "PeakIntegral", "cppLessons", "3"},
+			expected: []string{"STATE", "903381220", "CHANGES_REQUESTED", "COMMENTED"},
+		},
+		{
+			args:     []string{"pr", "review", "-body", "Отлично", "PeakIntegral", "cppLessons", "5", "approve"},
+			expected: []string{"APPROVED", "jostanise", "Отлично"},
+		},
+		{
+			args:     []string{"pr", "comment", "-start", "2", "-suggest", "return 0;", "PeakIntegral", "cppLessons", "5", "main.cpp", "4", "Проще так"},
+			expected: []string{"LINES", "2-4", "Проще так"},
+		},
+		{
+			args:     []string{"pr", "reply", "PeakIntegral", "cppLessons", "3", "820011001", "Поправил"},
+			expected: []string{"REPLY TO", "820011001", "Поправил"},
+		},
+		{
+			args:     []string{"pr", "create", "-draft", "-reviewers", "PeakIntegral", "-labels", "documentation", "PeakIntegral", "cppLessons", "patch-1", "yura", "Fix typo"},
+			expected: []string{"NUMBER", "8", "draft", "jostanise", "Fix typo"},
+		},
+		{
+			args:     []string{"pr", "merge", "-method", "squash", "PeakIntegral", "cppLessons", "3"},
+			expected: []string{"SHA"},
+		},
+		{
+			args:     []string{"pr", "ready", "PeakIntegral", "cppLessons", "5"},
+			expected: []string{"open", "WIP: third hero"},
+		},
+		{
+			args:     []string{"pr", "base", "PeakIntegral", "cppLessons", "3", "yura"},
+			expected: []string{"patch-1", "yura"},
+		},
+		{
+			args:     []string{"issue", "create", "-body", "Нужен Makefile", "-labels", "build", "-milestone", "1", "PeakIntegral", "cppLessons", "Add Linux build"},
+			expected: []string{"COMMENTS", "8", "open", "jostanise", "Add Linux build"},
+		},
+		{
+			args:     []string{"issue", "close", "-reason", "not_planned", "PeakIntegral", "cppLessons", "4"},
+			expected: []string{"closed", "Heroes do not compile on Windows"},
+		},
+		{
+			args:     []string{"issue", "comments", "PeakIntegral", "cppLessons", "4"},
+			expected: []string{"1060000002", "PeakIntegral", "Воспроизвожу на MSVC 2019"},
+		},
+		{
+			args:     []string{"commits", "-max-depth", "2", "PeakIntegral", "cppLessons", "main"},
+			expected: []string{"HASH", "AUTHOR"},
+		},
+		{
+			args:     []string{"tag", "list", "jostanise", "rsa_encrypted_local_chat"},
+			expected: []string{"v1.0", "0480a292df58ba0bb4851bf828ed25efc56da813"},
+		},
+		{
+			args:     []string{"tag", "list", "PeakIntegral", "cppLessons"},
+			expected: []string{"v0.1", "PeakIntegral", "2022-03-12T10:00:00Z", "Первая версия уроков"},
+		},
+		{
+			args:     []string{"release", "get", "jostanise", "rsa_encrypted_local_chat", "v1.0"},
+			expected: []string{"TAG", "v1.0", "2021-10-12T15:20:05Z"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		// Act
+		code, stdout, stderr, _ := runSim(t, testCase.args...)
+
+		// Assert
+		if code != 0 {
+			t.Errorf("Incorrect exit code for %v: %v, stderr: %s", testCase.args, code, stderr)
+			continue
+		}
+		for _, exp := range testCase.expected {
+			if !strings.Contains(stdout, exp) {
+				t.Errorf("Incorrect output for %v: expected %q in\n%s", testCase.args, exp, stdout)
+			}
+		}
+	}
+}
+
+func TestRunFormats(t *testing.T) {
+	// JSON
+	code, stdout, stderr, _ := runSim(t, "-format", "json", "contributors", "jostanise", "rsa_encrypted_local_chat")
+	if code != 0 {
+		t.Fatalf("json: exit code %v, stderr: %s", code, stderr)
+	}
+	var users []struct{ UserName string }
+	if err := json.Unmarshal([]byte(stdout), &users); err != nil || len(users) != 2 || users[0].UserName != "jostanise" {
+		t.Errorf("Incorrect JSON output: %v, %s", err, stdout)
+	}
+
+	// YAML
+	code, stdout, stderr, _ = runSim(t, "-format", "yaml", "branch", "list", "jostanise", "rsa_encrypted_local_chat")
+	if code != 0 {
+		t.Fatalf("yaml: exit code %v, stderr: %s", code, stderr)
+	}
+	var branches []map[string]interface{}
+	if err := yaml.Unmarshal([]byte(stdout), &branches); err != nil || len(branches) != 1 || branches[0]["name"] != "main" {
+		t.Errorf("Incorrect YAML output: %v, %s", err, stdout)
+	}
+}
+
+func TestRunMutations(t *testing.T) {
+	// Arrange
+	code, stdout, stderr, sim := runSim(t, "branch", "create", "jostanise", "rsa_encrypted_local_chat", "feature", "0480a292df58ba0bb4851bf828ed25efc56da813")
+
+	// Assert
+	if code != 0 || stdout != "" {
+		t.Errorf("branch create: exit code %v, stdout %q, stderr %s", code, stdout, stderr)
+	}
+	found := false
+	for _, r := range sim.Requests() {
+		if strings.HasPrefix(r, "POST /repos/jostanise/rsa_encrypted_local_chat/git/refs") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected POST git/refs, got %v", sim.Requests())
+	}
+}
+
+func TestRunReleaseAssets(t *testing.T) {
+	f, err := ghsim.LoadFixtures("../../testdata/fixtures.json")
+	if err != nil {
+		t.Fatalf("LoadFixtures: %v", err)
+	}
+	sim := ghsim.New(f)
+	t.Cleanup(sim.Close)
+	// Все команды теста работают с одним симулятором, чтобы видеть изменения друг друга
+	runOnSim := func(args ...string) string {
+		t.Helper()
+		var out, errOut bytes.Buffer
+		args = append([]string{"-token", "test", "-api-url", sim.URL, "-format", "json"}, args...)
+		if code := run(context.Background(), args, &out, &errOut); code != 0 {
+			t.Fatalf("%v: exit code %v, stderr: %s", args, code, errOut.String())
+		}
+		return out.String()
+	}
+
+	// Arrange
+	dir := t.TempDir()
+	src := filepath.Join(dir, "lessons.txt")
+	if err := os.WriteFile(src, []byte("урок 1"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var created []struct{ ID int64 }
+	if err := json.Unmarshal([]byte(runOnSim("release", "create", "-draft", "-name", "Уроки", "PeakIntegral", "cppLessons", "v0.2")), &created); err != nil || len(created) != 1 {
+		t.Fatalf("Incorrect release create output: %v, %v", created, err)
+	}
+	id := strconv.FormatInt(created[0].ID, 10)
+
+	// Act
+	uploaded := runOnSim("release", "upload", "-content-type", "text/plain", "PeakIntegral", "cppLessons", id, src)
+	published := runOnSim("release", "publish", "PeakIntegral", "cppLessons", id)
+	dst := filepath.Join(dir, "downloaded.txt")
+	runOnSim("release", "download", "PeakIntegral", "cppLessons", id, "lessons.txt", dst)
+
+	// Assert
+	if !strings.Contains(uploaded, `"ContentType": "text/plain"`) {
+		t.Errorf("Incorrect upload output: %s", uploaded)
+	}
+	if !strings.Contains(published, `"Draft": false`) {
+		t.Errorf("Incorrect publish output: %s", published)
+	}
+	if content, err := os.ReadFile(dst); err != nil || string(content) != "урок 1" {
+		t.Errorf("Incorrect downloaded file: %q, %v", content, err)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	testTable := []struct {
+		args []string
+		code int
+	}{
+		{args: []string{"unknown"}, code: 2},
+		{args: []string{"repo", "get", "jostanise"}, code: 2},
+		{args: []string{"-format", "xml", "repo", "list"}, code: 2},
+		{args: []string{"pr", "threads", "jostanise", "rsa_encrypted_local_chat", "x"}, code: 1},
+		{args: []string{"repo", "get", "jostanise", "missing"}, code: 1},
+		{args: []string{"pr", "review", "PeakIntegral", "cppLessons", "5", "reject"}, code: 1},
+		{args: []string{"pr", "edit-comment", "PeakIntegral", "cppLessons", "3", "820011001", "x"}, code: 1},
+		{args: []string{"pr", "merge", "PeakIntegral", "cppLessons", "5"}, code: 1},
+		{args: []string{"pr", "reopen", "PeakIntegral", "cppLessons", "1"}, code: 1},
+		{args: []string{"help"}, code: 0},
+	}
+
+	for _, testCase := range testTable {
+		// Act
+		code, _, stderr, _ := runSim(t, testCase.args...)
+
+		// Assert
+		if code != testCase.code {
+			t.Errorf("Incorrect exit code for %v: expected %v, got %v (stderr: %s)", testCase.args, testCase.code, code, stderr)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	notgogithub "github.com/jellybebra/not-go-github"
+)
+
+// Форматы вывода
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+func validFormat(format string) bool {
+	return format == formatTable || format == formatJSON || format == formatYAML
+}
+
+// result - результат команды. value выводится в JSON и YAML, header и rows - таблицей,
+// а непустой text вместо таблицы выводится как есть.
+// Команды, изменяющие данные, возвращают nil и ничего не выводят
+type result struct {
+	value  interface{}
+	header []string
+	rows   [][]string
+	text   string
+}
+
+// write выводит res в формате format
+func write(w io.Writer, format string, res *result) error {
+	if res == nil {
+		return nil
+	}
+
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res.value)
+	case formatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(res.value); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	if res.text != "" {
+		_, err := io.WriteString(w, res.text)
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(res.header, "\t"))
+	for _, row := range res.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// formatTime выводит время в формате RFC 3339, а нулевое время - пустой строкой
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// firstLine возвращает первую строку сообщения, чтобы многострочный текст не ломал таблицу
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+func usersResult(users []*notgogithub.User) *result {
+	res := &result{value: users, header: []string{"LOGIN", "NAME", "FOLLOWERS", "FOLLOWING"}}
+	for _, u := range users {
+		res.rows = append(res.rows, []string{u.UserName, u.FullName, strconv.Itoa(u.FollowersCount), strconv.Itoa(u.FollowingCount)})
+	}
+	return res
+}
+
+func repositoriesResult(repos []*notgogithub.Repository) *result {
+	res := &result{value: repos, header: []string{"NAME", "PRIVATE", "STARS", "FORKS", "LANGUAGE", "UPDATED", "LINK"}}
+	for _, r := range repos {
+		language := ""
+		if l, ok := r.PrimaryLanguage(); ok {
+			language = fmt.Sprintf("%s (%.1f%%)", l.Name, l.Percentage)
+		}
+		res.rows = append(res.rows, []string{
+			r.Name, strconv.FormatBool(r.IsPrivate), strconv.Itoa(r.StarsCount), strconv.Itoa(r.ForksCount),
+			language, formatTime(r.LastUpdatedTime), r.Link,
+		})
+	}
+	return res
+}
+
+func branchesResult(branches []*notgogithub.Branch) *result {
+	res := &result{value: branches, header: []string{"NAME", "UPDATED"}}
+	for _, b := range branches {
+		res.rows = append(res.rows, []string{b.Name, formatTime(b.UpdatedAt)})
+	}
+	return res
+}
+
+func commitsResult(commits []*notgogithub.Commit) *result {
+	res := &result{value: commits, header: []string{"HASH", "AUTHOR", "CREATED", "TITLE"}}
+	for _, c := range commits {
+		res.rows = append(res.rows, []string{c.Hash, c.Author, formatTime(c.CreatedAt), firstLine(c.Title)})
+	}
+	return res
+}
+
+func pullRequestsResult(prs []*notgogithub.PullRequest) *result {
+	res := &result{value: prs, header: []string{"NUMBER", "STATE", "AUTHOR", "SOURCE", "TARGET", "TITLE"}}
+	for _, pr := range prs {
+		res.rows = append(res.rows, []string{
+			strconv.Itoa(pr.Number), string(pr.State), pr.Author, pr.SourceBranch, pr.TargetBranch, firstLine(pr.Title),
+		})
+	}
+	return res
+}
+
+// mergeResult выводит SHA коммита, созданного слиянием запроса
+func mergeResult(sha string) *result {
+	return &result{value: struct{ SHA string }{sha}, header: []string{"SHA"}, rows: [][]string{{sha}}}
+}
+
+func filesResult(files []*notgogithub.PullRequestFile) *result {
+	res := &result{value: files, header: []string{"STATUS", "FILE", "ADDITIONS", "DELETIONS"}}
+	for _, f := range files {
+		name := f.Filename
+		if f.PreviousFilename != "" {
+			name = f.PreviousFilename + " -> " + f.Filename
+		}
+		res.rows = append(res.rows, []string{f.Status, name, strconv.Itoa(f.Additions), strconv.Itoa(f.Deletions)})
+	}
+	return res
+}
+
+// textResult выводит diff или patch как есть, а в JSON и YAML - полем Text
+func textResult(text string) *result {
+	return &result{value: struct{ Text string }{text}, text: text}
+}
+
+func mergeabilityResult(m *notgogithub.Mergeability) *result {
+	return &result{
+		value:  m,
+		header: []string{"STATE", "MERGEABLE", "REBASEABLE", "CONFLICTS", "HEAD"},
+		rows: [][]string{{
+			string(m.State), strconv.FormatBool(m.Mergeable), strconv.FormatBool(m.Rebaseable), strconv.FormatBool(m.Conflicts), m.HeadSHA,
+		}},
+	}
+}
+
+func threadsResult(threads []*notgogithub.Thread) *result {
+	res := &result{value: threads, header: []string{"ID", "FILE", "LINE", "RESOLVED", "OUTDATED", "COMMENTS", "AUTHOR", "FIRST COMMENT"}}
+	for _, t := range threads {
+		author, first := "", ""
+		if len(t.Conversation) > 0 {
+			author, first = t.Conversation[0].Author, firstLine(t.Conversation[0].Body)
+		}
+		res.rows = append(res.rows, []string{
+			strconv.FormatInt(t.ID, 10), t.Filename, strconv.FormatUint(t.LineOfCode, 10), strconv.FormatBool(t.Resolved),
+			strconv.FormatBool(t.Outdated), strconv.Itoa(len(t.Conversation)), author, first,
+		})
+	}
+	return res
+}
+
+func reviewsResult(reviews []*notgogithub.Review) *result {
+	res := &result{value: reviews, header: []string{"ID", "AUTHOR", "STATE", "SUBMITTED", "BODY"}}
+	for _, r := range reviews {
+		res.rows = append(res.rows, []string{strconv.FormatInt(r.ID, 10), r.Author, r.State, formatTime(r.SubmittedAt), firstLine(r.Body)})
+	}
+	return res
+}
+
+func commentsResult(comments []notgogithub.Comment) *result {
+	res := &result{value: comments, header: []string{"ID", "REVIEW", "REPLY TO", "LINES", "AUTHOR", "BODY"}}
+	for _, c := range comments {
+		lines := ""
+		if c.EndLine != 0 {
+			lines = fmt.Sprintf("%d-%d", c.StartLine, c.EndLine)
+		}
+		res.rows = append(res.rows, []string{
+			strconv.FormatInt(c.ID, 10), strconv.FormatInt(c.ReviewID, 10), strconv.FormatInt(c.InReplyTo, 10), lines, c.Author, firstLine(c.Body),
+		})
+	}
+	return res
+}
+
+func issuesResult(issues []*notgogithub.Issue) *result {
+	res := &result{value: issues, header: []string{"NUMBER", "STATE", "AUTHOR", "CREATED", "COMMENTS", "TITLE"}}
+	for _, i := range issues {
+		res.rows = append(res.rows, []string{
+			strconv.Itoa(i.Number), string(i.State), i.Author, formatTime(i.CreatedAt), strconv.Itoa(i.Comments), firstLine(i.Title),
+		})
+	}
+	return res
+}
+
+func issueCommentsResult(comments []*notgogithub.Comment) *result {
+	res := &result{value: comments, header: []string{"ID", "AUTHOR", "CREATED", "BODY"}}
+	for _, c := range comments {
+		res.rows = append(res.rows, []string{strconv.FormatInt(c.ID, 10), c.Author, formatTime(c.CreatedAt), firstLine(c.Body)})
+	}
+	return res
+}
+
+func tagsResult(tags []*notgogithub.Tag) *result {
+	res := &result{value: tags, header: []string{"TITLE", "HASH", "TAGGER", "TAGGED", "RELEASE", "MESSAGE"}}
+	for _, t := range tags {
+		release := ""
+		if t.Release != nil {
+			release = t.Release.Name
+		}
+		res.rows = append(res.rows, []string{t.Title, t.Hash, t.Tagger, formatTime(t.TaggedAt), release, firstLine(t.Message)})
+	}
+	return res
+}
+
+func releasesResult(releases []*notgogithub.Release) *result {
+	res := &result{value: releases, header: []string{"ID", "TAG", "NAME", "DRAFT", "PRERELEASE", "AUTHOR", "PUBLISHED", "ASSETS"}}
+	for _, r := range releases {
+		res.rows = append(res.rows, []string{
+			strconv.FormatInt(r.ID, 10), r.TagName, r.Name, strconv.FormatBool(r.Draft), strconv.FormatBool(r.Prerelease), r.Author, formatTime(r.PublishedAt),
+			strconv.Itoa(len(r.Assets)),
+		})
+	}
+	return res
+}
+
+func assetsResult(assets []notgogithub.ReleaseAsset) *result {
+	res := &result{value: assets, header: []string{"ID", "NAME", "CONTENT TYPE", "SIZE", "DOWNLOADS", "LINK"}}
+	for _, a := range assets {
+		res.rows = append(res.rows, []string{
+			strconv.FormatInt(a.ID, 10), a.Name, a.ContentType, strconv.Itoa(a.Size), strconv.Itoa(a.DownloadCount), a.DownloadLink,
+		})
+	}
+	return res
+}
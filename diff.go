@@ -0,0 +1,166 @@
+package notgogithub
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// Статусы файла в PullRequestFile.Status
+const (
+	FileAdded     = "added"
+	FileRemoved   = "removed"
+	FileModified  = "modified"
+	FileRenamed   = "renamed"
+	FileCopied    = "copied"
+	FileChanged   = "changed" // Изменены только права доступа
+	FileUnchanged = "unchanged"
+)
+
+// PullRequestFile хранит изменения одного файла в запросе на слияние
+type PullRequestFile struct {
+	Filename         string // Путь к файлу
+	PreviousFilename string // Прежний путь переименованного файла
+	Status           string // Статус: FileAdded, FileModified, FileRenamed и т.д.
+	Additions        int    // Добавлено строк
+	Deletions        int    // Удалено строк
+	Changes          int    // Всего измененных строк
+	Patch            string // Изменения в формате unified diff без заголовка; пустой у двоичных и слишком больших файлов
+	SHA              string // SHA blob-объекта файла в ветке-источнике
+}
+
+// MergeableState - состояние запроса на слияние с точки зрения возможности слияния
+type MergeableState string
+
+const (
+	MergeableClean    MergeableState = "clean"     // Можно слить
+	MergeableDirty    MergeableState = "dirty"     // Есть конфликты с веткой-назначением
+	MergeableBehind   MergeableState = "behind"    // Ветка-источник отстает от ветки-назначения, а правила требуют актуальности
+	MergeableBlocked  MergeableState = "blocked"   // Слиянию мешают правила защиты ветки
+	MergeableUnstable MergeableState = "unstable"  // Можно слить, но проверки не пройдены
+	MergeableHasHooks MergeableState = "has_hooks" // Можно слить, проверки пройдены, но есть pre-receive хуки
+	MergeableDraft    MergeableState = "draft"     // Черновик
+	MergeableUnknown  MergeableState = "unknown"   // GitHub еще не вычислил состояние или запрос закрыт
+)
+
+// Mergeability хранит сведения о том, можно ли слить запрос на слияние
+type Mergeability struct {
+	Mergeable  bool           // Слияние возможно без конфликтов
+	Rebaseable bool           // Возможно слияние способом MergeMethodRebase
+	Conflicts  bool           // Есть конфликты с веткой-назначением
+	State      MergeableState // Подробное состояние
+	HeadSHA    string         // SHA последнего коммита ветки-источника, для которого вычислено состояние
+}
+
+// GitHub вычисляет возможность слияния в фоне: пока ответа нет, поле mergeable равно null
+const (
+	mergeabilityAttempts = 5
+	mergeabilityDelay    = 500 * time.Millisecond
+)
+
+// newPullRequestFile преобразует измененный файл GitHub в PullRequestFile
+func newPullRequestFile(f *github.CommitFile) *PullRequestFile {
+	return &PullRequestFile{
+		Filename:         f.GetFilename(),
+		PreviousFilename: f.GetPreviousFilename(),
+		Status:           f.GetStatus(),
+		Additions:        f.GetAdditions(),
+		Deletions:        f.GetDeletions(),
+		Changes:          f.GetChanges(),
+		Patch:            f.GetPatch(),
+		SHA:              f.GetSHA(),
+	}
+}
+
+func (ghs *gitHubService) GetPullRequestFiles(ctx context.Context, owner, repositoryName string, pullRequestID int) ([]*PullRequestFile, error) {
+	files, err := collectPages(ctx, 0, func(lo github.ListOptions) ([]*github.CommitFile, *github.Response, error) {
+		return callAPI(ctx, ghs, "list pull request files", func() ([]*github.CommitFile, *github.Response, error) {
+			return ghs.client.PullRequests.ListFiles(ctx, owner, repositoryName, pullRequestID, &lo)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var Files []*PullRequestFile
+	for _, f := range nonNil(files) {
+		Files = append(Files, newPullRequestFile(f))
+	}
+	return Files, nil
+}
+
+func (ghs *gitHubService) GetPullRequestDiff(ctx context.Context, owner, repositoryName string, pullRequestID int) (string, error) {
+	return ghs.pullRequestRaw(ctx, owner, repositoryName, pullRequestID, "get pull request diff", github.Diff)
+}
+
+func (ghs *gitHubService) GetPullRequestPatch(ctx context.Context, owner, repositoryName string, pullRequestID int) (string, error) {
+	return ghs.pullRequestRaw(ctx, owner, repositoryName, pullRequestID, "get pull request patch", github.Patch)
+}
+
+// pullRequestRaw загружает изменения запроса на слияние текстом в формате rawType
+func (ghs *gitHubService) pullRequestRaw(ctx context.Context, owner, repositoryName string, pullRequestID int, op string, rawType github.RawType) (string, error) {
+	var raw string
+	err := ghs.do(ctx, op, func() (*github.Response, error) {
+		var (
+			resp *github.Response
+			err  error
+		)
+		raw, resp, err = ghs.client.PullRequests.GetRaw(ctx, owner, repositoryName, pullRequestID, github.RawOptions{Type: rawType})
+		return resp, err
+	})
+	return raw, err
+}
+
+func (ghs *gitHubService) GetPullRequestCommits(ctx context.Context, owner, repositoryName string, pullRequestID int) ([]*Commit, error) {
+	commits, err := collectPages(ctx, 0, func(lo github.ListOptions) ([]*github.RepositoryCommit, *github.Response, error) {
+		return callAPI(ctx, ghs, "list pull request commits", func() ([]*github.RepositoryCommit, *github.Response, error) {
+			return ghs.client.PullRequests.ListCommits(ctx, owner, repositoryName, pullRequestID, &lo)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertRepositoryCommits(commits), nil
+}
+
+func (ghs *gitHubService) GetPullRequestMergeability(ctx context.Context, owner, repositoryName string, pullRequestID int) (*Mergeability, error) {
+	var pr *github.PullRequest
+	for attempt := 0; attempt < mergeabilityAttempts; attempt++ {
+		if attempt > 0 {
+			if err := sleepContext(ctx, mergeabilityDelay); err != nil {
+				return nil, err
+			}
+		}
+
+		var err error
+		pr, _, err = callAPI(ctx, ghs, "get pull request", func() (*github.PullRequest, *github.Response, error) {
+			return ghs.client.PullRequests.Get(ctx, owner, repositoryName, pullRequestID)
+		})
+		if err != nil {
+			return nil, err
+		}
+		// У закрытого запроса состояние не вычисляется
+		if pr.Mergeable != nil || pr.GetState() != "open" {
+			break
+		}
+	}
+
+	return newMergeability(pr), nil
+}
+
+// newMergeability извлекает из запроса на слияние GitHub сведения о возможности слияния
+func newMergeability(pr *github.PullRequest) *Mergeability {
+	m := &Mergeability{
+		Mergeable:  pr.GetMergeable(),
+		Rebaseable: pr.GetRebaseable(),
+		State:      MergeableState(pr.GetMergeableState()),
+		HeadSHA:    pr.GetHead().GetSHA(),
+	}
+	if pr.Mergeable == nil || m.State == "" {
+		m.State = MergeableUnknown
+	}
+	m.Conflicts = m.State == MergeableDirty
+	return m
+}
@@ -0,0 +1,21 @@
+// Package notgogithub - библиотека для управления GitHub на Go поверх клиента GitHub API v3
+// (github.com/google/go-github).
+//
+// Все методы описаны интерфейсом GitServiceIFace. Рабочая реализация создается конструктором
+// NewGitHubService и настраивается опциями Option (WithAuth, WithEnterpriseURLs, WithMaxItems,
+// WithRateLimitPolicy, WithConcurrency, WithTransport). Отдельные вызовы списочных методов
+// настраиваются опциями ListOption (WithState, WithSince, WithEnrichment и т.д.).
+// Для тестов без сети предназначен FakeGitService, а для проверки работы с HTTP -
+// симулятор GitHub API из пакета ghsim.
+//
+//	ghs, err := notgogithub.NewGitHubService(ctx, notgogithub.WithAuth(notgogithub.EnvToken("GITHUB_TOKEN")))
+//	if err != nil {
+//		return err
+//	}
+//	repo, err := ghs.GetRepositoryByName(ctx, "jostanise", "rsa_encrypted_local_chat")
+//
+// Модуль еще не выпущен в версии v1, поэтому стабильность API не гарантируется: названия
+// и сигнатуры могут меняться. В GitServiceIFace регулярно добавляются методы, и каждый новый метод
+// ломает сторонние реализации интерфейса. Собственную реализацию стоит строить встраиванием
+// *FakeGitService, переопределяя только нужные методы
+package notgogithub
@@ -0,0 +1,38 @@
+package notgogithub
+
+// Enrichment - набор дополнительных данных, для загрузки которых нужны отдельные запросы к API.
+// Поле Enriched возвращаемых структур показывает, какие из них загружены
+type Enrichment uint
+
+const (
+	EnrichLanguages    Enrichment = 1 << iota // Языки программирования репозитория
+	EnrichProfiles                            // Полный профиль пользователя: имя, подписчики и подписки
+	EnrichReleaseNotes                        // Релиз тега
+	EnrichCommitDates                         // Дата последнего коммита ветки
+	EnrichAnnotations                         // Автор, сообщение и дата аннотированного тега
+
+	EnrichNone Enrichment = 0 // Только данные из ответа со списком
+
+	// EnrichAll - все дополнительные данные, набор по умолчанию
+	EnrichAll = EnrichLanguages | EnrichProfiles | EnrichReleaseNotes | EnrichCommitDates | EnrichAnnotations
+)
+
+// Has сообщает, входят ли в набор все данные из other
+func (e Enrichment) Has(other Enrichment) bool {
+	return e&other == other
+}
+
+// WithEnrichment загружает только дополнительные данные из набора e, например
+// WithEnrichment(EnrichNone) для одних имен соавторов без их профилей.
+// По умолчанию загружаются все дополнительные данные (EnrichAll)
+func WithEnrichment(e Enrichment) ListOption {
+	return func(lo *listOptions) {
+		lo.skip = EnrichAll &^ e
+	}
+}
+
+// enrichment возвращает набор дополнительных данных, которые нужно загрузить при вызове с opts
+func enrichment(opts []ListOption) Enrichment {
+	lo := newListOptions(opts)
+	return EnrichAll &^ lo.skip
+}
@@ -0,0 +1,123 @@
+package notgogithub
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// Виды ошибок GitHub API. Ошибки методов GitServiceIFace сравниваются с ними через errors.Is:
+//
+//	if errors.Is(err, ErrNotFound) { ... }
+var (
+	ErrNotFound     = errors.New("not found")         // Объект не существует или недоступен (404)
+	ErrUnauthorized = errors.New("unauthorized")      // Токен не передан, недействителен или требует 2FA (401)
+	ErrForbidden    = errors.New("forbidden")         // Недостаточно прав (403)
+	ErrRateLimited  = errors.New("rate limited")      // Исчерпан основной или вторичный лимит запросов
+	ErrConflict     = errors.New("conflict")          // Объект уже существует или конфликтует с текущим состоянием (409, 405 при слиянии)
+	ErrValidation   = errors.New("validation failed") // GitHub отклонил параметры запроса (422)
+)
+
+// Error - ошибка запроса к GitHub API. Исходная ошибка go-github доступна через errors.As
+// (например, *github.ErrorResponse), а вид ошибки - через errors.Is (ErrNotFound и т.д.)
+type Error struct {
+	Op         string // Операция, во время которой произошла ошибка, например "get repo"
+	StatusCode int    // HTTP-статус ответа (0, если ответа не было)
+	RequestID  string // Идентификатор запроса из заголовка X-GitHub-Request-Id для обращения в поддержку GitHub
+	Message    string // Сообщение об ошибке из ответа GitHub
+	Err        error  // Исходная ошибка
+
+	kind error // Один из Err* или nil, если вид ошибки не определен
+}
+
+func (e *Error) Error() string {
+	return e.Op + ": " + e.Err.Error()
+}
+
+// Unwrap возвращает исходную ошибку
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is сообщает, относится ли ошибка к виду target (ErrNotFound, ErrForbidden и т.д.)
+func (e *Error) Is(target error) bool {
+	return e.kind != nil && target == e.kind
+}
+
+// newError оборачивает ошибку запроса op в *Error и определяет ее вид.
+// Ошибки отмены контекста возвращаются как есть
+func newError(op string, resp *github.Response, err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	e := &Error{Op: op, Err: err}
+	if resp != nil && resp.Response != nil {
+		e.StatusCode = resp.StatusCode
+		e.RequestID = resp.Header.Get("X-GitHub-Request-Id")
+	}
+
+	var (
+		rateErr  *github.RateLimitError
+		abuseErr *github.AbuseRateLimitError
+		twoFA    *github.TwoFactorAuthError
+		respErr  *github.ErrorResponse
+		gqlErr   *graphQLError
+	)
+	switch {
+	case errors.As(err, &rateErr):
+		e.kind, e.Message = ErrRateLimited, rateErr.Message
+	case errors.As(err, &abuseErr):
+		e.kind, e.Message = ErrRateLimited, abuseErr.Message
+	case errors.As(err, &twoFA):
+		e.kind, e.Message = ErrUnauthorized, twoFA.Message
+	case errors.As(err, &respErr):
+		e.Message = respErr.Message
+		e.kind = errorKind(respErr)
+	case errors.As(err, &gqlErr):
+		e.Message, e.kind = gqlErr.Message, gqlErr.kind()
+	}
+	return e
+}
+
+// errorKind определяет вид ошибки по HTTP-статусу ответа
+func errorKind(respErr *github.ErrorResponse) error {
+	if respErr.Response == nil {
+		return nil
+	}
+
+	switch respErr.Response.StatusCode {
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusMethodNotAllowed:
+		// Так GitHub отвечает на слияние запроса, который нельзя слить
+		return ErrConflict
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusUnprocessableEntity:
+		// Повторное создание ветки, тега или репозитория GitHub отклоняет как ошибку валидации
+		if alreadyExists(respErr) {
+			return ErrConflict
+		}
+		return ErrValidation
+	}
+	return nil
+}
+
+func alreadyExists(respErr *github.ErrorResponse) bool {
+	for _, e := range respErr.Errors {
+		if e.Code == "already_exists" {
+			return true
+		}
+	}
+	return strings.Contains(strings.ToLower(respErr.Message), "already exists")
+}
@@ -0,0 +1,169 @@
+package notgogithub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+)
+
+func TestNewError(t *testing.T) {
+	// errorResponse возвращает ответ GitHub со статусом status и ошибку go-github для него
+	errorResponse := func(status int, message string, errs ...github.Error) (*github.Response, error) {
+		resp := &http.Response{StatusCode: status}
+		return &github.Response{Response: resp}, &github.ErrorResponse{Response: resp, Message: message, Errors: errs}
+	}
+
+	testTable := []struct {
+		name     string
+		call     func() (*github.Response, error)
+		expected error
+		status   int
+	}{
+		{
+			name:     "not found",
+			call:     func() (*github.Response, error) { return errorResponse(http.StatusNotFound, "Not Found") },
+			expected: ErrNotFound,
+			status:   http.StatusNotFound,
+		},
+		{
+			name:     "unauthorized",
+			call:     func() (*github.Response, error) { return errorResponse(http.StatusUnauthorized, "Bad credentials") },
+			expected: ErrUnauthorized,
+			status:   http.StatusUnauthorized,
+		},
+		{
+			name:     "forbidden",
+			call:     func() (*github.Response, error) { return errorResponse(http.StatusForbidden, "Must have admin rights") },
+			expected: ErrForbidden,
+			status:   http.StatusForbidden,
+		},
+		{
+			name:     "rate limited",
+			call:     func() (*github.Response, error) { return rateLimited(time.Now().Add(time.Hour)) },
+			expected: ErrRateLimited,
+			status:   http.StatusForbidden,
+		},
+		{
+			name: "conflict",
+			call: func() (*github.Response, error) {
+				return errorResponse(http.StatusConflict, "Git Repository is empty.")
+			},
+			expected: ErrConflict,
+			status:   http.StatusConflict,
+		},
+		{
+			name: "already exists",
+			call: func() (*github.Response, error) {
+				return errorResponse(http.StatusUnprocessableEntity, "Reference already exists")
+			},
+			expected: ErrConflict,
+			status:   http.StatusUnprocessableEntity,
+		},
+		{
+			name: "validation",
+			call: func() (*github.Response, error) {
+				return errorResponse(http.StatusUnprocessableEntity, "Validation Failed", github.Error{Resource: "PullRequest", Field: "head", Code: "invalid"})
+			},
+			expected: ErrValidation,
+			status:   http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "server error",
+			call:     func() (*github.Response, error) { return errorResponse(http.StatusInternalServerError, "boom") },
+			expected: nil,
+			status:   http.StatusInternalServerError,
+		},
+	}
+
+	kinds := []error{ErrNotFound, ErrUnauthorized, ErrForbidden, ErrRateLimited, ErrConflict, ErrValidation}
+
+	for _, testCase := range testTable {
+		ghs := &gitHubService{}
+
+		// Act
+		err := ghs.do(context.Background(), "get repo", testCase.call)
+
+		// Assert
+		var apiErr *Error
+		if !errors.As(err, &apiErr) {
+			t.Errorf("%s: expected *Error, got %T", testCase.name, err)
+			continue
+		}
+		if apiErr.Op != "get repo" || apiErr.StatusCode != testCase.status {
+			t.Errorf("%s: Incorrect error: %+v", testCase.name, apiErr)
+		}
+		for _, kind := range kinds {
+			if errors.Is(err, kind) != (kind == testCase.expected) {
+				t.Errorf("%s: Incorrect errors.Is(err, %v): %v", testCase.name, kind, errors.Is(err, kind))
+			}
+		}
+
+		// Ошибка остается видимой через обертки и не теряет исходный тип go-github
+		wrapped := fmt.Errorf("outer: %w", err)
+		if testCase.expected != nil && !errors.Is(wrapped, testCase.expected) {
+			t.Errorf("%s: wrapped error lost its kind", testCase.name)
+		}
+		var ghErr *github.ErrorResponse
+		var rateErr *github.RateLimitError
+		if !errors.As(wrapped, &ghErr) && !errors.As(wrapped, &rateErr) {
+			t.Errorf("%s: wrapped error lost go-github error", testCase.name)
+		}
+	}
+}
+
+func TestNewErrorRequestID(t *testing.T) {
+	// Arrange
+	resp := &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}}
+	resp.Header.Set("X-GitHub-Request-Id", "ABCD:1234")
+
+	// Act
+	err := newError("get user", &github.Response{Response: resp}, &github.ErrorResponse{Response: resp, Message: "Not Found"})
+
+	// Assert
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.RequestID != "ABCD:1234" || apiErr.Message != "Not Found" {
+		t.Errorf("Incorrect error: %+v", err)
+	}
+}
+
+func TestNewErrorCanceled(t *testing.T) {
+	err := newError("get user", nil, context.Canceled)
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled as is, got %v", err)
+	}
+}
+
+func TestNewErrorGraphQL(t *testing.T) {
+	testTable := []struct {
+		kind     string
+		expected error
+	}{
+		{kind: "NOT_FOUND", expected: ErrNotFound},
+		{kind: "FORBIDDEN", expected: ErrForbidden},
+		{kind: "UNPROCESSABLE", expected: ErrValidation},
+		{kind: "", expected: nil},
+	}
+
+	for _, testCase := range testTable {
+		// Act
+		err := newError("list review threads", nil, &graphQLError{Type: testCase.kind, Message: "boom"})
+
+		// Assert
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.Message != "boom" {
+			t.Errorf("%q: Incorrect error: %+v", testCase.kind, err)
+			continue
+		}
+		if testCase.expected != nil && !errors.Is(err, testCase.expected) {
+			t.Errorf("%q: expected %v, got %v", testCase.kind, testCase.expected, err)
+		}
+		if testCase.expected == nil && (errors.Is(err, ErrNotFound) || errors.Is(err, ErrValidation)) {
+			t.Errorf("%q: Incorrect kind of error: %v", testCase.kind, err)
+		}
+	}
+}
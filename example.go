@@ -1,10 +1,14 @@
-package main
+package notgogithub
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
+	"io"
+	"net/url"
+	"path"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/go-github/v45/github"
@@ -17,6 +21,8 @@ type User struct {
 	FullName       string // Полное имя пользователя
 	FollowersCount int    // Количество подписчиков
 	FollowingCount int    // Количество подписок
+
+	Enriched Enrichment // EnrichProfiles, если загружен полный профиль, иначе известен только UserName
 }
 
 // Repository хранит информацию о репозиториях пользователя
@@ -29,190 +35,433 @@ type Repository struct {
 	ForksCount      int       // Количество форков (ответвлений, сделанных другими пользователями)
 	LastUpdatedTime time.Time // Время последнего изменения
 
-	// programmingLanguage хранит информацию об используемых языках программирования
-	programmingLanguage []struct {
-		Name           string  // Название языка программирования
-		PercentOfUsage float64 // Процент использования в репозитории
-	}
+	Languages []Language // Используемые языки программирования, начиная с основного
+	Enriched  Enrichment // EnrichLanguages, если загружены языки программирования
 }
 
+// Branch хранит информацию о ветке репозитория
 type Branch struct {
 	Name      string    // Название ветки
 	UpdatedAt time.Time // Дата последнего обновления
+
+	Enriched Enrichment // EnrichCommitDates, если загружена дата последнего обновления
 }
 
+// Commit хранит информацию о коммите
 type Commit struct {
 	Hash      string    // SHA коммита
 	Title     string    // Сообщение коммита
+	Author    string    // Логин автора на GitHub, а если коммит не связан с аккаунтом - имя автора из git
 	CreatedAt time.Time // Дата создания коммита
+	Parents   []string  // SHA родительских коммитов
 }
 
+// Issue хранит информацию о проблеме (issue) репозитория
 type Issue struct {
-	Title                   string    // Тема issue
-	IsClosed                bool      // Актуальная или разрешенная проблема
-	ResolvedPullRequestLink string    // Ссылка на PR, в котором разрешена проблема
-	CreatedAt               time.Time // Дата создания
-	UpdatedAt               time.Time // Дата обновления
+	ID              int64      // Глобальный идентификатор проблемы
+	Number          int        // Номер проблемы в репозитории (отображен в url как /issues/{number})
+	Title           string     // Тема issue
+	Body            string     // Описание
+	State           State      // Состояние: StateOpen или StateClosed
+	Author          string     // Логин автора
+	Labels          []string   // Названия меток
+	Assignees       []string   // Логины исполнителей
+	Milestone       int        // Номер вехи (0 - проблема не привязана к вехе)
+	Comments        int        // Количество комментариев в обсуждении
+	Locked          bool       // Обсуждение заблокировано: комментировать могут только соавторы
+	LockReason      LockReason // Причина блокировки ("" - не указана)
+	PullRequestLink string     // Ссылка на запрос на слияние, если проблема является им ("" - обычная проблема)
+	CreatedAt       time.Time  // Дата создания
+	UpdatedAt       time.Time  // Дата обновления
+	ClosedAt        time.Time  // Дата закрытия (нулевая, если проблема открыта)
 }
 
+// PullRequest хранит информацию о запросе на слияние
 type PullRequest struct {
-	ID           int    // Номер запроса на слияние (отображен в url как /pulls/{id})
-	Title        string // Название запроса на слияние
-	SourceBranch string // Название ветки-источника
-	TargetBranch string // Название ветки-назначения
-	IsClosed     bool   // Закрыт или открыт
+	ID           int64     // Глобальный идентификатор запроса на слияние
+	Number       int       // Номер запроса на слияние (отображен в url как /pulls/{number})
+	Title        string    // Название запроса на слияние
+	SourceBranch string    // Название ветки-источника
+	TargetBranch string    // Название ветки-назначения
+	State        State     // Состояние: StateOpen, StateDraft, StateClosed или StateMerged
+	Author       string    // Логин автора
+	Labels       []string  // Названия меток
+	Assignees    []string  // Логины исполнителей
+	CreatedAt    time.Time // Дата создания
+	UpdatedAt    time.Time // Дата обновления
+	ClosedAt     time.Time // Дата закрытия (нулевая, если запрос открыт)
+	MergedAt     time.Time // Дата слияния (нулевая, если слияния не было)
+
+	Body      string   // Описание запроса на слияние
+	HeadSHA   string   // SHA последнего коммита ветки-источника
+	Reviewers []string // Логины пользователей, у которых запрошено ревью
+	Link      string   // Ссылка на запрос на слияние
 }
 
+// Thread хранит обсуждение в запросе на слияние: первый комментарий к коду и ответы на него
+// от всех участников. Комментарии, не привязанные к коду, собраны в общее обсуждение с ID 0
 type Thread struct {
-	Filename   string   // имя файла из пулл реквеста, под которым комментарии
+	Filename   string   // имя файла из пулл реквеста, под которым комментарии ("" - общее обсуждение)
 	LineOfCode uint64   // Номер строки, под которой оставлены комментарии
-	Comments   []string // Сообщения из комментариев (история переписки)
+	Comments   []string // Сообщения из комментариев. Deprecated: используйте Conversation
+
+	ID           int64     // Идентификатор первого комментария обсуждения (0 - общее обсуждение)
+	Outdated     bool      // Код под обсуждением изменился после комментария
+	Resolved     bool      // Обсуждение отмечено решенным
+	Conversation []Comment // Комментарии от первого к последнему
 }
 
+// Tag хранит информацию о теге. Легковесный тег - только ссылка на коммит, аннотированный
+// дополнительно хранит автора, сообщение и дату. Релиза у тега может не быть
 type Tag struct {
 	Title       string    // Название тега
-	Hash        string    // SHA, хэш
-	Description string    // Описание тега
+	Hash        string    // SHA коммита, на который указывает тег
+	Description string    // Описание релиза тега. Deprecated: используйте Release.Body
 	ZipLink     string    // Ссылка на скачивание архива
-	CreatedAt   time.Time // Дата создания
+	CreatedAt   time.Time // Дата создания релиза тега. Deprecated: используйте Release.CreatedAt
+
+	Annotated bool      // Тег аннотированный
+	Tagger    string    // Имя автора аннотированного тега
+	Message   string    // Сообщение аннотированного тега
+	TaggedAt  time.Time // Дата создания аннотированного тега
+	Release   *Release  // Релиз тега (nil, если релиза нет или он не загружен)
+
+	Enriched Enrichment // EnrichReleaseNotes и EnrichAnnotations, если загружены релиз и данные аннотированного тега
 }
 
+// GitServiceIFace - методы управления GitHub. Его реализуют сервис, созданный NewGitHubService,
+// и FakeGitService для тестов без сети
 type GitServiceIFace interface {
 	// GetUserInfo получает основную информацию о пользователе
-	GetUserInfo(userName string) (*User, error)
+	GetUserInfo(ctx context.Context, userName string) (*User, error)
 
-	// GetUserRepositories получает список всех репозиториев пользователя
-	GetUserRepositories(userName string) ([]*Repository, error)
+	// GetUserRepositories получает список всех репозиториев пользователя.
+	// WithEnrichment без EnrichLanguages пропускает загрузку языков
+	GetUserRepositories(ctx context.Context, userName string, opts ...ListOption) ([]*Repository, error)
 
 	// GetRepositoryByName получает информацию об указанном репозитории
-	GetRepositoryByName(userName, repositoryName string) (*Repository, error)
+	GetRepositoryByName(ctx context.Context, userName, repositoryName string, opts ...ListOption) (*Repository, error)
 
 	// CreateRepository создает репозиторий с указанным именем
-	CreateRepository(repositoryName string) error
+	CreateRepository(ctx context.Context, repositoryName string) error
 
-	// GetRepositoryBranches получает список всех веток репозитория
-	GetRepositoryBranches(userName, repositoryName string) ([]*Branch, error)
+	// GetRepositoryBranches получает список всех веток репозитория.
+	// WithEnrichment без EnrichCommitDates пропускает загрузку дат последних коммитов
+	GetRepositoryBranches(ctx context.Context, userName, repositoryName string, opts ...ListOption) ([]*Branch, error)
 
 	// CreateBranch создает новую ветку
-	CreateBranch(userName, repoName, branchName, sha string) error
+	CreateBranch(ctx context.Context, userName, repoName, branchName, sha string) error
 
 	// DeleteBranch удаляет указанную ветку
-	DeleteBranch(userName, repoName, branchName string) error
+	DeleteBranch(ctx context.Context, userName, repoName, branchName string) error
+
+	// GetBranchCommits возвращает коммиты указанной ветки, каждый один раз, потомки раньше родителей.
+	// WithSince, WithUntil, WithPath, WithAuthor и WithMaxDepth ограничивают историю
+	GetBranchCommits(ctx context.Context, userName, repositoryName, branchName string, opts ...ListOption) ([]*Commit, error)
+
+	// GetRepositoryPullRequests получает информацию о запросах на слияние (WithState отбирает по состоянию)
+	GetRepositoryPullRequests(ctx context.Context, userName, repositoryName string, opts ...ListOption) ([]*PullRequest, error)
+
+	// CreatePullRequest создает запрос на слияние ветки sourceBranch в destBranch и возвращает его.
+	// Описание, черновик, рецензенты и метки задаются опциями. Если запрос создан, но рецензенты
+	// или метки не назначены, возвращаются и запрос, и ошибка
+	CreatePullRequest(ctx context.Context, owner, repositoryName, sourceBranch, destBranch, title string, opts ...PullRequestOption) (*PullRequest, error)
+
+	// GetPullRequest возвращает запрос на слияние по номеру
+	GetPullRequest(ctx context.Context, owner, repositoryName string, pullRequestID int) (*PullRequest, error)
+
+	// MergePullRequest сливает запрос на слияние способом method и возвращает SHA созданного коммита.
+	// Непустой headSHA должен совпадать с последним коммитом ветки-источника, иначе, как и при
+	// невозможности слияния (конфликт, черновик, закрытый запрос), ошибка совпадает с ErrConflict
+	MergePullRequest(ctx context.Context, owner, repositoryName string, pullRequestID int, method MergeMethod, headSHA string) (string, error)
+
+	// ClosePullRequest закрывает запрос на слияние без слияния
+	ClosePullRequest(ctx context.Context, owner, repositoryName string, pullRequestID int) (*PullRequest, error)
+
+	// ReopenPullRequest снова открывает закрытый запрос на слияние
+	ReopenPullRequest(ctx context.Context, owner, repositoryName string, pullRequestID int) (*PullRequest, error)
+
+	// UpdatePullRequestTitleBody меняет название и описание запроса на слияние
+	UpdatePullRequestTitleBody(ctx context.Context, owner, repositoryName string, pullRequestID int, title, body string) (*PullRequest, error)
+
+	// ChangePullRequestBase меняет ветку-назначение запроса на слияние
+	ChangePullRequestBase(ctx context.Context, owner, repositoryName string, pullRequestID int, destBranch string) (*PullRequest, error)
+
+	// ConvertPullRequestToDraft переводит открытый запрос на слияние в черновик
+	ConvertPullRequestToDraft(ctx context.Context, owner, repositoryName string, pullRequestID int) (*PullRequest, error)
+
+	// MarkPullRequestReadyForReview переводит черновик в открытый запрос на слияние, готовый к ревью
+	MarkPullRequestReadyForReview(ctx context.Context, owner, repositoryName string, pullRequestID int) (*PullRequest, error)
+
+	// GetPullRequestFiles получает файлы, измененные запросом на слияние, со статистикой строк и патчем
+	GetPullRequestFiles(ctx context.Context, owner, repositoryName string, pullRequestID int) ([]*PullRequestFile, error)
+
+	// GetPullRequestDiff получает все изменения запроса на слияние одним текстом в формате unified diff
+	GetPullRequestDiff(ctx context.Context, owner, repositoryName string, pullRequestID int) (string, error)
+
+	// GetPullRequestPatch получает изменения запроса на слияние по коммитам в формате git format-patch
+	GetPullRequestPatch(ctx context.Context, owner, repositoryName string, pullRequestID int) (string, error)
+
+	// GetPullRequestCommits получает коммиты запроса на слияние от старых к новым
+	GetPullRequestCommits(ctx context.Context, owner, repositoryName string, pullRequestID int) ([]*Commit, error)
+
+	// GetPullRequestMergeability сообщает, можно ли слить запрос на слияние и есть ли конфликты.
+	// Пока GitHub вычисляет состояние, метод повторяет запрос; если ответа так и нет, State равно MergeableUnknown
+	GetPullRequestMergeability(ctx context.Context, owner, repositoryName string, pullRequestID int) (*Mergeability, error)
+
+	// GetThreadsInfo получает обсуждения конкретного запроса на слияние: комментарии к коду, собранные
+	// по цепочкам ответов из всех ревью, и общее обсуждение запроса (Thread с ID 0, если в нем есть комментарии).
+	// Решенность обсуждений известна только через GraphQL API: если он недоступен (нет токена или у токена
+	// нет доступа), обсуждения возвращаются с Resolved false
+	GetThreadsInfo(ctx context.Context, userName, repositoryName string, pullRequestID int) ([]*Thread, error)
+
+	// GetReviews возвращает ревью запроса на слияние от старых к новым
+	GetReviews(ctx context.Context, owner, repositoryName string, pullRequestID int) ([]*Review, error)
+
+	// CreateReview отправляет ревью с решением event, текстом body и комментариями к строкам кода.
+	// Одобрить свой запрос на слияние или запросить в нем исправления нельзя: ошибка совпадает с ErrValidation
+	CreateReview(ctx context.Context, owner, repositoryName string, pullRequestID int, event ReviewEvent, body string, comments ...LineComment) (*Review, error)
+
+	// CreateLineComment оставляет комментарий к строке или диапазону строк последней версии кода,
+	// открывая новое обсуждение. Предложенное изменение добавляется в текст функцией Suggestion
+	CreateLineComment(ctx context.Context, owner, repositoryName string, pullRequestID int, comment LineComment) (*Comment, error)
 
-	// GetBranchCommits возвращает коммиты указанной ветки
-	GetBranchCommits(userName, repositoryName, branchName string) ([]*Commit, error)
+	// ReplyToThread отвечает в обсуждение с идентификатором threadID из GetThreadsInfo.
+	// threadID 0 - общее обсуждение запроса на слияние
+	ReplyToThread(ctx context.Context, owner, repositoryName string, pullRequestID int, threadID int64, body string) (*Comment, error)
 
-	// GetRepositoryPullRequests получает информацию о запросах на слияние
-	GetRepositoryPullRequests(userName, repositoryName string) ([]*PullRequest, error)
+	// EditComment меняет текст своего комментария в запросе на слияние. Если комментария
+	// в этом запросе нет, ошибка совпадает с ErrNotFound, если он чужой - с ErrForbidden
+	EditComment(ctx context.Context, owner, repositoryName string, pullRequestID int, commentID int64, body string) (*Comment, error)
 
-	// CreatePullRequest создает новый запрос на слияние
-	CreatePullRequest(userName, repoName, sourceBranch, destBranch, title string) error
+	// DeleteComment удаляет свой комментарий в запросе на слияние. Ошибки те же, что у EditComment
+	DeleteComment(ctx context.Context, owner, repositoryName string, pullRequestID int, commentID int64) error
 
-	// GetThreadsInfo получает информацию об обсуждениях конкретного запроса на слияние
-	GetThreadsInfo(userName, repositoryName string, pullRequestID int) ([]*Thread, error)
+	// DismissReview отклоняет ревью с решением (одобрение или запрос исправлений), указывая причину message
+	DismissReview(ctx context.Context, owner, repositoryName string, pullRequestID int, reviewID int64, message string) (*Review, error)
 
-	// GetIssues получает информацию об опубликованных проблемах репозитория
-	GetIssues(userName, repositoryName string) ([]*Issue, error)
+	// GetIssues получает информацию об опубликованных проблемах репозитория (WithState отбирает по состоянию)
+	GetIssues(ctx context.Context, userName, repositoryName string, opts ...ListOption) ([]*Issue, error)
 
-	// GetRepositoryContributors получает список соавторов репозитория
-	GetRepositoryContributors(userName, repositoryName string) ([]*User, error)
+	// CreateIssue создает проблему с темой title. Описание, метки, исполнители и веха задаются опциями
+	CreateIssue(ctx context.Context, owner, repositoryName, title string, opts ...IssueOption) (*Issue, error)
 
-	// GetRepositoryTags возвращает информацию о тегах репозитория
-	GetRepositoryTags(userName, repositoryName string) ([]*Tag, error)
+	// GetIssue возвращает проблему по номеру
+	GetIssue(ctx context.Context, owner, repositoryName string, issueNumber int) (*Issue, error)
+
+	// EditIssue меняет поля проблемы, переданные опциями
+	EditIssue(ctx context.Context, owner, repositoryName string, issueNumber int, opts ...IssueOption) (*Issue, error)
+
+	// CloseIssue закрывает проблему с причиной reason ("" - IssueCompleted)
+	CloseIssue(ctx context.Context, owner, repositoryName string, issueNumber int, reason IssueStateReason) (*Issue, error)
+
+	// ReopenIssue открывает закрытую проблему заново
+	ReopenIssue(ctx context.Context, owner, repositoryName string, issueNumber int) (*Issue, error)
+
+	// LockIssue блокирует обсуждение проблемы: комментировать его смогут только соавторы
+	LockIssue(ctx context.Context, owner, repositoryName string, issueNumber int, reason LockReason) error
+
+	// UnlockIssue снимает блокировку обсуждения проблемы
+	UnlockIssue(ctx context.Context, owner, repositoryName string, issueNumber int) error
+
+	// GetIssueComments возвращает комментарии обсуждения проблемы от старых к новым
+	GetIssueComments(ctx context.Context, owner, repositoryName string, issueNumber int) ([]*Comment, error)
+
+	// CreateIssueComment добавляет комментарий в обсуждение проблемы
+	CreateIssueComment(ctx context.Context, owner, repositoryName string, issueNumber int, body string) (*Comment, error)
+
+	// EditIssueComment меняет текст своего комментария в обсуждении проблемы. Если комментария
+	// в этой проблеме нет, ошибка совпадает с ErrNotFound, если он чужой - с ErrForbidden
+	EditIssueComment(ctx context.Context, owner, repositoryName string, issueNumber int, commentID int64, body string) (*Comment, error)
+
+	// DeleteIssueComment удаляет свой комментарий в обсуждении проблемы. Ошибки те же, что у EditIssueComment
+	DeleteIssueComment(ctx context.Context, owner, repositoryName string, issueNumber int, commentID int64) error
+
+	// GetRepositoryContributors получает список соавторов репозитория.
+	// WithEnrichment без EnrichProfiles возвращает только логины без загрузки профилей
+	GetRepositoryContributors(ctx context.Context, userName, repositoryName string, opts ...ListOption) ([]*User, error)
+
+	// GetRepositoryTags возвращает информацию о тегах репозитория, в том числе о тегах без релизов.
+	// WithEnrichment без EnrichReleaseNotes пропускает загрузку релизов,
+	// без EnrichAnnotations - загрузку автора, сообщения и даты аннотированных тегов
+	GetRepositoryTags(ctx context.Context, userName, repositoryName string, opts ...ListOption) ([]*Tag, error)
 
 	// CreateTag создает новый тег
-	CreateTag(userName, repositoryName, title, sha string) error
+	CreateTag(ctx context.Context, userName, repositoryName, title, sha string) error
 
 	// DeleteTag удаляет тег по имени
-	DeleteTag(userName, repositoryName, tagName string) error
+	DeleteTag(ctx context.Context, userName, repositoryName, tagName string) error
+
+	// GetRepositoryReleases возвращает релизы репозитория, начиная с последнего
+	GetRepositoryReleases(ctx context.Context, owner, repositoryName string) ([]*Release, error)
+
+	// GetReleaseByTag возвращает опубликованный релиз тега. Если релиза нет, ошибка совпадает с ErrNotFound
+	GetReleaseByTag(ctx context.Context, owner, repositoryName, tagName string) (*Release, error)
+
+	// GetRelease возвращает релиз по идентификатору, в том числе черновик
+	GetRelease(ctx context.Context, owner, repositoryName string, releaseID int64) (*Release, error)
+
+	// GetLatestRelease возвращает последний (latest) опубликованный релиз репозитория
+	GetLatestRelease(ctx context.Context, owner, repositoryName string) (*Release, error)
+
+	// CreateRelease создает релиз тега tagName. Если тега нет, GitHub создаст его при публикации
+	// релиза (см. WithTargetCommitish). Черновик создается опцией WithReleaseDraft(true)
+	CreateRelease(ctx context.Context, owner, repositoryName, tagName string, opts ...ReleaseOption) (*Release, error)
+
+	// EditRelease меняет поля релиза, переданные опциями
+	EditRelease(ctx context.Context, owner, repositoryName string, releaseID int64, opts ...ReleaseOption) (*Release, error)
+
+	// PublishRelease публикует черновик релиза
+	PublishRelease(ctx context.Context, owner, repositoryName string, releaseID int64) (*Release, error)
+
+	// DeleteRelease удаляет релиз. Тег релиза остается
+	DeleteRelease(ctx context.Context, owner, repositoryName string, releaseID int64) error
+
+	// UploadReleaseAsset прикрепляет к релизу файл name с MIME-типом contentType.
+	// Содержимое читается с начала content
+	UploadReleaseAsset(ctx context.Context, owner, repositoryName string, releaseID int64, name, contentType string, content io.ReadSeeker) (*ReleaseAsset, error)
+
+	// DownloadReleaseAsset открывает на чтение файл релиза по имени. Вызывающий должен закрыть результат.
+	// Если файла нет, ошибка совпадает с ErrNotFound
+	DownloadReleaseAsset(ctx context.Context, owner, repositoryName string, releaseID int64, assetName string) (io.ReadCloser, error)
 
 	// SetAccessToRepository предоставляет доступ к репозиторию указанному пользователю
-	SetAccessToRepository(owner, repositoryName, oppoUserName string) error
+	SetAccessToRepository(ctx context.Context, owner, repositoryName, oppoUserName string) error
 
 	// DenyAccessToRepository закрывает доступ к репозиторию указанному пользователю
-	DenyAccessToRepository(owner, repositoryName, oppoUserName string) error
+	DenyAccessToRepository(ctx context.Context, owner, repositoryName, oppoUserName string) error
+
+	// RateLimitStatus возвращает текущий остаток лимита запросов к GitHub API
+	RateLimitStatus(ctx context.Context) (*RateLimit, error)
+
+	// IterateUserRepositories постранично обходит репозитории пользователя
+	IterateUserRepositories(ctx context.Context, userName string, opts ...ListOption) *RepositoryIterator
+
+	// IterateRepositoryBranches постранично обходит ветки репозитория
+	IterateRepositoryBranches(ctx context.Context, userName, repositoryName string, opts ...ListOption) *BranchIterator
+
+	// IterateBranchCommits постранично обходит коммиты указанной ветки в порядке ответа GitHub.
+	// Фильтры те же, что у GetBranchCommits, кроме WithMaxDepth: обход останавливает вызывающий
+	IterateBranchCommits(ctx context.Context, userName, repositoryName, branchName string, opts ...ListOption) *CommitIterator
+
+	// IterateRepositoryPullRequests постранично обходит запросы на слияние
+	IterateRepositoryPullRequests(ctx context.Context, userName, repositoryName string, opts ...ListOption) *PullRequestIterator
+
+	// IterateIssues постранично обходит проблемы репозитория
+	IterateIssues(ctx context.Context, userName, repositoryName string, opts ...ListOption) *IssueIterator
+
+	// IterateRepositoryContributors постранично обходит соавторов репозитория
+	IterateRepositoryContributors(ctx context.Context, userName, repositoryName string, opts ...ListOption) *UserIterator
+
+	// IterateRepositoryTags постранично обходит теги репозитория
+	IterateRepositoryTags(ctx context.Context, userName, repositoryName string, opts ...ListOption) *TagIterator
+
+	// IterateRepositoryReleases постранично обходит релизы репозитория
+	IterateRepositoryReleases(ctx context.Context, owner, repositoryName string) *ReleaseIterator
 }
 
 // Структура, реализующая интерфейс GitServiceIFace
 type gitHubService struct {
 	client *github.Client
-}
+	opts   options
 
-// NewGitHubService - конструктор gitHubService
-func NewGitHubService(ctx context.Context) (GitServiceIFace, error) {
-	// Используем Oauth2.0 в качестве протокола аутентификации
-	ts := oauth2.StaticTokenSource(
-		// Передаем Oauth2.0-токен, который можно получить в настройках профиля GitHub
-		&oauth2.Token{AccessToken: os.Getenv("GITHUB_TOKEN")},
-	)
-	tc := oauth2.NewClient(ctx, ts)
+	rateMu sync.Mutex
+	rate   RateLimit // Последнее известное состояние лимита запросов
+}
 
-	// Запросы к GitHub API будут отправлены от имени аутентифицированного пользователя
-	client := github.NewClient(tc)
+// NewGitHubService - конструктор gitHubService.
+// ctx используется только для создания OAuth-клиента; каждый метод сервиса принимает собственный контекст.
+// Учетные данные проверяются сразу: если токен не удалось получить, возвращается ошибка.
+// Готовый токен (StaticToken, EnvToken, DotEnvToken) проверяется только на пустоту:
+// недействительный токен обнаружится на первом запросе как ErrUnauthorized
+func NewGitHubService(ctx context.Context, opts ...Option) (GitServiceIFace, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
 
-	return &gitHubService{client: client}, nil
-}
+	if o.auth == nil {
+		return nil, errors.New("auth: nil Authenticator")
+	}
 
-const (
-	commitsURL    = "https://api.github.com/repos/%s/%s/commits/"
-	gitCommitsURL = "https://api.github.com/repos/%s/%s/git/commits/"
-)
+	if hc := o.httpClient(); hc != nil {
+		// oauth2 берет базовый транспорт из контекста
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, hc)
+	}
 
-func getLanguages(gitHubRepo *github.Repository, ghs *gitHubService) ([]struct {
-	Name           string
-	PercentOfUsage float64
-}, error) {
-	languages, _, err := ghs.client.Repositories.ListLanguages(context.Background(), gitHubRepo.GetOwner().GetLogin(), gitHubRepo.GetName())
+	anonymous, err := o.newClient(o.httpClient())
 	if err != nil {
 		return nil, err
 	}
 
-	sum := 0
-	for _, bytes := range languages {
-		sum += bytes
+	// Используем Oauth2.0 в качестве протокола аутентификации
+	ts, err := o.auth.TokenSource(ctx, anonymous)
+	if err != nil {
+		return nil, fmt.Errorf("auth: %w", err)
 	}
 
-	var Languages []struct {
-		Name           string
-		PercentOfUsage float64
+	tc := o.httpClient()
+	if ts != nil {
+		// Получаем токен заранее, чтобы ошибки учетных данных обнаружились при создании сервиса
+		token, err := ts.Token()
+		if err != nil {
+			return nil, fmt.Errorf("auth: %w", err)
+		}
+		if token.AccessToken == "" {
+			return nil, fmt.Errorf("auth: %w", ErrEmptyToken)
+		}
+
+		tc = oauth2.NewClient(ctx, oauth2.ReuseTokenSource(token, ts))
 	}
 
-	for lang, bytes := range languages {
-		l := struct {
-			Name           string
-			PercentOfUsage float64
-		}{
-			lang,
-			float64(bytes) / float64(sum),
-		}
-		Languages = append(Languages, l)
+	// Запросы к GitHub API будут отправлены от имени аутентифицированного пользователя
+	client, err := o.newClient(tc)
+	if err != nil {
+		return nil, err
 	}
 
-	return Languages, nil
+	return &gitHubService{client: client, opts: o}, nil
 }
 
-func findParentsOfCommit(ghs *gitHubService, commit *github.Commit, userName string, repositoryName string) ([]*github.Commit, error) {
-	// Вырезаем SHA и по SHA ищем коммит (попробовать переделать)
-	url := fmt.Sprintf(gitCommitsURL, userName, repositoryName)
-	sha := strings.Replace(commit.GetURL(), url, "", 1)
-	goodCommit, _, err := ghs.client.Git.GetCommit(context.Background(), userName, repositoryName, sha)
-	if err != nil {
-		return nil, fmt.Errorf("get commit: %w", err)
+// commitSHA возвращает SHA коммита. Если поле sha в ответе пустое, SHA берется
+// из последнего сегмента URL коммита, поэтому адрес хоста (github.com или Enterprise) не важен
+func commitSHA(sha, commitURL string) string {
+	if sha != "" {
+		return sha
 	}
 
-	var Parents []*github.Commit
-	Parents = append(Parents, goodCommit)
+	u, err := url.Parse(commitURL)
+	if err != nil {
+		return ""
+	}
+	p := strings.TrimSuffix(u.Path, "/")
+	if p == "" {
+		return ""
+	}
+	return path.Base(p)
+}
 
-	for _, commit := range goodCommit.Parents {
-		grandParents, err := findParentsOfCommit(ghs, commit, userName, repositoryName)
-		if err != nil {
-			return nil, fmt.Errorf("find parents of commit: %w", err)
+// nonNil возвращает элементы items без nil: в неполном ответе GitHub элемент списка может быть null
+func nonNil[T any](items []*T) []*T {
+	var present []*T
+	for _, item := range items {
+		if item != nil {
+			present = append(present, item)
 		}
+	}
+	return present
+}
 
-		Parents = append(Parents, grandParents...)
+// getLanguages загружает языки программирования репозитория
+func getLanguages(ctx context.Context, gitHubRepo *github.Repository, ghs *gitHubService) ([]Language, error) {
+	languages, _, err := callAPI(ctx, ghs, "get languages", func() (map[string]int, *github.Response, error) {
+		return ghs.client.Repositories.ListLanguages(ctx, gitHubRepo.GetOwner().GetLogin(), gitHubRepo.GetName())
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return Parents, nil
+	return newLanguages(languages), nil
 }
 
 // Необходимо реализовать нижепредставленные методы в соответствии со структурой интерфейса
@@ -221,10 +470,12 @@ func findParentsOfCommit(ghs *gitHubService, commit *github.Commit, userName str
 //                                   |
 //                                   V
 
-func (ghs *gitHubService) GetUserInfo(userName string) (*User, error) {
-	ghUser, _, err := ghs.client.Users.Get(context.Background(), userName)
+func (ghs *gitHubService) GetUserInfo(ctx context.Context, userName string) (*User, error) {
+	ghUser, _, err := callAPI(ctx, ghs, "get user", func() (*github.User, *github.Response, error) {
+		return ghs.client.Users.Get(ctx, userName)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("get user: %w", err)
+		return nil, err
 	}
 
 	user := User{
@@ -232,104 +483,151 @@ func (ghs *gitHubService) GetUserInfo(userName string) (*User, error) {
 		FullName:       ghUser.GetName(),
 		FollowersCount: ghUser.GetFollowers(),
 		FollowingCount: ghUser.GetFollowing(),
+		Enriched:       EnrichProfiles,
 	}
 
 	return &user, nil
 }
 
-func (ghs *gitHubService) GetUserRepositories(userName string) ([]*Repository, error) {
-	opts := github.RepositoryListOptions{}
-
-	repos, _, err := ghs.client.Repositories.List(context.Background(), userName, &opts)
+func (ghs *gitHubService) GetUserRepositories(ctx context.Context, userName string, opts ...ListOption) ([]*Repository, error) {
+	repos, err := collectPages(ctx, ghs.opts.maxItems, ghs.repositoryPages(ctx, userName))
 	if err != nil {
-		return nil, fmt.Errorf("list user repos: %w", err)
+		return nil, err
 	}
 
-	var Repos []*Repository
-	for _, r := range repos {
-		langs, err := getLanguages(r, ghs)
-		if err != nil {
-			return nil, fmt.Errorf("get languages: %w", err)
+	return ghs.convertRepositories(ctx, repos, enrichment(opts))
+}
+
+// repositoryPages загружает страницы списка репозиториев пользователя
+func (ghs *gitHubService) repositoryPages(ctx context.Context, userName string) pageFetcher[*github.Repository] {
+	return func(lo github.ListOptions) ([]*github.Repository, *github.Response, error) {
+		opts := github.RepositoryListOptions{ListOptions: lo}
+		return callAPI(ctx, ghs, "list user repos", func() ([]*github.Repository, *github.Response, error) {
+			return ghs.client.Repositories.List(ctx, userName, &opts)
+		})
+	}
+}
+
+// convertRepositories преобразует репозитории в Repository и, если enrich содержит EnrichLanguages,
+// дополняет их языками программирования
+func (ghs *gitHubService) convertRepositories(ctx context.Context, repos []*github.Repository, enrich Enrichment) ([]*Repository, error) {
+	repos = nonNil(repos)
+	if !enrich.Has(EnrichLanguages) {
+		var Repos []*Repository
+		for _, r := range repos {
+			Repos = append(Repos, newRepository(r, nil))
 		}
+		return Repos, nil
+	}
 
-		repo := Repository{
-			Name:                r.GetName(),
-			Description:         r.GetDescription(),
-			Link:                r.GetHTMLURL(),
-			IsPrivate:           r.GetPrivate(),
-			StarsCount:          r.GetStargazersCount(),
-			ForksCount:          r.GetForksCount(),
-			LastUpdatedTime:     r.GetUpdatedAt().Time,
-			programmingLanguage: langs,
+	return parallel(ctx, ghs.opts.concurrency, repos, func(ctx context.Context, r *github.Repository) (*Repository, error) {
+		langs, err := getLanguages(ctx, r, ghs)
+		if err != nil {
+			return nil, err
 		}
 
-		Repos = append(Repos, &repo)
-	}
+		repo := newRepository(r, langs)
+		repo.Enriched = EnrichLanguages
+		return repo, nil
+	})
+}
 
-	return Repos, nil
+// newRepository преобразует репозиторий GitHub в Repository
+func newRepository(r *github.Repository, langs []Language) *Repository {
+	return &Repository{
+		Name:            r.GetName(),
+		Description:     r.GetDescription(),
+		Link:            r.GetHTMLURL(),
+		IsPrivate:       r.GetPrivate(),
+		StarsCount:      r.GetStargazersCount(),
+		ForksCount:      r.GetForksCount(),
+		LastUpdatedTime: r.GetUpdatedAt().Time,
+		Languages:       langs,
+	}
 }
 
-func (ghs *gitHubService) GetRepositoryByName(userName, repositoryName string) (*Repository, error) {
-	repo, _, err := ghs.client.Repositories.Get(context.Background(), userName, repositoryName)
+func (ghs *gitHubService) GetRepositoryByName(ctx context.Context, userName, repositoryName string, opts ...ListOption) (*Repository, error) {
+	repo, _, err := callAPI(ctx, ghs, "get repo", func() (*github.Repository, *github.Response, error) {
+		return ghs.client.Repositories.Get(ctx, userName, repositoryName)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("get repo: %w", err)
+		return nil, err
 	}
 
-	langs, err := getLanguages(repo, ghs)
-	if err != nil {
-		return nil, fmt.Errorf("get langs for repo: %w", err)
+	if !enrichment(opts).Has(EnrichLanguages) {
+		return newRepository(repo, nil), nil
 	}
 
-	rp := Repository{
-		Name:                repo.GetName(),
-		Description:         repo.GetDescription(),
-		Link:                repo.GetHTMLURL(),
-		IsPrivate:           repo.GetPrivate(),
-		StarsCount:          repo.GetStargazersCount(),
-		ForksCount:          repo.GetForksCount(),
-		LastUpdatedTime:     repo.GetUpdatedAt().Time,
-		programmingLanguage: langs,
+	langs, err := getLanguages(ctx, repo, ghs)
+	if err != nil {
+		return nil, err
 	}
 
-	return &rp, nil
+	r := newRepository(repo, langs)
+	r.Enriched = EnrichLanguages
+	return r, nil
 }
 
-func (ghs *gitHubService) CreateRepository(repositoryName string) error {
+func (ghs *gitHubService) CreateRepository(ctx context.Context, repositoryName string) error {
 	repo := &github.Repository{Name: &repositoryName}
-	_, _, err := ghs.client.Repositories.Create(context.Background(), "", repo)
+	_, _, err := callAPI(ctx, ghs, "create repo", func() (*github.Repository, *github.Response, error) {
+		return ghs.client.Repositories.Create(ctx, "", repo)
+	})
 	return err
 }
 
-func (ghs *gitHubService) GetRepositoryBranches(owner, repositoryName string) ([]*Branch, error) {
-	opts := github.BranchListOptions{}
-	branches, _, err := ghs.client.Repositories.ListBranches(context.Background(), owner, repositoryName, &opts)
+func (ghs *gitHubService) GetRepositoryBranches(ctx context.Context, owner, repositoryName string, opts ...ListOption) ([]*Branch, error) {
+	branches, err := collectPages(ctx, ghs.opts.maxItems, ghs.branchPages(ctx, owner, repositoryName))
 	if err != nil {
-		return nil, fmt.Errorf("list branches: %w", err)
+		return nil, err
 	}
 
-	var Branches []*Branch
-	for _, branch := range branches {
+	return ghs.convertBranches(ctx, owner, repositoryName, branches, enrichment(opts))
+}
+
+// branchPages загружает страницы списка веток репозитория
+func (ghs *gitHubService) branchPages(ctx context.Context, owner, repositoryName string) pageFetcher[*github.Branch] {
+	return func(lo github.ListOptions) ([]*github.Branch, *github.Response, error) {
+		opts := github.BranchListOptions{ListOptions: lo}
+		return callAPI(ctx, ghs, "list branches", func() ([]*github.Branch, *github.Response, error) {
+			return ghs.client.Repositories.ListBranches(ctx, owner, repositoryName, &opts)
+		})
+	}
+}
+
+// convertBranches преобразует ветки в Branch и, если enrich содержит EnrichCommitDates,
+// дополняет их датой последнего коммита
+func (ghs *gitHubService) convertBranches(ctx context.Context, owner, repositoryName string, branches []*github.Branch, enrich Enrichment) ([]*Branch, error) {
+	branches = nonNil(branches)
+	if !enrich.Has(EnrichCommitDates) {
+		var Branches []*Branch
+		for _, branch := range branches {
+			Branches = append(Branches, &Branch{Name: branch.GetName()})
+		}
+		return Branches, nil
+	}
+
+	return parallel(ctx, ghs.opts.concurrency, branches, func(ctx context.Context, branch *github.Branch) (*Branch, error) {
 		badCommit := branch.GetCommit()
 		// badCommit.GetAuthor().GetDate() returns 0001-01-01 00:00:00 +0000 UTC
-		url := fmt.Sprintf(commitsURL, owner, repositoryName)
-		sha := strings.Replace(badCommit.GetURL(), url, "", 1)
+		sha := commitSHA(badCommit.GetSHA(), badCommit.GetURL())
 
-		goodCommit, _, err := ghs.client.Git.GetCommit(context.Background(), owner, repositoryName, sha)
+		goodCommit, _, err := callAPI(ctx, ghs, "get commit", func() (*github.Commit, *github.Response, error) {
+			return ghs.client.Git.GetCommit(ctx, owner, repositoryName, sha)
+		})
 		if err != nil {
-			return nil, fmt.Errorf("get commit: %w", err)
+			return nil, err
 		}
 
-		br := Branch{
-			Name:      *branch.Name,
+		return &Branch{
+			Name:      branch.GetName(),
 			UpdatedAt: goodCommit.GetAuthor().GetDate(),
-		}
-		Branches = append(Branches, &br)
-	}
-
-	return Branches, nil
+			Enriched:  EnrichCommitDates,
+		}, nil
+	})
 }
 
-func (ghs *gitHubService) CreateBranch(userName, repoName, branchName, sha string) error {
+func (ghs *gitHubService) CreateBranch(ctx context.Context, userName, repoName, branchName, sha string) error {
 	// https://stackoverflow.com/questions/9506181/github-api-create-branch
 	// https://api.github.com/repos/jostanise/rsa_encrypted_local_chat/branches
 	// https://api.github.com/repos/jostanise/rsa_encrypted_local_chat/git/refs/heads
@@ -338,272 +636,437 @@ func (ghs *gitHubService) CreateBranch(userName, repoName, branchName, sha strin
 	ref := "refs/heads/" + branchName
 	obj := github.GitObject{SHA: &sha}
 	ghref := github.Reference{Ref: &ref, Object: &obj}
-	_, _, err := ghs.client.Git.CreateRef(context.Background(), userName, repoName, &ghref)
+	_, _, err := callAPI(ctx, ghs, "create branch", func() (*github.Reference, *github.Response, error) {
+		return ghs.client.Git.CreateRef(ctx, userName, repoName, &ghref)
+	})
 	return err
 }
 
-func (ghs *gitHubService) DeleteBranch(userName, repoName, branchName string) error {
+func (ghs *gitHubService) DeleteBranch(ctx context.Context, userName, repoName, branchName string) error {
 	ref := "refs/heads/" + branchName
-	_, err := ghs.client.Git.DeleteRef(context.Background(), userName, repoName, ref)
-	return err
+	return ghs.do(ctx, "delete branch", func() (*github.Response, error) {
+		return ghs.client.Git.DeleteRef(ctx, userName, repoName, ref)
+	})
 }
 
-func (ghs *gitHubService) GetBranchCommits(userName, repositoryName, branchName string) ([]*Commit, error) {
-	br, _, err := ghs.client.Repositories.GetBranch(context.Background(), userName, repositoryName, branchName, true)
+func (ghs *gitHubService) GetBranchCommits(ctx context.Context, userName, repositoryName, branchName string, opts ...ListOption) ([]*Commit, error) {
+	filter := newListOptions(opts)
+	commits, err := collectPages(ctx, filter.limit(ghs.opts.maxItems), ghs.commitPages(ctx, userName, repositoryName, branchName, filter))
 	if err != nil {
-		return nil, fmt.Errorf("get branch: %w", err)
+		return nil, err
 	}
 
-	lastCommit := br.GetCommit().GetCommit()
-	commits, err := findParentsOfCommit(ghs, lastCommit, userName, repositoryName)
-	if err != nil {
-		return nil, fmt.Errorf("find parents of commit: %w", err)
+	return linearizeHistory(convertRepositoryCommits(commits)), nil
+}
+
+// commitPages загружает страницы истории коммитов, достижимых из указанной ветки
+func (ghs *gitHubService) commitPages(ctx context.Context, userName, repositoryName, branchName string, filter listOptions) pageFetcher[*github.RepositoryCommit] {
+	return func(lo github.ListOptions) ([]*github.RepositoryCommit, *github.Response, error) {
+		opts := github.CommitsListOptions{
+			SHA:         branchName,
+			Path:        filter.path,
+			Author:      filter.author,
+			Since:       filter.since,
+			Until:       filter.until,
+			ListOptions: lo,
+		}
+		return callAPI(ctx, ghs, "list commits", func() ([]*github.RepositoryCommit, *github.Response, error) {
+			return ghs.client.Repositories.ListCommits(ctx, userName, repositoryName, &opts)
+		})
 	}
+}
 
+// convertRepositoryCommits преобразует коммиты из списка коммитов репозитория в Commit
+func convertRepositoryCommits(commits []*github.RepositoryCommit) []*Commit {
 	var Commits []*Commit
-	for _, c := range commits {
+	for _, c := range nonNil(commits) {
 		commit := Commit{
 			Hash:      c.GetSHA(),
-			Title:     c.GetMessage(),
-			CreatedAt: c.GetAuthor().GetDate(),
+			Title:     c.GetCommit().GetMessage(),
+			Author:    c.GetAuthor().GetLogin(),
+			CreatedAt: c.GetCommit().GetAuthor().GetDate(),
+		}
+		if commit.Author == "" {
+			commit.Author = c.GetCommit().GetAuthor().GetName()
+		}
+		for _, parent := range nonNil(c.Parents) {
+			commit.Parents = append(commit.Parents, commitSHA(parent.GetSHA(), parent.GetURL()))
 		}
 		Commits = append(Commits, &commit)
 	}
 
-	return Commits, nil
+	return Commits
 }
 
-func (ghs *gitHubService) GetRepositoryPullRequests(userName, repositoryName string) ([]*PullRequest, error) { // <--- no username?
-	opts := github.PullRequestListOptions{State: "all"}
-	pullRequests, _, err := ghs.client.PullRequests.List(context.Background(), userName, repositoryName, &opts)
+func (ghs *gitHubService) GetRepositoryPullRequests(ctx context.Context, userName, repositoryName string, opts ...ListOption) ([]*PullRequest, error) { // <--- no username?
+	pullRequests, err := collectPages(ctx, ghs.opts.maxItems, ghs.pullRequestPages(ctx, userName, repositoryName, newListOptions(opts)))
 	if err != nil {
-		return nil, fmt.Errorf("list pull requests: %w", err)
+		return nil, err
 	}
 
-	var PullRequests []*PullRequest
-	for _, r := range pullRequests {
-		request := PullRequest{
-			ID:           int(*r.ID),
-			Title:        *r.Title,
-			SourceBranch: *r.Head.Ref,
-			TargetBranch: *r.Base.Ref,
-			IsClosed:     *r.Locked, // не то? (выдаёт только false)
+	return convertPullRequests(pullRequests), nil
+}
+
+// pullRequestPages загружает страницы списка запросов на слияние, оставляя на них только запросы,
+// прошедшие отбор по состоянию
+func (ghs *gitHubService) pullRequestPages(ctx context.Context, userName, repositoryName string, filter listOptions) pageFetcher[*github.PullRequest] {
+	return func(lo github.ListOptions) ([]*github.PullRequest, *github.Response, error) {
+		opts := github.PullRequestListOptions{State: filter.apiState(), ListOptions: lo}
+		pullRequests, resp, err := callAPI(ctx, ghs, "list pull requests", func() ([]*github.PullRequest, *github.Response, error) {
+			return ghs.client.PullRequests.List(ctx, userName, repositoryName, &opts)
+		})
+		if err != nil {
+			return nil, resp, err
 		}
-		PullRequests = append(PullRequests, &request)
-	}
 
-	return PullRequests, nil
+		var matched []*github.PullRequest
+		for _, r := range pullRequests {
+			if filter.matchState(pullRequestState(r)) {
+				matched = append(matched, r)
+			}
+		}
+		return matched, resp, nil
+	}
 }
 
-func (ghs *gitHubService) CreatePullRequest(userName, repoName, sourceBranch, destBranch, title string) error {
-	pull := github.NewPullRequest{Title: &title, Head: &sourceBranch, Base: &destBranch}
-	_, _, err := ghs.client.PullRequests.Create(context.Background(), userName, repoName, &pull)
-	return err
+// pullRequestState определяет состояние запроса на слияние: GitHub отдает только open или closed,
+// а слияние и черновик отмечает отдельными полями
+func pullRequestState(r *github.PullRequest) State {
+	switch {
+	case !r.GetMergedAt().IsZero() || r.GetMerged():
+		return StateMerged
+	case r.GetState() == "closed":
+		return StateClosed
+	case r.GetDraft():
+		return StateDraft
+	}
+	return StateOpen
 }
 
-func (ghs *gitHubService) GetThreadsInfo(userName, repositoryName string, pullRequestID int) ([]*Thread, error) {
-	opts := github.ListOptions{}
-	reviews, _, err := ghs.client.PullRequests.ListReviews(context.Background(), userName, repositoryName, pullRequestID, &opts)
-	if err != nil {
-		return nil, fmt.Errorf("list reviews: %w", err)
+// newPullRequest преобразует запрос на слияние GitHub в PullRequest
+func newPullRequest(r *github.PullRequest) *PullRequest {
+	request := &PullRequest{
+		ID:           r.GetID(),
+		Number:       r.GetNumber(),
+		Title:        r.GetTitle(),
+		SourceBranch: r.GetHead().GetRef(),
+		TargetBranch: r.GetBase().GetRef(),
+		State:        pullRequestState(r),
+		Author:       r.GetUser().GetLogin(),
+		Assignees:    logins(r.Assignees),
+		CreatedAt:    r.GetCreatedAt(),
+		UpdatedAt:    r.GetUpdatedAt(),
+		ClosedAt:     r.GetClosedAt(),
+		MergedAt:     r.GetMergedAt(),
+		Body:         r.GetBody(),
+		HeadSHA:      r.GetHead().GetSHA(),
+		Reviewers:    logins(r.RequestedReviewers),
+		Link:         r.GetHTMLURL(),
 	}
+	for _, label := range nonNil(r.Labels) {
+		request.Labels = append(request.Labels, label.GetName())
+	}
+	return request
+}
 
-	var AllThreads []*Thread
-
-	for _, review := range reviews { // оказывается reviews делится по людям
-		opts := github.ListOptions{}
-		comments, _, err := ghs.client.PullRequests.ListReviewComments(context.Background(), userName, repositoryName, pullRequestID, review.GetID(), &opts)
-		if err != nil {
-			return nil, fmt.Errorf("list review comments: %w", err)
-		}
-
-		type Comment struct {
-			FileName   string
-			LineOfCode uint64
-			Message    string
-		}
-		var AllComments []Comment
+// convertPullRequests преобразует запросы на слияние GitHub в PullRequest
+func convertPullRequests(pullRequests []*github.PullRequest) []*PullRequest {
+	var PullRequests []*PullRequest
+	for _, r := range nonNil(pullRequests) {
+		PullRequests = append(PullRequests, newPullRequest(r))
+	}
 
-		FileNames := make(map[string]struct{})
+	return PullRequests
+}
 
-		for _, comment := range comments {
-			// comment.GetOriginalLine() возвращает 0
-			goodComment, _, err := ghs.client.PullRequests.GetComment(context.Background(), userName, repositoryName, comment.GetID())
-			if err != nil {
-				return nil, fmt.Errorf("get comment: %w", err)
-			}
+func (ghs *gitHubService) GetIssues(ctx context.Context, userName, repositoryName string, opts ...ListOption) ([]*Issue, error) {
+	issues, err := collectPages(ctx, ghs.opts.maxItems, ghs.issuePages(ctx, userName, repositoryName, newListOptions(opts)))
+	if err != nil {
+		return nil, err
+	}
 
-			c := Comment{
-				FileName:   goodComment.GetPath(),
-				LineOfCode: uint64(goodComment.GetOriginalLine()),
-				Message:    goodComment.GetBody(),
-			}
-			AllComments = append(AllComments, c)
-			FileNames[c.FileName] = struct{}{}
-		}
+	return convertIssues(issues), nil
+}
 
-		// Соберём все номера строк для каждого файла
-		type FileLines struct {
-			FileName string
-			Lines    map[uint64]struct{}
+// issuePages загружает страницы списка проблем репозитория, оставляя на них только проблемы,
+// прошедшие отбор по состоянию
+func (ghs *gitHubService) issuePages(ctx context.Context, userName, repositoryName string, filter listOptions) pageFetcher[*github.Issue] {
+	return func(lo github.ListOptions) ([]*github.Issue, *github.Response, error) {
+		opts := github.IssueListByRepoOptions{State: filter.apiState(), ListOptions: lo}
+		issues, resp, err := callAPI(ctx, ghs, "list issues by repo", func() ([]*github.Issue, *github.Response, error) {
+			return ghs.client.Issues.ListByRepo(ctx, userName, repositoryName, &opts)
+		})
+		if err != nil {
+			return nil, resp, err
 		}
-		var LinesForFiles []FileLines
-
-		for f := range FileNames {
-			lines := make(map[uint64]struct{})
 
-			for _, comment := range AllComments {
-				if comment.FileName == f {
-					lines[comment.LineOfCode] = struct{}{}
-				}
-			}
-
-			lfl := FileLines{
-				FileName: f,
-				Lines:    lines,
+		var matched []*github.Issue
+		for _, issue := range issues {
+			if filter.matchState(State(issue.GetState())) {
+				matched = append(matched, issue)
 			}
-			LinesForFiles = append(LinesForFiles, lfl)
 		}
+		return matched, resp, nil
+	}
+}
 
-		var Threads []*Thread
+// convertIssues преобразует проблемы GitHub в Issue
+func convertIssues(issues []*github.Issue) []*Issue {
+	var Issues []*Issue
+	for _, issue := range nonNil(issues) {
+		Issues = append(Issues, newIssue(issue))
+	}
 
-		for _, f := range LinesForFiles {
-			for line := range f.Lines {
-				var comments []string
-				for _, comment := range AllComments {
-					if (comment.FileName == f.FileName) && (comment.LineOfCode == line) {
-						comments = append(comments, comment.Message)
-					}
-				}
+	return Issues
+}
 
-				t := Thread{
-					Filename:   f.FileName,
-					LineOfCode: line,
-					Comments:   comments,
-				}
-				Threads = append(Threads, &t)
-			}
-		}
-		AllThreads = append(AllThreads, Threads...)
+// newIssue преобразует проблему GitHub в Issue
+func newIssue(issue *github.Issue) *Issue {
+	i := &Issue{
+		ID:              issue.GetID(),
+		Number:          issue.GetNumber(),
+		Title:           issue.GetTitle(),
+		Body:            issue.GetBody(),
+		State:           State(issue.GetState()),
+		Author:          issue.GetUser().GetLogin(),
+		Assignees:       logins(issue.Assignees),
+		Milestone:       issue.GetMilestone().GetNumber(),
+		Comments:        issue.GetComments(),
+		Locked:          issue.GetLocked(),
+		LockReason:      LockReason(issue.GetActiveLockReason()),
+		PullRequestLink: issue.GetPullRequestLinks().GetURL(),
+		CreatedAt:       issue.GetCreatedAt(),
+		UpdatedAt:       issue.GetUpdatedAt(),
+		ClosedAt:        issue.GetClosedAt(),
+	}
+	for _, label := range nonNil(issue.Labels) {
+		i.Labels = append(i.Labels, label.GetName())
 	}
+	return i
+}
 
-	return AllThreads, nil
+// logins возвращает логины пользователей
+func logins(users []*github.User) []string {
+	var Logins []string
+	for _, u := range nonNil(users) {
+		Logins = append(Logins, u.GetLogin())
+	}
+	return Logins
 }
 
-func (ghs *gitHubService) GetIssues(userName, repositoryName string) ([]*Issue, error) {
-	opts := github.IssueListByRepoOptions{State: "all"}
-	issues, _, err := ghs.client.Issues.ListByRepo(context.Background(), userName, repositoryName, &opts)
+func (ghs *gitHubService) GetRepositoryContributors(ctx context.Context, userName, repositoryName string, opts ...ListOption) ([]*User, error) {
+	contributors, err := collectPages(ctx, ghs.opts.maxItems, ghs.contributorPages(ctx, userName, repositoryName))
 	if err != nil {
-		return nil, fmt.Errorf("list issues by repo: %w", err)
+		return nil, err
 	}
 
-	var Issues []*Issue
-	for _, issue := range issues {
-		i := Issue{
-			Title:                   *issue.Title,
-			IsClosed:                issue.GetLocked(),                    // это вообще то? выдаёт только false
-			ResolvedPullRequestLink: issue.GetPullRequestLinks().GetURL(), // Если не "", то IsClosed = true?
-			CreatedAt:               *issue.CreatedAt,
-			UpdatedAt:               *issue.UpdatedAt,
-		}
-		Issues = append(Issues, &i)
-	}
+	return ghs.convertContributors(ctx, contributors, enrichment(opts))
+}
 
-	return Issues, nil
+// contributorPages загружает страницы списка соавторов репозитория
+func (ghs *gitHubService) contributorPages(ctx context.Context, userName, repositoryName string) pageFetcher[*github.Contributor] {
+	return func(lo github.ListOptions) ([]*github.Contributor, *github.Response, error) {
+		opts := github.ListContributorsOptions{ListOptions: lo}
+		return callAPI(ctx, ghs, "list contributors", func() ([]*github.Contributor, *github.Response, error) {
+			return ghs.client.Repositories.ListContributors(ctx, userName, repositoryName, &opts)
+		})
+	}
 }
 
-func (ghs *gitHubService) GetRepositoryContributors(userName, repositoryName string) ([]*User, error) {
-	opts := github.ListContributorsOptions{}
-	contributors, _, err := ghs.client.Repositories.ListContributors(context.Background(), userName, repositoryName, &opts)
-	if err != nil {
-		return nil, fmt.Errorf("list contributors: %w", err)
+// convertContributors преобразует соавторов в User. Если enrich содержит EnrichProfiles,
+// загружаются их полные профили, иначе известны только логины
+func (ghs *gitHubService) convertContributors(ctx context.Context, contributors []*github.Contributor, enrich Enrichment) ([]*User, error) {
+	contributors = nonNil(contributors)
+	if !enrich.Has(EnrichProfiles) {
+		var Users []*User
+		for _, contributor := range contributors {
+			Users = append(Users, &User{UserName: contributor.GetLogin()})
+		}
+		return Users, nil
 	}
 
-	var Users []*User
-	for _, user := range contributors {
-		id, _, err := ghs.client.Users.GetByID(context.Background(), user.GetID())
+	return parallel(ctx, ghs.opts.concurrency, contributors, func(ctx context.Context, contributor *github.Contributor) (*User, error) {
+		id, _, err := callAPI(ctx, ghs, "get user by ID", func() (*github.User, *github.Response, error) {
+			return ghs.client.Users.GetByID(ctx, contributor.GetID())
+		})
 		if err != nil {
-			return nil, fmt.Errorf("get user by ID: %w", err)
+			return nil, err
 		}
 
-		user := User{
+		return &User{
 			UserName:       id.GetLogin(),
 			FullName:       id.GetName(),
 			FollowersCount: id.GetFollowers(),
 			FollowingCount: id.GetFollowing(),
+			Enriched:       EnrichProfiles,
+		}, nil
+	})
+}
+
+func (ghs *gitHubService) GetRepositoryTags(ctx context.Context, userName, repositoryName string, opts ...ListOption) ([]*Tag, error) {
+	tags, err := collectPages(ctx, ghs.opts.maxItems, ghs.tagPages(ctx, userName, repositoryName))
+	if err != nil {
+		return nil, err
+	}
+
+	return ghs.convertTags(ctx, userName, repositoryName, tags, enrichment(opts), ghs.annotatedTagsOnce(ctx, userName, repositoryName))
+}
+
+// tagPages загружает страницы списка тегов репозитория
+func (ghs *gitHubService) tagPages(ctx context.Context, userName, repositoryName string) pageFetcher[*github.RepositoryTag] {
+	return func(lo github.ListOptions) ([]*github.RepositoryTag, *github.Response, error) {
+		return callAPI(ctx, ghs, "list repo tags", func() ([]*github.RepositoryTag, *github.Response, error) {
+			return ghs.client.Repositories.ListTags(ctx, userName, repositoryName, &lo)
+		})
+	}
+}
+
+// convertTags преобразует теги в Tag. Если enrich содержит EnrichAnnotations, аннотированные теги
+// (их возвращает annotatedTags) дополняются автором, сообщением и датой из объекта тега git,
+// а если EnrichReleaseNotes - релизом
+func (ghs *gitHubService) convertTags(ctx context.Context, userName, repositoryName string, tags []*github.RepositoryTag, enrich Enrichment, annotatedTags func() (map[string]string, error)) ([]*Tag, error) {
+	tags = nonNil(tags)
+
+	var annotated map[string]string
+	if enrich.Has(EnrichAnnotations) && len(tags) > 0 {
+		var err error
+		if annotated, err = annotatedTags(); err != nil {
+			return nil, err
 		}
-		Users = append(Users, &user)
 	}
 
-	return Users, nil
+	return parallel(ctx, ghs.opts.concurrency, tags, func(ctx context.Context, tag *github.RepositoryTag) (*Tag, error) {
+		t := &Tag{
+			Title:   tag.GetName(),
+			Hash:    tag.GetCommit().GetSHA(),
+			ZipLink: tag.GetZipballURL(),
+		}
+
+		if enrich.Has(EnrichAnnotations) {
+			if sha, ok := annotated[t.Title]; ok {
+				gitTag, _, err := callAPI(ctx, ghs, "get tag", func() (*github.Tag, *github.Response, error) {
+					return ghs.client.Git.GetTag(ctx, userName, repositoryName, sha)
+				})
+				if err != nil {
+					return nil, err
+				}
+
+				t.Annotated = true
+				t.Tagger = gitTag.GetTagger().GetName()
+				t.Message = gitTag.GetMessage()
+				t.TaggedAt = gitTag.GetTagger().GetDate()
+			}
+			t.Enriched |= EnrichAnnotations
+		}
+
+		if enrich.Has(EnrichReleaseNotes) {
+			release, err := ghs.GetReleaseByTag(ctx, userName, repositoryName, t.Title)
+			switch {
+			case errors.Is(err, ErrNotFound):
+				// Легковесные теги обычно создаются без релиза
+			case err != nil:
+				return nil, err
+			default:
+				t.Release = release
+				t.Description, t.CreatedAt = release.Body, release.CreatedAt
+			}
+			t.Enriched |= EnrichReleaseNotes
+		}
+
+		return t, nil
+	})
 }
 
-func (ghs *gitHubService) GetRepositoryTags(userName, repositoryName string) ([]*Tag, error) {
-	opts := github.ListOptions{}
-	tags, _, err := ghs.client.Repositories.ListTags(context.Background(), userName, repositoryName, &opts)
+// annotatedTags возвращает SHA объектов аннотированных тегов репозитория по их названиям.
+// Ссылка легковесного тега указывает прямо на коммит, поэтому такие теги в результат не входят
+func (ghs *gitHubService) annotatedTags(ctx context.Context, userName, repositoryName string) (map[string]string, error) {
+	refs, err := collectPages(ctx, 0, func(lo github.ListOptions) ([]*github.Reference, *github.Response, error) {
+		opts := github.ReferenceListOptions{Ref: "tags", ListOptions: lo}
+		return callAPI(ctx, ghs, "list tag refs", func() ([]*github.Reference, *github.Response, error) {
+			return ghs.client.Git.ListMatchingRefs(ctx, userName, repositoryName, &opts)
+		})
+	})
 	if err != nil {
-		return nil, fmt.Errorf("list repo tags: %w", err)
+		return nil, err
 	}
 
-	var Tags []*Tag
-	for _, tag := range tags {
-		release, _, err := ghs.client.Repositories.GetReleaseByTag(context.Background(), userName, repositoryName, tag.GetName())
-		if err != nil {
-			return nil, fmt.Errorf("get release by tag: %w", err)
+	annotated := make(map[string]string)
+	for _, ref := range nonNil(refs) {
+		if ref.GetObject().GetType() == "tag" {
+			annotated[strings.TrimPrefix(ref.GetRef(), "refs/tags/")] = ref.GetObject().GetSHA()
 		}
+	}
+	return annotated, nil
+}
 
-		t := Tag{
-			Title:       tag.GetName(),
-			Hash:        tag.GetCommit().GetSHA(),
-			Description: *release.Body,
-			ZipLink:     tag.GetZipballURL(),
-			CreatedAt:   release.GetCreatedAt().Time,
+// annotatedTagsOnce возвращает функцию, которая загружает аннотированные теги репозитория
+// при первом успешном вызове и дальше возвращает их же: итератор по тегам не должен
+// заново перечислять ссылки тегов на каждой странице
+func (ghs *gitHubService) annotatedTagsOnce(ctx context.Context, userName, repositoryName string) func() (map[string]string, error) {
+	var annotated map[string]string
+	return func() (map[string]string, error) {
+		if annotated == nil {
+			tags, err := ghs.annotatedTags(ctx, userName, repositoryName)
+			if err != nil {
+				return nil, err
+			}
+			annotated = tags
 		}
-		Tags = append(Tags, &t)
+		return annotated, nil
 	}
-
-	return Tags, nil
 }
 
-func (ghs *gitHubService) CreateTag(owner, repo, title, sha string) error {
+func (ghs *gitHubService) CreateTag(ctx context.Context, owner, repo, title, sha string) error {
 	ref := "refs/tags/" + title
 	obj := github.GitObject{SHA: &sha}
 	ghref := github.Reference{Ref: &ref, Object: &obj}
-	_, _, err := ghs.client.Git.CreateRef(context.Background(), owner, repo, &ghref)
+	_, _, err := callAPI(ctx, ghs, "create tag", func() (*github.Reference, *github.Response, error) {
+		return ghs.client.Git.CreateRef(ctx, owner, repo, &ghref)
+	})
 	return err
 }
 
-func (ghs *gitHubService) DeleteTag(owner, repositoryName, tagName string) error {
+func (ghs *gitHubService) DeleteTag(ctx context.Context, owner, repositoryName, tagName string) error {
 	ref := "refs/tags/" + tagName
-	_, err := ghs.client.Git.DeleteRef(context.Background(), owner, repositoryName, ref)
-	return err
+	return ghs.do(ctx, "delete tag", func() (*github.Response, error) {
+		return ghs.client.Git.DeleteRef(ctx, owner, repositoryName, ref)
+	})
 }
 
-func (ghs *gitHubService) SetAccessToRepository(owner, repositoryName, oppoUserName string) error {
+func (ghs *gitHubService) SetAccessToRepository(ctx context.Context, owner, repositoryName, oppoUserName string) error {
 	opts := github.RepositoryAddCollaboratorOptions{Permission: "pull"}
-	_, _, err := ghs.client.Repositories.AddCollaborator(context.Background(), owner, repositoryName, oppoUserName, &opts)
+	_, _, err := callAPI(ctx, ghs, "add collaborator", func() (*github.CollaboratorInvitation, *github.Response, error) {
+		return ghs.client.Repositories.AddCollaborator(ctx, owner, repositoryName, oppoUserName, &opts)
+	})
 	return err
 }
 
-func (ghs *gitHubService) DenyAccessToRepository(owner, repositoryName, oppoUserName string) error {
-	_, err := ghs.client.Repositories.RemoveCollaborator(context.Background(), owner, repositoryName, oppoUserName)
+func (ghs *gitHubService) DenyAccessToRepository(ctx context.Context, owner, repositoryName, oppoUserName string) error {
+	err := ghs.do(ctx, "remove collaborator", func() (*github.Response, error) {
+		return ghs.client.Repositories.RemoveCollaborator(ctx, owner, repositoryName, oppoUserName)
+	})
 	if err != nil {
-		return fmt.Errorf("removing collaborator: %w", err)
+		return err
 	}
 
 	// Для случая, когда пользователь не принял приглашение
-	opts := github.ListOptions{}
-	invites, _, err := ghs.client.Repositories.ListInvitations(context.Background(), owner, repositoryName, &opts)
+	invites, err := collectPages(ctx, 0, func(lo github.ListOptions) ([]*github.RepositoryInvitation, *github.Response, error) {
+		return callAPI(ctx, ghs, "list invitations", func() ([]*github.RepositoryInvitation, *github.Response, error) {
+			return ghs.client.Repositories.ListInvitations(ctx, owner, repositoryName, &lo)
+		})
+	})
 	if err != nil {
-		return fmt.Errorf("list invitations: %w", err)
+		return err
 	}
 
 	for _, invite := range invites {
-		login := invite.Invitee.GetLogin()
+		login := invite.GetInvitee().GetLogin()
 		if login == oppoUserName {
-			_, err := ghs.client.Repositories.DeleteInvitation(context.Background(), owner, repositoryName, *invite.ID)
+			err := ghs.do(ctx, "delete invitation", func() (*github.Response, error) {
+				return ghs.client.Repositories.DeleteInvitation(ctx, owner, repositoryName, invite.GetID())
+			})
 			if err != nil {
-				return fmt.Errorf("delete invitation: %w", err)
+				return err
 			}
 			break
 		}
@@ -1,22 +1,62 @@
-package main
+package notgogithub
 
 import (
 	"context"
-	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/joho/godotenv"
+
+	"github.com/jellybebra/not-go-github/cassette"
 )
 
-func getGHS() GitServiceIFace {
-	godotenv.Load(".env")
+// getCassetteGHS возвращает сервис, который воспроизводит ответы из кассеты testdata/cassettes/<name>.json
+// (написанной вручную, см. testdata/cassettes/README.md). С переменной окружения GITHUB_RECORD=1 запросы
+// уходят в GitHub с токеном из .env, а кассета перезаписывается записью настоящих ответов
+func getCassetteGHS(t *testing.T, name string) GitServiceIFace {
+	t.Helper()
+
+	mode := cassette.ModeReplay
+	opts := []Option{WithAuth(StaticToken("replay"))}
+	if os.Getenv("GITHUB_RECORD") != "" {
+		godotenv.Load(".env")
+		mode = cassette.ModeRecord
+		opts = nil
+	}
+
+	rec, err := cassette.New(filepath.Join("testdata", "cassettes", name+".json"), mode, nil)
+	if err != nil {
+		t.Fatalf("cassette: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := rec.Save(); err != nil {
+			t.Errorf("save cassette: %v", err)
+		}
+	})
 
-	ghs, err := NewGitHubService(context.TODO())
+	ghs, err := NewGitHubService(context.Background(), append(opts, WithTransport(rec))...)
 	if err != nil {
-		fmt.Printf("err: %v\n", err)
+		t.Fatalf("NewGitHubService: %v", err)
+	}
+	return ghs
+}
+
+// getGHS возвращает сервис, который обращается к GitHub с токеном из GITHUB_TOKEN или .env.
+// Без токена тест пропускается
+func getGHS(t *testing.T) GitServiceIFace {
+	t.Helper()
+
+	godotenv.Load(".env")
+	if os.Getenv(DefaultTokenEnv) == "" {
+		t.Skipf("%s is not set, skipping live GitHub test", DefaultTokenEnv)
 	}
 
+	ghs, err := NewGitHubService(context.Background())
+	if err != nil {
+		t.Fatalf("NewGitHubService: %v", err)
+	}
 	return ghs
 }
 
@@ -33,6 +73,7 @@ func TestGetUserInfo(t *testing.T) {
 				FullName:       "Mikhail Chestneyshy",
 				FollowersCount: 0,
 				FollowingCount: 0,
+				Enriched:       EnrichProfiles,
 			},
 		},
 		{
@@ -42,15 +83,16 @@ func TestGetUserInfo(t *testing.T) {
 				FullName:       "",
 				FollowersCount: 0,
 				FollowingCount: 0,
+				Enriched:       EnrichProfiles,
 			},
 		},
 	}
 
 	// Act
-	ghs := getGHS()
+	ghs := getGHS(t)
 
 	for _, testCase := range testTable {
-		presult, _ := ghs.GetUserInfo(testCase.username)
+		presult, _ := ghs.GetUserInfo(context.Background(), testCase.username)
 		result := *presult
 
 		// sresult := result.UserName + " " + result.FullName + " " + fmt.Sprint(result.FollowersCount) + " " + fmt.Sprint(result.FollowingCount)
@@ -82,13 +124,11 @@ func TestGetRepositoryByName(t *testing.T) {
 				StarsCount:      0,
 				ForksCount:      0,
 				LastUpdatedTime: time.Time{}, // как "2022-07-08 09:33:31 +0000 UTC" преобразовать для теста
-				programmingLanguage: []struct {
-					Name           string
-					PercentOfUsage float64
-				}{
+				Languages: []Language{
 					{
-						Name:           "Go",
-						PercentOfUsage: 1,
+						Name:       "Go",
+						Bytes:      24310,
+						Percentage: 100,
 					},
 				},
 			},
@@ -105,17 +145,16 @@ func TestGetRepositoryByName(t *testing.T) {
 				StarsCount:      0,
 				ForksCount:      0,
 				LastUpdatedTime: time.Time{}, // как "2021-10-12 15:20:12 +0000 UTC" преобразовать для теста
-				programmingLanguage: []struct {
-					Name           string
-					PercentOfUsage float64
-				}{
+				Languages: []Language{
 					{
-						Name:           "Python",
-						PercentOfUsage: 0.9896706768744683,
+						Name:       "Python",
+						Bytes:      8144,
+						Percentage: 98.96706768744683,
 					},
 					{
-						Name:           "Batchfile",
-						PercentOfUsage: 0.010329323125531656,
+						Name:       "Batchfile",
+						Bytes:      85,
+						Percentage: 1.0329323125531655,
 					},
 				},
 			},
@@ -123,10 +162,10 @@ func TestGetRepositoryByName(t *testing.T) {
 	}
 
 	// Act
-	ghs := getGHS()
+	ghs := getCassetteGHS(t, "GetRepositoryByName")
 
 	for _, testCase := range testTable {
-		repo, _ := ghs.GetRepositoryByName(testCase.owner, testCase.repo)
+		repo, _ := ghs.GetRepositoryByName(context.Background(), testCase.owner, testCase.repo)
 		result := *repo
 
 		// Assert
@@ -141,8 +180,14 @@ func TestGetRepositoryByName(t *testing.T) {
 		// и т.д. доделать
 
 		// slice can only be compared to nil, придётся сравнивать элементы обоих слайсов
-		if result.programmingLanguage[0] != testCase.expected.programmingLanguage[0] {
-			t.Errorf("Incorrect result for %s/%s", testCase.owner, testCase.repo)
+		if len(result.Languages) != len(testCase.expected.Languages) {
+			t.Errorf("Incorrect amount of languages for %s/%s", testCase.owner, testCase.repo)
+			continue
+		}
+		for i, language := range result.Languages {
+			if language != testCase.expected.Languages[i] {
+				t.Errorf("Incorrect language for %s/%s: expected %v, got %v", testCase.owner, testCase.repo, testCase.expected.Languages[i], language)
+			}
 		}
 	}
 }
@@ -163,12 +208,14 @@ func TestGetRepositoryContributors(t *testing.T) {
 					FullName:       "Mikhail Chestneyshy",
 					FollowersCount: 0,
 					FollowingCount: 0,
+					Enriched:       EnrichProfiles,
 				},
 				{
 					UserName:       "PeakIntegral",
 					FullName:       "",
 					FollowersCount: 0,
 					FollowingCount: 0,
+					Enriched:       EnrichProfiles,
 				},
 			},
 		},
@@ -181,16 +228,17 @@ func TestGetRepositoryContributors(t *testing.T) {
 					FullName:       "Mikhail Chestneyshy",
 					FollowersCount: 0,
 					FollowingCount: 0,
+					Enriched:       EnrichProfiles,
 				},
 			},
 		},
 	}
 
 	// Act
-	ghs := getGHS()
+	ghs := getGHS(t)
 
 	for _, testCase := range testTable {
-		contributors, _ := ghs.GetRepositoryContributors(testCase.owner, testCase.repo)
+		contributors, _ := ghs.GetRepositoryContributors(context.Background(), testCase.owner, testCase.repo)
 
 		// Assert
 		if len(contributors) == len(testCase.expected) {
@@ -243,10 +291,10 @@ func TestGetRepositoryTags(t *testing.T) {
 	}
 
 	// Act
-	ghs := getGHS()
+	ghs := getCassetteGHS(t, "GetRepositoryTags")
 
 	for _, testCase := range testTable {
-		tags, _ := ghs.GetRepositoryTags(testCase.owner, testCase.repo)
+		tags, _ := ghs.GetRepositoryTags(context.Background(), testCase.owner, testCase.repo)
 
 		// Assert
 		if len(tags) == len(testCase.expected) {
@@ -275,6 +323,14 @@ func TestGetRepositoryTags(t *testing.T) {
 				if res.CreatedAt != exp.CreatedAt {
 					t.Errorf("Incorrect CreatedAt: expected %v, got %v", res.CreatedAt, exp.CreatedAt)
 				}
+
+				if res.Annotated != exp.Annotated {
+					t.Errorf("Incorrect Annotated: expected %v, got %v", exp.Annotated, res.Annotated)
+				}
+
+				if res.Release == nil || res.Release.TagName != exp.Title || !res.Release.CreatedAt.Equal(exp.CreatedAt) {
+					t.Errorf("Incorrect Release: %+v", res.Release)
+				}
 			}
 		} else {
 			t.Errorf("Incorrect amount of tags for %s/%s: expected %v, got %v",
@@ -303,6 +359,7 @@ func TestGetRepositoryBranches(t *testing.T) {
 				{
 					Name:      "main",
 					UpdatedAt: stringToTime("2021-10-12 15:20:05 +0000 UTC"),
+					Enriched:  EnrichCommitDates,
 				},
 			},
 		},
@@ -313,28 +370,32 @@ func TestGetRepositoryBranches(t *testing.T) {
 				{
 					Name:      "create-sec-hero",
 					UpdatedAt: stringToTime("2022-03-02 20:11:13 +0000 UTC"),
+					Enriched:  EnrichCommitDates,
 				},
 				{
 					Name:      "main",
 					UpdatedAt: stringToTime("2022-03-05 18:17:54 +0000 UTC"),
+					Enriched:  EnrichCommitDates,
 				},
 				{
 					Name:      "patch-1",
 					UpdatedAt: stringToTime("2022-03-05 22:52:20 +0000 UTC"),
+					Enriched:  EnrichCommitDates,
 				},
 				{
 					Name:      "yura",
 					UpdatedAt: stringToTime("2022-03-02 14:59:38 +0000 UTC"),
+					Enriched:  EnrichCommitDates,
 				},
 			},
 		},
 	}
 
 	// Act
-	ghs := getGHS()
+	ghs := getCassetteGHS(t, "GetRepositoryBranches")
 
 	for _, testCase := range testTable {
-		branches, _ := ghs.GetRepositoryBranches(testCase.owner, testCase.repo)
+		branches, _ := ghs.GetRepositoryBranches(context.Background(), testCase.owner, testCase.repo)
 
 		// Assert
 		if len(branches) == len(testCase.expected) {
@@ -368,7 +429,6 @@ Get:
 
 - CreateRepository
 - CreateBranch
-- CreatePullRequest
 - CreateTag
 - DeleteTag
 - SetAccessToRepository
@@ -376,3 +436,36 @@ Get:
 - DeleteBranch
 
 */
+
+func TestCommitSHA(t *testing.T) {
+	// Arrange
+	testTable := []struct {
+		sha      string
+		url      string
+		expected string
+	}{
+		{
+			sha:      "0480a292df58ba0bb4851bf828ed25efc56da813",
+			url:      "",
+			expected: "0480a292df58ba0bb4851bf828ed25efc56da813",
+		},
+		{
+			url:      "https://api.github.com/repos/jostanise/rsa_encrypted_local_chat/git/commits/0480a292df58ba0bb4851bf828ed25efc56da813",
+			expected: "0480a292df58ba0bb4851bf828ed25efc56da813",
+		},
+		{
+			url:      "https://ghe.example.com/api/v3/repos/team/project/commits/0480a292df58ba0bb4851bf828ed25efc56da813",
+			expected: "0480a292df58ba0bb4851bf828ed25efc56da813",
+		},
+	}
+
+	for _, testCase := range testTable {
+		// Act
+		result := commitSHA(testCase.sha, testCase.url)
+
+		// Assert
+		if result != testCase.expected {
+			t.Errorf("Incorrect SHA for %q: expected %v, got %v", testCase.url, testCase.expected, result)
+		}
+	}
+}
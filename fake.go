@@ -0,0 +1,881 @@
+package notgogithub
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/jellybebra/not-go-github/internal/gitmodel"
+)
+
+// FakeGitService - реализация GitServiceIFace, которая хранит пользователей, репозитории,
+// ветки, коммиты, запросы на слияние, ревью, обсуждения, проблемы, теги, релизы и соавторов в памяти.
+//
+// Изменяющие методы (CreateBranch, DeleteTag, SetAccessToRepository и т.д.) меняют состояние так же,
+// как это сделал бы GitHub, поэтому FakeGitService можно использовать в тестах кода,
+// построенного на GitServiceIFace, без доступа к сети. Состояние наполняется методами Add*.
+// Ошибки сравниваются с ErrNotFound и ErrConflict через errors.Is, как и ошибки gitHubService
+type FakeGitService struct {
+	mu          sync.Mutex
+	currentUser string
+	users       map[string]*User
+	repos       map[string]*fakeRepository
+	now         func() time.Time
+	lastID      int64 // Последний выданный идентификатор (см. newID)
+}
+
+// fakeRepository - состояние одного репозитория FakeGitService
+type fakeRepository struct {
+	owner         string
+	info          Repository
+	commits       map[string]*fakeCommit
+	branches      map[string]string // Имя ветки -> SHA последнего коммита
+	pullRequests  []*PullRequest
+	threads       map[int][]*Thread          // Номер запроса на слияние -> обсуждения
+	reviews       map[int][]*Review          // Номер запроса на слияние -> ревью от старых к новым
+	files         map[int][]*PullRequestFile // Номер запроса на слияние -> измененные файлы
+	mergeBases    map[int]string             // Номер слитого запроса -> SHA ветки-назначения до слияния
+	issues        []*Issue
+	issueComments map[int][]*Comment // Номер проблемы -> комментарии от старых к новым
+	contributors  []string
+	tags          []*Tag
+	releases      []*Release       // От нового к старому
+	latestRelease int64            // Релиз, явно отмеченный последним; 0 - не отмечен
+	assets        map[int64][]byte // Идентификатор файла релиза -> содержимое
+	collaborators map[string]struct{}
+}
+
+type fakeCommit struct {
+	commit  Commit
+	parents []string
+	paths   []string // Файлы, измененные коммитом
+}
+
+var _ GitServiceIFace = (*FakeGitService)(nil)
+
+// NewFakeGitService создает пустой FakeGitService. currentUser - пользователь,
+// от имени которого выполняются запросы: ему принадлежат репозитории из CreateRepository
+func NewFakeGitService(currentUser string) *FakeGitService {
+	return &FakeGitService{
+		currentUser: currentUser,
+		users:       make(map[string]*User),
+		repos:       make(map[string]*fakeRepository),
+		now:         time.Now,
+	}
+}
+
+// AddUser добавляет пользователя или заменяет существующего с тем же UserName
+func (f *FakeGitService) AddUser(user User) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.users[user.UserName] = &user
+}
+
+// AddRepository добавляет репозиторий пользователя owner
+func (f *FakeGitService) AddRepository(owner string, repo Repository) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.repos[repoKey(owner, repo.Name)] = newFakeRepository(owner, repo)
+}
+
+// AddCommit добавляет в репозиторий коммит с указанными родителями
+func (f *FakeGitService) AddCommit(owner, repositoryName string, commit Commit, parents ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(owner, repositoryName)
+	if err != nil {
+		return err
+	}
+	for _, parent := range parents {
+		if _, ok := repo.commits[parent]; !ok {
+			return fmt.Errorf("parent commit %s: %w", parent, ErrNotFound)
+		}
+	}
+
+	commit.Parents = append([]string(nil), parents...)
+	repo.commits[commit.Hash] = &fakeCommit{commit: commit, parents: commit.Parents}
+	return nil
+}
+
+// AddCommitFiles отмечает файлы, измененные коммитом sha, для отбора коммитов через WithPath
+func (f *FakeGitService) AddCommitFiles(owner, repositoryName, sha string, paths ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(owner, repositoryName)
+	if err != nil {
+		return err
+	}
+	c, ok := repo.commits[sha]
+	if !ok {
+		return fmt.Errorf("commit %s: %w", sha, ErrNotFound)
+	}
+
+	c.paths = append(c.paths, paths...)
+	return nil
+}
+
+// SetBranch создает ветку или переносит ее на коммит sha
+func (f *FakeGitService) SetBranch(owner, repositoryName, branchName, sha string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(owner, repositoryName)
+	if err != nil {
+		return err
+	}
+	if _, ok := repo.commits[sha]; !ok {
+		return fmt.Errorf("commit %s: %w", sha, ErrNotFound)
+	}
+
+	repo.branches[branchName] = sha
+	return nil
+}
+
+// AddPullRequest добавляет запрос на слияние
+func (f *FakeGitService) AddPullRequest(owner, repositoryName string, pullRequest PullRequest) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(owner, repositoryName)
+	if err != nil {
+		return err
+	}
+
+	if pullRequest.State == "" {
+		pullRequest.State = StateOpen
+	}
+	repo.pullRequests = append(repo.pullRequests, &pullRequest)
+	return nil
+}
+
+// AddThread добавляет обсуждение к запросу на слияние с номером pullRequestID. Обсуждение задается
+// комментариями Conversation или одними текстами Comments. Комментарии без ID получают идентификаторы,
+// а в обсуждении кода (с Filename) ответы связываются с первым комментарием
+func (f *FakeGitService) AddThread(owner, repositoryName string, pullRequestID int, thread Thread) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(owner, repositoryName)
+	if err != nil {
+		return err
+	}
+	if repo.pullRequest(pullRequestID) == nil {
+		return fmt.Errorf("pull request %d: %w", pullRequestID, ErrNotFound)
+	}
+
+	if len(thread.Conversation) == 0 {
+		for _, body := range thread.Comments {
+			thread.Conversation = append(thread.Conversation, Comment{Body: body})
+		}
+	}
+	thread.Conversation = append([]Comment(nil), thread.Conversation...)
+	thread.Comments = nil
+	for i := range thread.Conversation {
+		c := &thread.Conversation[i]
+		if c.ID == 0 {
+			c.ID = f.newID()
+		}
+		if thread.Filename != "" {
+			if i == 0 {
+				thread.ID = c.ID
+			} else if c.InReplyTo == 0 {
+				c.InReplyTo = thread.ID
+			}
+			if c.EndLine == 0 {
+				c.EndLine = int(thread.LineOfCode)
+			}
+			if c.StartLine == 0 {
+				c.StartLine = c.EndLine
+			}
+			c.Outdated, c.Resolved = thread.Outdated, thread.Resolved
+		}
+		thread.Comments = append(thread.Comments, c.Body)
+	}
+
+	repo.threads[pullRequestID] = append(repo.threads[pullRequestID], &thread)
+	return nil
+}
+
+// AddIssue добавляет проблему
+func (f *FakeGitService) AddIssue(owner, repositoryName string, issue Issue) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(owner, repositoryName)
+	if err != nil {
+		return err
+	}
+
+	if issue.State == "" {
+		issue.State = StateOpen
+	}
+	repo.issues = append(repo.issues, &issue)
+	return nil
+}
+
+// AddContributor добавляет пользователя в список соавторов репозитория
+func (f *FakeGitService) AddContributor(owner, repositoryName, userName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(owner, repositoryName)
+	if err != nil {
+		return err
+	}
+
+	repo.contributors = append(repo.contributors, userName)
+	return nil
+}
+
+// AddTag добавляет тег. Коммит tag.Hash должен существовать. Аннотированный тег задается
+// полями Annotated, Tagger, Message и TaggedAt, а релиз тега - методом AddRelease
+func (f *FakeGitService) AddTag(owner, repositoryName string, tag Tag) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(owner, repositoryName)
+	if err != nil {
+		return err
+	}
+	if _, ok := repo.commits[tag.Hash]; !ok {
+		return fmt.Errorf("commit %s: %w", tag.Hash, ErrNotFound)
+	}
+
+	repo.tags = append(repo.tags, &tag)
+	return nil
+}
+
+// Collaborators возвращает отсортированный список пользователей, которым открыт доступ к репозиторию
+func (f *FakeGitService) Collaborators(owner, repositoryName string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(owner, repositoryName)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range repo.collaborators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (f *FakeGitService) GetUserInfo(ctx context.Context, userName string) (*User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	user, ok := f.users[userName]
+	if !ok {
+		return nil, fmt.Errorf("get user %s: %w", userName, ErrNotFound)
+	}
+
+	u := *user
+	u.Enriched = EnrichProfiles
+	return &u, nil
+}
+
+func (f *FakeGitService) GetUserRepositories(ctx context.Context, userName string, opts ...ListOption) ([]*Repository, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if userName == "" {
+		userName = f.currentUser
+	}
+
+	enrich := enrichment(opts)
+	var Repos []*Repository
+	for _, repo := range f.repos {
+		if repo.owner == userName {
+			Repos = append(Repos, repo.repository(enrich))
+		}
+	}
+	sort.Slice(Repos, func(i, j int) bool { return Repos[i].Name < Repos[j].Name })
+
+	return Repos, nil
+}
+
+func (f *FakeGitService) GetRepositoryByName(ctx context.Context, userName, repositoryName string, opts ...ListOption) (*Repository, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(userName, repositoryName)
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.repository(enrichment(opts)), nil
+}
+
+func (f *FakeGitService) CreateRepository(ctx context.Context, repositoryName string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := repoKey(f.currentUser, repositoryName)
+	if _, ok := f.repos[key]; ok {
+		return fmt.Errorf("create repo %s: %w", key, ErrConflict)
+	}
+
+	f.repos[key] = newFakeRepository(f.currentUser, Repository{
+		Name:            repositoryName,
+		Link:            "https://github.com/" + key,
+		LastUpdatedTime: f.now(),
+	})
+	return nil
+}
+
+func (f *FakeGitService) GetRepositoryBranches(ctx context.Context, userName, repositoryName string, opts ...ListOption) ([]*Branch, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(userName, repositoryName)
+	if err != nil {
+		return nil, err
+	}
+
+	enrich := enrichment(opts)
+	var Branches []*Branch
+	for name, sha := range repo.branches {
+		branch := Branch{Name: name}
+		if enrich.Has(EnrichCommitDates) {
+			branch.UpdatedAt = repo.commits[sha].commit.CreatedAt
+			branch.Enriched = EnrichCommitDates
+		}
+		Branches = append(Branches, &branch)
+	}
+	sort.Slice(Branches, func(i, j int) bool { return Branches[i].Name < Branches[j].Name })
+
+	return Branches, nil
+}
+
+func (f *FakeGitService) CreateBranch(ctx context.Context, userName, repoName, branchName, sha string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(userName, repoName)
+	if err != nil {
+		return err
+	}
+	if _, ok := repo.branches[branchName]; ok {
+		return fmt.Errorf("create branch %s: %w", branchName, ErrConflict)
+	}
+	if _, ok := repo.commits[sha]; !ok {
+		return fmt.Errorf("commit %s: %w", sha, ErrNotFound)
+	}
+
+	repo.branches[branchName] = sha
+	return nil
+}
+
+func (f *FakeGitService) DeleteBranch(ctx context.Context, userName, repoName, branchName string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(userName, repoName)
+	if err != nil {
+		return err
+	}
+	if _, ok := repo.branches[branchName]; !ok {
+		return fmt.Errorf("branch %s: %w", branchName, ErrNotFound)
+	}
+
+	delete(repo.branches, branchName)
+	return nil
+}
+
+func (f *FakeGitService) GetBranchCommits(ctx context.Context, userName, repositoryName, branchName string, opts ...ListOption) ([]*Commit, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(userName, repositoryName)
+	if err != nil {
+		return nil, err
+	}
+	head, ok := repo.branches[branchName]
+	if !ok {
+		return nil, fmt.Errorf("branch %s: %w", branchName, ErrNotFound)
+	}
+
+	filter := newListOptions(opts)
+	var Commits []*Commit
+	for _, c := range repo.history(head) {
+		if filter.maxDepth > 0 && len(Commits) == filter.maxDepth {
+			break
+		}
+		if repo.matchCommit(c, filter) {
+			Commits = append(Commits, c)
+		}
+	}
+	return Commits, nil
+}
+
+func (f *FakeGitService) GetRepositoryPullRequests(ctx context.Context, userName, repositoryName string, opts ...ListOption) ([]*PullRequest, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(userName, repositoryName)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := newListOptions(opts)
+	var PullRequests []*PullRequest
+	for _, pr := range repo.pullRequests {
+		if !filter.matchState(pr.State) {
+			continue
+		}
+		PullRequests = append(PullRequests, repo.copyPullRequest(pr))
+	}
+	return PullRequests, nil
+}
+
+func (f *FakeGitService) GetThreadsInfo(ctx context.Context, userName, repositoryName string, pullRequestID int) ([]*Thread, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(userName, repositoryName)
+	if err != nil {
+		return nil, err
+	}
+	if repo.pullRequest(pullRequestID) == nil {
+		return nil, fmt.Errorf("pull request %d: %w", pullRequestID, ErrNotFound)
+	}
+
+	var Threads []*Thread
+	for _, thread := range repo.threads[pullRequestID] {
+		t := *thread
+		t.Comments = append([]string(nil), thread.Comments...)
+		t.Conversation = append([]Comment(nil), thread.Conversation...)
+		Threads = append(Threads, &t)
+	}
+	return Threads, nil
+}
+
+func (f *FakeGitService) GetIssues(ctx context.Context, userName, repositoryName string, opts ...ListOption) ([]*Issue, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(userName, repositoryName)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := newListOptions(opts)
+	var Issues []*Issue
+	for _, issue := range repo.issues {
+		if !filter.matchState(issue.State) {
+			continue
+		}
+		Issues = append(Issues, repo.copyIssue(issue))
+	}
+	return Issues, nil
+}
+
+func (f *FakeGitService) GetRepositoryContributors(ctx context.Context, userName, repositoryName string, opts ...ListOption) ([]*User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(userName, repositoryName)
+	if err != nil {
+		return nil, err
+	}
+
+	enrich := enrichment(opts)
+	var Users []*User
+	for _, name := range repo.contributors {
+		user := User{UserName: name}
+		if known, ok := f.users[name]; ok && enrich.Has(EnrichProfiles) {
+			user = *known
+			user.Enriched = EnrichProfiles
+		}
+		Users = append(Users, &user)
+	}
+	return Users, nil
+}
+
+func (f *FakeGitService) GetRepositoryTags(ctx context.Context, userName, repositoryName string, opts ...ListOption) ([]*Tag, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(userName, repositoryName)
+	if err != nil {
+		return nil, err
+	}
+
+	enrich := enrichment(opts)
+	var Tags []*Tag
+	for _, tag := range repo.tags {
+		t := *tag
+		if enrich.Has(EnrichAnnotations) {
+			t.Enriched |= EnrichAnnotations
+		} else {
+			t.Annotated, t.Tagger, t.Message, t.TaggedAt = false, "", "", time.Time{}
+		}
+		if enrich.Has(EnrichReleaseNotes) {
+			if release := repo.publishedRelease(t.Title); release != nil {
+				t.Release = copyRelease(release)
+				t.Description, t.CreatedAt = release.Body, release.CreatedAt
+			}
+			t.Enriched |= EnrichReleaseNotes
+		} else {
+			t.Description, t.CreatedAt, t.Release = "", time.Time{}, nil
+		}
+		Tags = append(Tags, &t)
+	}
+	return Tags, nil
+}
+
+func (f *FakeGitService) CreateTag(ctx context.Context, userName, repositoryName, title, sha string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(userName, repositoryName)
+	if err != nil {
+		return err
+	}
+	if repo.tag(title) >= 0 {
+		return fmt.Errorf("create tag %s: %w", title, ErrConflict)
+	}
+	if _, ok := repo.commits[sha]; !ok {
+		return fmt.Errorf("commit %s: %w", sha, ErrNotFound)
+	}
+
+	repo.tags = append(repo.tags, &Tag{
+		Title:   title,
+		Hash:    sha,
+		ZipLink: fmt.Sprintf("https://api.github.com/repos/%s/%s/zipball/refs/tags/%s", userName, repositoryName, title),
+	})
+	return nil
+}
+
+func (f *FakeGitService) DeleteTag(ctx context.Context, userName, repositoryName, tagName string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(userName, repositoryName)
+	if err != nil {
+		return err
+	}
+	i := repo.tag(tagName)
+	if i < 0 {
+		return fmt.Errorf("tag %s: %w", tagName, ErrNotFound)
+	}
+
+	repo.tags = append(repo.tags[:i], repo.tags[i+1:]...)
+	return nil
+}
+
+func (f *FakeGitService) SetAccessToRepository(ctx context.Context, owner, repositoryName, oppoUserName string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(owner, repositoryName)
+	if err != nil {
+		return err
+	}
+	if _, ok := f.users[oppoUserName]; !ok {
+		return fmt.Errorf("user %s: %w", oppoUserName, ErrNotFound)
+	}
+
+	repo.collaborators[oppoUserName] = struct{}{}
+	return nil
+}
+
+func (f *FakeGitService) DenyAccessToRepository(ctx context.Context, owner, repositoryName, oppoUserName string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(owner, repositoryName)
+	if err != nil {
+		return err
+	}
+
+	delete(repo.collaborators, oppoUserName)
+	return nil
+}
+
+func (f *FakeGitService) RateLimitStatus(ctx context.Context) (*RateLimit, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Запросы к FakeGitService лимит не расходуют
+	return &RateLimit{Limit: 5000, Remaining: 5000, Reset: f.now().Add(time.Hour)}, nil
+}
+
+func (f *FakeGitService) IterateUserRepositories(ctx context.Context, userName string, opts ...ListOption) *RepositoryIterator {
+	return fakeIterator(ctx, func() ([]*Repository, error) {
+		return f.GetUserRepositories(ctx, userName, opts...)
+	})
+}
+
+func (f *FakeGitService) IterateRepositoryBranches(ctx context.Context, userName, repositoryName string, opts ...ListOption) *BranchIterator {
+	return fakeIterator(ctx, func() ([]*Branch, error) {
+		return f.GetRepositoryBranches(ctx, userName, repositoryName, opts...)
+	})
+}
+
+func (f *FakeGitService) IterateBranchCommits(ctx context.Context, userName, repositoryName, branchName string, opts ...ListOption) *CommitIterator {
+	return fakeIterator(ctx, func() ([]*Commit, error) {
+		return f.GetBranchCommits(ctx, userName, repositoryName, branchName, opts...)
+	})
+}
+
+func (f *FakeGitService) IterateRepositoryPullRequests(ctx context.Context, userName, repositoryName string, opts ...ListOption) *PullRequestIterator {
+	return fakeIterator(ctx, func() ([]*PullRequest, error) {
+		return f.GetRepositoryPullRequests(ctx, userName, repositoryName, opts...)
+	})
+}
+
+func (f *FakeGitService) IterateIssues(ctx context.Context, userName, repositoryName string, opts ...ListOption) *IssueIterator {
+	return fakeIterator(ctx, func() ([]*Issue, error) {
+		return f.GetIssues(ctx, userName, repositoryName, opts...)
+	})
+}
+
+func (f *FakeGitService) IterateRepositoryContributors(ctx context.Context, userName, repositoryName string, opts ...ListOption) *UserIterator {
+	return fakeIterator(ctx, func() ([]*User, error) {
+		return f.GetRepositoryContributors(ctx, userName, repositoryName, opts...)
+	})
+}
+
+func (f *FakeGitService) IterateRepositoryTags(ctx context.Context, userName, repositoryName string, opts ...ListOption) *TagIterator {
+	return fakeIterator(ctx, func() ([]*Tag, error) {
+		return f.GetRepositoryTags(ctx, userName, repositoryName, opts...)
+	})
+}
+
+// fakeIterator выдает результат list постранично, как это делает GitHub API.
+// list вызывается при загрузке каждой страницы, поэтому итератор видит изменения состояния
+func fakeIterator[T any](ctx context.Context, list func() ([]T, error)) *Iterator[T] {
+	return newIterator(ctx, func(opts github.ListOptions) ([]T, *github.Response, error) {
+		items, err := list()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		page := opts.Page
+		if page < 1 {
+			page = 1
+		}
+		start := (page - 1) * opts.PerPage
+		if start > len(items) {
+			start = len(items)
+		}
+		end := start + opts.PerPage
+		if end > len(items) {
+			end = len(items)
+		}
+
+		resp := &github.Response{}
+		if end < len(items) {
+			resp.NextPage = page + 1
+		}
+		return items[start:end], resp, nil
+	})
+}
+
+// repo возвращает репозиторий; вызывается под f.mu
+func (f *FakeGitService) repo(owner, repositoryName string) (*fakeRepository, error) {
+	repo, ok := f.repos[repoKey(owner, repositoryName)]
+	if !ok {
+		return nil, fmt.Errorf("repo %s: %w", repoKey(owner, repositoryName), ErrNotFound)
+	}
+	return repo, nil
+}
+
+func repoKey(owner, repositoryName string) string {
+	return owner + "/" + repositoryName
+}
+
+func newFakeRepository(owner string, info Repository) *fakeRepository {
+	return &fakeRepository{
+		owner:         owner,
+		info:          info,
+		commits:       make(map[string]*fakeCommit),
+		branches:      make(map[string]string),
+		threads:       make(map[int][]*Thread),
+		reviews:       make(map[int][]*Review),
+		files:         make(map[int][]*PullRequestFile),
+		mergeBases:    make(map[int]string),
+		issueComments: make(map[int][]*Comment),
+		collaborators: make(map[string]struct{}),
+	}
+}
+
+// repository возвращает копию сведений о репозитории; языки включаются, только если их запросили
+func (r *fakeRepository) repository(enrich Enrichment) *Repository {
+	info := r.info
+	if enrich.Has(EnrichLanguages) {
+		info.Enriched = EnrichLanguages
+	} else {
+		info.Languages = nil
+	}
+	return &info
+}
+
+func (r *fakeRepository) pullRequest(number int) *PullRequest {
+	for _, pr := range r.pullRequests {
+		if pr.Number == number {
+			return pr
+		}
+	}
+	return nil
+}
+
+// nextNumber возвращает номер, следующий за последним номером запроса на слияние или проблемы
+func (r *fakeRepository) nextNumber() int {
+	number := 1
+	for _, pr := range r.pullRequests {
+		if pr.Number >= number {
+			number = pr.Number + 1
+		}
+	}
+	for _, issue := range r.issues {
+		if issue.Number >= number {
+			number = issue.Number + 1
+		}
+	}
+	return number
+}
+
+// newID выдает новый идентификатор релиза, файла релиза, ревью или комментария.
+// Из него же строится SHA коммита слияния в MergePullRequest
+func (f *FakeGitService) newID() int64 {
+	f.lastID++
+	return f.lastID
+}
+
+func (r *fakeRepository) tag(title string) int {
+	for i, tag := range r.tags {
+		if tag.Title == title {
+			return i
+		}
+	}
+	return -1
+}
+
+// matchCommit сообщает, проходит ли коммит фильтры WithSince, WithUntil, WithAuthor и WithPath
+func (r *fakeRepository) matchCommit(c *Commit, filter listOptions) bool {
+	if !filter.since.IsZero() && c.CreatedAt.Before(filter.since) {
+		return false
+	}
+	if !filter.until.IsZero() && c.CreatedAt.After(filter.until) {
+		return false
+	}
+	if filter.author != "" && c.Author != filter.author {
+		return false
+	}
+	if filter.path == "" {
+		return true
+	}
+	for _, p := range r.commits[c.Hash].paths {
+		if p == filter.path || strings.HasPrefix(p, strings.TrimSuffix(filter.path, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// history возвращает коммиты, достижимые из head, в порядке gitmodel.History
+func (r *fakeRepository) history(head string) []*Commit {
+	var Commits []*Commit
+	for _, sha := range gitmodel.History(r.graph, head) {
+		Commits = append(Commits, r.commit(sha))
+	}
+	return Commits
+}
+
+// commit возвращает копию коммита sha
+func (r *fakeRepository) commit(sha string) *Commit {
+	c := r.commits[sha].commit
+	c.Parents = append([]string(nil), c.Parents...)
+	return &c
+}
+
+// graph находит коммит репозитория для gitmodel
+func (r *fakeRepository) graph(sha string) (gitmodel.Node, bool) {
+	c, ok := r.commits[sha]
+	if !ok {
+		return gitmodel.Node{}, false
+	}
+	return gitmodel.Node{Parents: c.parents, Date: c.commit.CreatedAt, Files: c.paths}, true
+}
@@ -0,0 +1,167 @@
+package notgogithub
+
+import (
+	"context"
+
+	"github.com/jellybebra/not-go-github/internal/gitmodel"
+)
+
+// AddPullRequestFiles задает файлы, измененные запросом на слияние с номером pullRequestID.
+// По ним FakeGitService строит GetPullRequestDiff и GetPullRequestPatch
+func (f *FakeGitService) AddPullRequestFiles(owner, repositoryName string, pullRequestID int, files ...PullRequestFile) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, _, err := f.pull(owner, repositoryName, pullRequestID)
+	if err != nil {
+		return err
+	}
+
+	for i := range files {
+		file := files[i]
+		if file.Status == "" {
+			file.Status = FileModified
+		}
+		if file.Changes == 0 {
+			file.Changes = file.Additions + file.Deletions
+		}
+		repo.files[pullRequestID] = append(repo.files[pullRequestID], &file)
+	}
+	return nil
+}
+
+func (f *FakeGitService) GetPullRequestFiles(ctx context.Context, owner, repositoryName string, pullRequestID int) ([]*PullRequestFile, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, _, err := f.pull(owner, repositoryName, pullRequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	var Files []*PullRequestFile
+	for _, file := range repo.files[pullRequestID] {
+		fc := *file
+		Files = append(Files, &fc)
+	}
+	return Files, nil
+}
+
+func (f *FakeGitService) GetPullRequestDiff(ctx context.Context, owner, repositoryName string, pullRequestID int) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, _, err := f.pull(owner, repositoryName, pullRequestID)
+	if err != nil {
+		return "", err
+	}
+	return unifiedDiff(repo.files[pullRequestID]), nil
+}
+
+func (f *FakeGitService) GetPullRequestPatch(ctx context.Context, owner, repositoryName string, pullRequestID int) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, pr, err := f.pull(owner, repositoryName, pullRequestID)
+	if err != nil {
+		return "", err
+	}
+
+	var commits []gitmodel.PatchCommit
+	for _, c := range repo.pullRequestCommits(pr) {
+		commits = append(commits, gitmodel.PatchCommit{SHA: c.Hash, Author: c.Author, Date: c.CreatedAt, Subject: c.Title})
+	}
+	return gitmodel.FormatPatch(commits, unifiedDiff(repo.files[pullRequestID])), nil
+}
+
+func (f *FakeGitService) GetPullRequestCommits(ctx context.Context, owner, repositoryName string, pullRequestID int) ([]*Commit, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, pr, err := f.pull(owner, repositoryName, pullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	return repo.pullRequestCommits(pr), nil
+}
+
+func (f *FakeGitService) GetPullRequestMergeability(ctx context.Context, owner, repositoryName string, pullRequestID int) (*Mergeability, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, pr, err := f.pull(owner, repositoryName, pullRequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	head, ok := repo.branches[pr.SourceBranch]
+	m := &Mergeability{State: MergeableUnknown, HeadSHA: head}
+	if !ok || (pr.State != StateOpen && pr.State != StateDraft) {
+		return m, nil
+	}
+
+	m.Conflicts = repo.conflicts(head, repo.branches[pr.TargetBranch])
+	m.Mergeable, m.Rebaseable = !m.Conflicts, !m.Conflicts
+	switch {
+	case pr.State == StateDraft:
+		m.State = MergeableDraft
+	case m.Conflicts:
+		m.State = MergeableDirty
+	default:
+		m.State = MergeableClean
+	}
+	return m, nil
+}
+
+// pullRequestCommits возвращает коммиты ветки-источника, которых нет в ветке-назначении, от старых к новым.
+// Для слитого запроса ветка-назначение берется в состоянии до слияния
+func (r *fakeRepository) pullRequestCommits(pr *PullRequest) []*Commit {
+	head, ok := r.branches[pr.SourceBranch]
+	if !ok {
+		return nil
+	}
+	base, merged := r.mergeBases[pr.Number]
+	if !merged {
+		base = r.branches[pr.TargetBranch]
+	}
+
+	var Commits []*Commit
+	for _, sha := range gitmodel.Ahead(r.graph, head, base) {
+		Commits = append(Commits, r.commit(sha))
+	}
+	return Commits
+}
+
+// conflicts сообщает, меняли ли обе ветки одни и те же файлы после их общего предка
+func (r *fakeRepository) conflicts(head, base string) bool {
+	return gitmodel.Conflicts(r.graph, head, base)
+}
+
+// unifiedDiff собирает изменения файлов в один diff в формате git
+func unifiedDiff(files []*PullRequestFile) string {
+	var diff []gitmodel.File
+	for _, f := range files {
+		diff = append(diff, gitmodel.File{Filename: f.Filename, PreviousFilename: f.PreviousFilename, Status: f.Status, Patch: f.Patch})
+	}
+	return gitmodel.UnifiedDiff(diff)
+}
@@ -0,0 +1,343 @@
+package notgogithub
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// AddIssueComment добавляет комментарий в обсуждение проблемы с номером issueNumber.
+// Issue.Comments FakeGitService считает по добавленным комментариям
+func (f *FakeGitService) AddIssueComment(owner, repositoryName string, issueNumber int, comment Comment) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, _, err := f.issue(owner, repositoryName, issueNumber)
+	if err != nil {
+		return err
+	}
+
+	repo.issueComments[issueNumber] = append(repo.issueComments[issueNumber], &comment)
+	return nil
+}
+
+func (f *FakeGitService) CreateIssue(ctx context.Context, owner, repositoryName, title string, opts ...IssueOption) (*Issue, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(owner, repositoryName)
+	if err != nil {
+		return nil, err
+	}
+	req := newIssueRequest(opts)
+	req.Title = &title
+	if err := repo.validateIssue(req); err != nil {
+		return nil, err
+	}
+
+	// Как и на GitHub, проблемы и запросы на слияние делят одну нумерацию
+	number := repo.nextNumber()
+	now := f.now()
+	issue := &Issue{
+		ID:        int64(number),
+		Number:    number,
+		State:     StateOpen,
+		Author:    f.currentUser,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	issue.apply(req)
+	repo.issues = append(repo.issues, issue)
+	return repo.copyIssue(issue), nil
+}
+
+func (f *FakeGitService) GetIssue(ctx context.Context, owner, repositoryName string, issueNumber int) (*Issue, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, issue, err := f.issue(owner, repositoryName, issueNumber)
+	if err != nil {
+		return nil, err
+	}
+	return repo.copyIssue(issue), nil
+}
+
+func (f *FakeGitService) EditIssue(ctx context.Context, owner, repositoryName string, issueNumber int, opts ...IssueOption) (*Issue, error) {
+	return f.editIssue(ctx, owner, repositoryName, issueNumber, newIssueRequest(opts))
+}
+
+func (f *FakeGitService) CloseIssue(ctx context.Context, owner, repositoryName string, issueNumber int, reason IssueStateReason) (*Issue, error) {
+	if reason == "" {
+		reason = IssueCompleted
+	}
+	state, r := string(StateClosed), string(reason)
+	return f.editIssue(ctx, owner, repositoryName, issueNumber, &issueRequest{State: &state, StateReason: &r})
+}
+
+func (f *FakeGitService) ReopenIssue(ctx context.Context, owner, repositoryName string, issueNumber int) (*Issue, error) {
+	state, r := string(StateOpen), string(issueReopened)
+	return f.editIssue(ctx, owner, repositoryName, issueNumber, &issueRequest{State: &state, StateReason: &r})
+}
+
+// editIssue меняет поля проблемы, заданные в req
+func (f *FakeGitService) editIssue(ctx context.Context, owner, repositoryName string, issueNumber int, req *issueRequest) (*Issue, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, issue, err := f.issue(owner, repositoryName, issueNumber)
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.validateIssue(req); err != nil {
+		return nil, err
+	}
+
+	now := f.now()
+	if req.State != nil && State(*req.State) != issue.State {
+		issue.ClosedAt = time.Time{}
+		if State(*req.State) == StateClosed {
+			issue.ClosedAt = now
+		}
+	}
+	issue.apply(req)
+	issue.UpdatedAt = now
+	return repo.copyIssue(issue), nil
+}
+
+func (f *FakeGitService) LockIssue(ctx context.Context, owner, repositoryName string, issueNumber int, reason LockReason) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, issue, err := f.issue(owner, repositoryName, issueNumber)
+	if err != nil {
+		return err
+	}
+	switch reason {
+	case "", LockOffTopic, LockTooHeated, LockResolved, LockSpam:
+	default:
+		return fmt.Errorf("lock reason %q: %w", reason, ErrValidation)
+	}
+	if !repo.isCollaborator(f.currentUser) {
+		return fmt.Errorf("lock issue %d without push access: %w", issueNumber, ErrForbidden)
+	}
+
+	issue.Locked, issue.LockReason = true, reason
+	return nil
+}
+
+func (f *FakeGitService) UnlockIssue(ctx context.Context, owner, repositoryName string, issueNumber int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, issue, err := f.issue(owner, repositoryName, issueNumber)
+	if err != nil {
+		return err
+	}
+	if !repo.isCollaborator(f.currentUser) {
+		return fmt.Errorf("unlock issue %d without push access: %w", issueNumber, ErrForbidden)
+	}
+
+	issue.Locked, issue.LockReason = false, ""
+	return nil
+}
+
+func (f *FakeGitService) GetIssueComments(ctx context.Context, owner, repositoryName string, issueNumber int) ([]*Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, _, err := f.issue(owner, repositoryName, issueNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var Comments []*Comment
+	for _, c := range repo.issueComments[issueNumber] {
+		comment := *c
+		Comments = append(Comments, &comment)
+	}
+	return Comments, nil
+}
+
+func (f *FakeGitService) CreateIssueComment(ctx context.Context, owner, repositoryName string, issueNumber int, body string) (*Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, issue, err := f.issue(owner, repositoryName, issueNumber)
+	if err != nil {
+		return nil, err
+	}
+	if body == "" {
+		return nil, fmt.Errorf("issue comment without body: %w", ErrValidation)
+	}
+	if issue.Locked && !repo.isCollaborator(f.currentUser) {
+		return nil, fmt.Errorf("comment on locked issue %d: %w", issueNumber, ErrForbidden)
+	}
+
+	now := f.now()
+	comment := &Comment{ID: f.newID(), Author: f.currentUser, Body: body, CreatedAt: now, UpdatedAt: now}
+	comment.Link = fmt.Sprintf("https://github.com/%s/%s/issues/%d#issuecomment-%d", repo.owner, repo.info.Name, issueNumber, comment.ID)
+	repo.issueComments[issueNumber] = append(repo.issueComments[issueNumber], comment)
+	issue.UpdatedAt = now
+	result := *comment
+	return &result, nil
+}
+
+func (f *FakeGitService) EditIssueComment(ctx context.Context, owner, repositoryName string, issueNumber int, commentID int64, body string) (*Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, i, err := f.ownIssueComment(owner, repositoryName, issueNumber, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if body == "" {
+		return nil, fmt.Errorf("issue comment without body: %w", ErrValidation)
+	}
+
+	c := repo.issueComments[issueNumber][i]
+	c.Body = body
+	c.UpdatedAt = f.now()
+	result := *c
+	return &result, nil
+}
+
+func (f *FakeGitService) DeleteIssueComment(ctx context.Context, owner, repositoryName string, issueNumber int, commentID int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, i, err := f.ownIssueComment(owner, repositoryName, issueNumber, commentID)
+	if err != nil {
+		return err
+	}
+
+	comments := repo.issueComments[issueNumber]
+	repo.issueComments[issueNumber] = append(comments[:i], comments[i+1:]...)
+	return nil
+}
+
+// issue находит проблему репозитория по номеру. Обсуждения запросов на слияние
+// FakeGitService хранит отдельно, в обсуждениях (см. ReplyToThread)
+func (f *FakeGitService) issue(owner, repositoryName string, issueNumber int) (*fakeRepository, *Issue, error) {
+	repo, err := f.repo(owner, repositoryName)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, issue := range repo.issues {
+		if issue.Number == issueNumber {
+			return repo, issue, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("issue %d: %w", issueNumber, ErrNotFound)
+}
+
+// ownIssueComment находит комментарий текущего пользователя в обсуждении проблемы:
+// возвращает репозиторий и индекс комментария
+func (f *FakeGitService) ownIssueComment(owner, repositoryName string, issueNumber int, commentID int64) (*fakeRepository, int, error) {
+	repo, _, err := f.issue(owner, repositoryName, issueNumber)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	for i, c := range repo.issueComments[issueNumber] {
+		if c.ID != commentID {
+			continue
+		}
+		if c.Author != f.currentUser {
+			return nil, -1, fmt.Errorf("comment %d by %s: %w", commentID, c.Author, ErrForbidden)
+		}
+		return repo, i, nil
+	}
+	return nil, -1, fmt.Errorf("comment %d: %w", commentID, ErrNotFound)
+}
+
+// validateIssue проверяет поля проблемы так же, как GitHub перед созданием или изменением
+func (r *fakeRepository) validateIssue(req *issueRequest) error {
+	if req.Title != nil && *req.Title == "" {
+		return fmt.Errorf("issue without title: %w", ErrValidation)
+	}
+	if req.Milestone != nil && *req.Milestone < 1 {
+		return fmt.Errorf("milestone %d: %w", *req.Milestone, ErrValidation)
+	}
+	if req.Assignees != nil {
+		for _, login := range *req.Assignees {
+			if !r.isCollaborator(login) {
+				return fmt.Errorf("assignee %s, who is not a collaborator: %w", login, ErrValidation)
+			}
+		}
+	}
+	if req.StateReason != nil {
+		switch IssueStateReason(*req.StateReason) {
+		case IssueCompleted, IssueNotPlanned, issueReopened:
+		default:
+			return fmt.Errorf("state reason %q: %w", *req.StateReason, ErrValidation)
+		}
+	}
+	return nil
+}
+
+// apply переносит в проблему поля, заданные в req
+func (i *Issue) apply(req *issueRequest) {
+	if req.Title != nil {
+		i.Title = *req.Title
+	}
+	if req.Body != nil {
+		i.Body = *req.Body
+	}
+	if req.Labels != nil {
+		i.Labels = append([]string(nil), *req.Labels...)
+	}
+	if req.Assignees != nil {
+		i.Assignees = append([]string(nil), *req.Assignees...)
+	}
+	if req.Milestone != nil {
+		i.Milestone = *req.Milestone
+	}
+	if req.State != nil {
+		i.State = State(*req.State)
+	}
+}
+
+// copyIssue возвращает копию проблемы с количеством комментариев
+func (r *fakeRepository) copyIssue(issue *Issue) *Issue {
+	i := *issue
+	i.Labels = append([]string(nil), issue.Labels...)
+	i.Assignees = append([]string(nil), issue.Assignees...)
+	i.Comments = len(r.issueComments[issue.Number])
+	return &i
+}
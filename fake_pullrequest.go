@@ -0,0 +1,274 @@
+package notgogithub
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func (f *FakeGitService) CreatePullRequest(ctx context.Context, owner, repositoryName, sourceBranch, destBranch, title string, opts ...PullRequestOption) (*PullRequest, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(owner, repositoryName)
+	if err != nil {
+		return nil, err
+	}
+	for _, branch := range []string{sourceBranch, destBranch} {
+		if _, ok := repo.branches[branch]; !ok {
+			return nil, fmt.Errorf("branch %s: %w", branch, ErrNotFound)
+		}
+	}
+	if sourceBranch == destBranch {
+		return nil, fmt.Errorf("pull request from %s into itself: %w", sourceBranch, ErrValidation)
+	}
+
+	// Как и на GitHub, запросы на слияние и проблемы делят одну нумерацию
+	req := newPullRequestRequest(opts)
+	number := repo.nextNumber()
+	now := f.now()
+	pr := &PullRequest{
+		ID:           int64(number),
+		Number:       number,
+		Title:        title,
+		SourceBranch: sourceBranch,
+		TargetBranch: destBranch,
+		State:        StateOpen,
+		Author:       f.currentUser,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		Body:         req.body,
+		Link:         fmt.Sprintf("https://github.com/%s/%s/pull/%d", repo.owner, repo.info.Name, number),
+	}
+	if req.draft {
+		pr.State = StateDraft
+	}
+	repo.pullRequests = append(repo.pullRequests, pr)
+
+	// Рецензенты назначаются уже созданному запросу, поэтому при ошибке он остается
+	for _, login := range req.reviewers {
+		if err := repo.canReview(pr, login); err != nil {
+			return repo.copyPullRequest(pr), err
+		}
+	}
+	pr.Reviewers = appendNew(pr.Reviewers, req.reviewers...)
+	pr.Labels = appendNew(pr.Labels, req.labels...)
+	return repo.copyPullRequest(pr), nil
+}
+
+func (f *FakeGitService) GetPullRequest(ctx context.Context, owner, repositoryName string, pullRequestID int) (*PullRequest, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, pr, err := f.pull(owner, repositoryName, pullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	return repo.copyPullRequest(pr), nil
+}
+
+func (f *FakeGitService) MergePullRequest(ctx context.Context, owner, repositoryName string, pullRequestID int, method MergeMethod, headSHA string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, pr, err := f.pull(owner, repositoryName, pullRequestID)
+	if err != nil {
+		return "", err
+	}
+	if method == "" {
+		method = MergeMethodMerge
+	}
+	if method != MergeMethodMerge && method != MergeMethodSquash && method != MergeMethodRebase {
+		return "", fmt.Errorf("merge method %q: %w", method, ErrValidation)
+	}
+	if pr.State != StateOpen {
+		return "", fmt.Errorf("merge %s pull request %d: %w", pr.State, pullRequestID, ErrConflict)
+	}
+	head, ok := repo.branches[pr.SourceBranch]
+	if !ok {
+		return "", fmt.Errorf("merge pull request %d without branch %s: %w", pullRequestID, pr.SourceBranch, ErrConflict)
+	}
+	if headSHA != "" && headSHA != head {
+		return "", fmt.Errorf("head of pull request %d is %s, not %s: %w", pullRequestID, head, headSHA, ErrConflict)
+	}
+	if repo.conflicts(head, repo.branches[pr.TargetBranch]) {
+		return "", fmt.Errorf("pull request %d has conflicts: %w", pullRequestID, ErrConflict)
+	}
+
+	// Вместо переноса коммитов ветки-источника слияние создает на ветке-назначении один коммит:
+	// с двумя родителями для MergeMethodMerge и с одним для остальных способов
+	now := f.now()
+	base := repo.branches[pr.TargetBranch]
+	commit := Commit{
+		Hash:      fmt.Sprintf("%040x", f.newID()),
+		Title:     fmt.Sprintf("%s (#%d)", pr.Title, pr.Number),
+		Author:    f.currentUser,
+		CreatedAt: now,
+		Parents:   []string{base},
+	}
+	if method == MergeMethodMerge {
+		commit.Title = fmt.Sprintf("Merge pull request #%d from %s/%s", pr.Number, repo.owner, pr.SourceBranch)
+		commit.Parents = append(commit.Parents, head)
+	}
+	repo.commits[commit.Hash] = &fakeCommit{commit: commit, parents: commit.Parents}
+	repo.branches[pr.TargetBranch] = commit.Hash
+	repo.mergeBases[pr.Number] = base
+
+	pr.State = StateMerged
+	pr.MergedAt, pr.ClosedAt, pr.UpdatedAt = now, now, now
+	return commit.Hash, nil
+}
+
+func (f *FakeGitService) ClosePullRequest(ctx context.Context, owner, repositoryName string, pullRequestID int) (*PullRequest, error) {
+	return f.editPullRequest(ctx, owner, repositoryName, pullRequestID, func(repo *fakeRepository, pr *PullRequest) error {
+		if pr.State == StateMerged {
+			return fmt.Errorf("close merged pull request %d: %w", pullRequestID, ErrValidation)
+		}
+		if pr.State != StateClosed {
+			pr.State = StateClosed
+			pr.ClosedAt = f.now()
+		}
+		return nil
+	})
+}
+
+func (f *FakeGitService) ReopenPullRequest(ctx context.Context, owner, repositoryName string, pullRequestID int) (*PullRequest, error) {
+	return f.editPullRequest(ctx, owner, repositoryName, pullRequestID, func(repo *fakeRepository, pr *PullRequest) error {
+		if pr.State == StateMerged {
+			return fmt.Errorf("reopen merged pull request %d: %w", pullRequestID, ErrValidation)
+		}
+		if pr.State != StateClosed {
+			return nil
+		}
+		if _, ok := repo.branches[pr.SourceBranch]; !ok {
+			return fmt.Errorf("reopen pull request %d without branch %s: %w", pullRequestID, pr.SourceBranch, ErrValidation)
+		}
+		pr.State = StateOpen
+		pr.ClosedAt = time.Time{}
+		return nil
+	})
+}
+
+func (f *FakeGitService) UpdatePullRequestTitleBody(ctx context.Context, owner, repositoryName string, pullRequestID int, title, body string) (*PullRequest, error) {
+	return f.editPullRequest(ctx, owner, repositoryName, pullRequestID, func(repo *fakeRepository, pr *PullRequest) error {
+		if title == "" {
+			return fmt.Errorf("pull request %d without title: %w", pullRequestID, ErrValidation)
+		}
+		pr.Title, pr.Body = title, body
+		return nil
+	})
+}
+
+func (f *FakeGitService) ChangePullRequestBase(ctx context.Context, owner, repositoryName string, pullRequestID int, destBranch string) (*PullRequest, error) {
+	return f.editPullRequest(ctx, owner, repositoryName, pullRequestID, func(repo *fakeRepository, pr *PullRequest) error {
+		if pr.State != StateOpen && pr.State != StateDraft {
+			return fmt.Errorf("change base of %s pull request %d: %w", pr.State, pullRequestID, ErrValidation)
+		}
+		if _, ok := repo.branches[destBranch]; !ok || destBranch == pr.SourceBranch {
+			return fmt.Errorf("base branch %s: %w", destBranch, ErrValidation)
+		}
+		pr.TargetBranch = destBranch
+		return nil
+	})
+}
+
+func (f *FakeGitService) ConvertPullRequestToDraft(ctx context.Context, owner, repositoryName string, pullRequestID int) (*PullRequest, error) {
+	return f.editPullRequest(ctx, owner, repositoryName, pullRequestID, func(repo *fakeRepository, pr *PullRequest) error {
+		if pr.State != StateOpen && pr.State != StateDraft {
+			return fmt.Errorf("convert %s pull request %d to draft: %w", pr.State, pullRequestID, ErrValidation)
+		}
+		pr.State = StateDraft
+		return nil
+	})
+}
+
+func (f *FakeGitService) MarkPullRequestReadyForReview(ctx context.Context, owner, repositoryName string, pullRequestID int) (*PullRequest, error) {
+	return f.editPullRequest(ctx, owner, repositoryName, pullRequestID, func(repo *fakeRepository, pr *PullRequest) error {
+		if pr.State != StateOpen && pr.State != StateDraft {
+			return fmt.Errorf("mark %s pull request %d ready for review: %w", pr.State, pullRequestID, ErrValidation)
+		}
+		pr.State = StateOpen
+		return nil
+	})
+}
+
+// editPullRequest меняет запрос на слияние функцией edit и возвращает его копию.
+// Если edit вернула ошибку, запрос не должен быть изменен
+func (f *FakeGitService) editPullRequest(ctx context.Context, owner, repositoryName string, pullRequestID int, edit func(repo *fakeRepository, pr *PullRequest) error) (*PullRequest, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, pr, err := f.pull(owner, repositoryName, pullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	if err := edit(repo, pr); err != nil {
+		return nil, err
+	}
+
+	pr.UpdatedAt = f.now()
+	return repo.copyPullRequest(pr), nil
+}
+
+// copyPullRequest возвращает копию запроса на слияние с текущим SHA ветки-источника
+func (r *fakeRepository) copyPullRequest(pr *PullRequest) *PullRequest {
+	p := *pr
+	p.Labels = append([]string(nil), pr.Labels...)
+	p.Assignees = append([]string(nil), pr.Assignees...)
+	p.Reviewers = append([]string(nil), pr.Reviewers...)
+	if sha, ok := r.branches[pr.SourceBranch]; ok {
+		p.HeadSHA = sha
+	}
+	return &p
+}
+
+// canReview проверяет, что у пользователя login можно запросить ревью запроса pr: как и GitHub,
+// ревью запрашивается только у соавторов репозитория и не у автора запроса
+func (r *fakeRepository) canReview(pr *PullRequest, login string) error {
+	if login == pr.Author {
+		return fmt.Errorf("review of pull request %d from its author %s: %w", pr.Number, login, ErrValidation)
+	}
+	if !r.isCollaborator(login) {
+		return fmt.Errorf("review from %s, who is not a collaborator: %w", login, ErrValidation)
+	}
+	return nil
+}
+
+// isCollaborator сообщает, есть ли у пользователя login доступ к репозиторию
+func (r *fakeRepository) isCollaborator(login string) bool {
+	_, ok := r.collaborators[login]
+	return ok || login == r.owner
+}
+
+// appendNew добавляет к list значения, которых в нем еще нет
+func appendNew(list []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, existing := range list {
+			if existing == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
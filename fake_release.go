@@ -0,0 +1,349 @@
+package notgogithub
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+)
+
+// AddRelease добавляет релиз. Тег release.TagName должен существовать.
+// Если release.ID не задан, релизу выдается новый идентификатор
+func (f *FakeGitService) AddRelease(owner, repositoryName string, release Release) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(owner, repositoryName)
+	if err != nil {
+		return err
+	}
+	if repo.tag(release.TagName) < 0 {
+		return fmt.Errorf("tag %s: %w", release.TagName, ErrNotFound)
+	}
+
+	if release.ID == 0 {
+		release.ID = f.newID()
+	} else if release.ID > f.lastID {
+		f.lastID = release.ID
+	}
+	repo.releases = append([]*Release{&release}, repo.releases...)
+	return nil
+}
+
+func (f *FakeGitService) GetRepositoryReleases(ctx context.Context, owner, repositoryName string) ([]*Release, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(owner, repositoryName)
+	if err != nil {
+		return nil, err
+	}
+
+	var Releases []*Release
+	for _, release := range repo.releases {
+		Releases = append(Releases, copyRelease(release))
+	}
+	return Releases, nil
+}
+
+func (f *FakeGitService) GetReleaseByTag(ctx context.Context, owner, repositoryName, tagName string) (*Release, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(owner, repositoryName)
+	if err != nil {
+		return nil, err
+	}
+	release := repo.publishedRelease(tagName)
+	if release == nil {
+		return nil, fmt.Errorf("release %s: %w", tagName, ErrNotFound)
+	}
+
+	return copyRelease(release), nil
+}
+
+func (f *FakeGitService) GetRelease(ctx context.Context, owner, repositoryName string, releaseID int64) (*Release, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, release, err := f.release(owner, repositoryName, releaseID)
+	if err != nil {
+		return nil, err
+	}
+	return copyRelease(release), nil
+}
+
+func (f *FakeGitService) GetLatestRelease(ctx context.Context, owner, repositoryName string) (*Release, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(owner, repositoryName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, release := range repo.releases {
+		if release.ID == repo.latestRelease && !release.Draft {
+			return copyRelease(release), nil
+		}
+	}
+	// Без явной отметки последним считается самый новый опубликованный не предварительный релиз
+	for _, release := range repo.releases {
+		if !release.Draft && !release.Prerelease {
+			return copyRelease(release), nil
+		}
+	}
+	return nil, fmt.Errorf("latest release: %w", ErrNotFound)
+}
+
+func (f *FakeGitService) CreateRelease(ctx context.Context, owner, repositoryName, tagName string, opts ...ReleaseOption) (*Release, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, err := f.repo(owner, repositoryName)
+	if err != nil {
+		return nil, err
+	}
+	for _, release := range repo.releases {
+		if release.TagName == tagName {
+			return nil, fmt.Errorf("create release %s: %w", tagName, ErrConflict)
+		}
+	}
+
+	release := &Release{
+		ID:        f.newID(),
+		TagName:   tagName,
+		Target:    "main",
+		Author:    f.currentUser,
+		Link:      fmt.Sprintf("https://github.com/%s/%s/releases/tag/%s", owner, repositoryName, url.PathEscape(tagName)),
+		CreatedAt: f.now(),
+	}
+	if err := f.applyRelease(repo, release, newReleaseRequest(opts)); err != nil {
+		return nil, err
+	}
+
+	repo.releases = append([]*Release{release}, repo.releases...)
+	return copyRelease(release), nil
+}
+
+func (f *FakeGitService) EditRelease(ctx context.Context, owner, repositoryName string, releaseID int64, opts ...ReleaseOption) (*Release, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, release, err := f.release(owner, repositoryName, releaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Изменения применяются к копии, чтобы ошибка не оставила релиз измененным наполовину
+	edited := copyRelease(release)
+	if err := f.applyRelease(repo, edited, newReleaseRequest(opts)); err != nil {
+		return nil, err
+	}
+	*release = *edited
+	return copyRelease(release), nil
+}
+
+func (f *FakeGitService) PublishRelease(ctx context.Context, owner, repositoryName string, releaseID int64) (*Release, error) {
+	return f.EditRelease(ctx, owner, repositoryName, releaseID, WithReleaseDraft(false))
+}
+
+func (f *FakeGitService) DeleteRelease(ctx context.Context, owner, repositoryName string, releaseID int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, release, err := f.release(owner, repositoryName, releaseID)
+	if err != nil {
+		return err
+	}
+
+	for i := range repo.releases {
+		if repo.releases[i] == release {
+			repo.releases = append(repo.releases[:i], repo.releases[i+1:]...)
+			break
+		}
+	}
+	for _, asset := range release.Assets {
+		delete(repo.assets, asset.ID)
+	}
+	if repo.latestRelease == releaseID {
+		repo.latestRelease = 0
+	}
+	return nil
+}
+
+func (f *FakeGitService) UploadReleaseAsset(ctx context.Context, owner, repositoryName string, releaseID int64, name, contentType string, content io.ReadSeeker) (*ReleaseAsset, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, release, err := f.release(owner, repositoryName, releaseID)
+	if err != nil {
+		return nil, err
+	}
+	for _, asset := range release.Assets {
+		if asset.Name == name {
+			return nil, fmt.Errorf("upload release asset %s: %w", name, ErrConflict)
+		}
+	}
+
+	asset := ReleaseAsset{
+		ID:          f.newID(),
+		Name:        name,
+		ContentType: contentType,
+		Size:        len(data),
+		DownloadLink: fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s",
+			owner, repositoryName, url.PathEscape(release.TagName), url.PathEscape(name)),
+		CreatedAt: f.now(),
+	}
+	release.Assets = append(release.Assets, asset)
+	if repo.assets == nil {
+		repo.assets = make(map[int64][]byte)
+	}
+	repo.assets[asset.ID] = data
+	return &asset, nil
+}
+
+func (f *FakeGitService) DownloadReleaseAsset(ctx context.Context, owner, repositoryName string, releaseID int64, assetName string) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, release, err := f.release(owner, repositoryName, releaseID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range release.Assets {
+		if release.Assets[i].Name == assetName {
+			release.Assets[i].DownloadCount++
+			return io.NopCloser(bytes.NewReader(repo.assets[release.Assets[i].ID])), nil
+		}
+	}
+	return nil, fmt.Errorf("release %d has no asset %q: %w", releaseID, assetName, ErrNotFound)
+}
+
+func (f *FakeGitService) IterateRepositoryReleases(ctx context.Context, owner, repositoryName string) *ReleaseIterator {
+	return fakeIterator(ctx, func() ([]*Release, error) {
+		return f.GetRepositoryReleases(ctx, owner, repositoryName)
+	})
+}
+
+// release находит релиз репозитория по идентификатору
+func (f *FakeGitService) release(owner, repositoryName string, releaseID int64) (*fakeRepository, *Release, error) {
+	repo, err := f.repo(owner, repositoryName)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, release := range repo.releases {
+		if release.ID == releaseID {
+			return repo, release, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("release %d: %w", releaseID, ErrNotFound)
+}
+
+// applyRelease переносит в release поля запроса req. Как и GitHub, при публикации релиза
+// создает его тег на release.Target, если тега еще нет
+func (f *FakeGitService) applyRelease(repo *fakeRepository, release *Release, req *releaseRequest) error {
+	if req.TargetCommitish != nil {
+		release.Target = *req.TargetCommitish
+	}
+	if req.Name != nil {
+		release.Name = *req.Name
+	}
+	if req.Body != nil {
+		release.Body = *req.Body
+	}
+	if req.Prerelease != nil {
+		release.Prerelease = *req.Prerelease
+	}
+	if req.Draft != nil {
+		release.Draft = *req.Draft
+	}
+	if release.Draft {
+		release.PublishedAt = time.Time{}
+	} else if release.PublishedAt.IsZero() {
+		if repo.tag(release.TagName) < 0 {
+			sha, ok := repo.branches[release.Target]
+			if !ok {
+				sha = release.Target
+			}
+			if _, ok := repo.commits[sha]; !ok {
+				return fmt.Errorf("target %s: %w", release.Target, ErrValidation)
+			}
+			repo.tags = append(repo.tags, &Tag{Title: release.TagName, Hash: sha, ZipLink: fmt.Sprintf(
+				"https://api.github.com/repos/%s/%s/zipball/refs/tags/%s", repo.owner, repo.info.Name, release.TagName)})
+		}
+		release.PublishedAt = f.now()
+	}
+	if req.MakeLatest != nil {
+		switch {
+		case *req.MakeLatest == "true":
+			repo.latestRelease = release.ID
+		case repo.latestRelease == release.ID:
+			repo.latestRelease = 0
+		}
+	}
+	return nil
+}
+
+// copyRelease возвращает копию релиза, которую вызывающий может менять, не затрагивая состояние
+func copyRelease(release *Release) *Release {
+	r := *release
+	r.Assets = append([]ReleaseAsset(nil), release.Assets...)
+	return &r
+}
+
+// publishedRelease возвращает опубликованный релиз тега или nil: черновики, как и в GitHub,
+// по тегу не находятся
+func (r *fakeRepository) publishedRelease(tagName string) *Release {
+	for _, release := range r.releases {
+		if release.TagName == tagName && !release.Draft {
+			return release
+		}
+	}
+	return nil
+}
@@ -0,0 +1,328 @@
+package notgogithub
+
+import (
+	"context"
+	"fmt"
+)
+
+// reviewStates - состояние ревью, отправленного с решением
+var reviewStates = map[ReviewEvent]string{
+	ReviewApprove:        ReviewStateApproved,
+	ReviewRequestChanges: ReviewStateChangesRequested,
+	ReviewComment:        ReviewStateCommented,
+}
+
+// AddReview добавляет ревью к запросу на слияние с номером pullRequestID.
+// Если review.ID не задан, ревью выдается новый идентификатор
+func (f *FakeGitService) AddReview(owner, repositoryName string, pullRequestID int, review Review) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, _, err := f.pull(owner, repositoryName, pullRequestID)
+	if err != nil {
+		return err
+	}
+
+	if review.ID == 0 {
+		review.ID = f.newID()
+	} else if review.ID > f.lastID {
+		f.lastID = review.ID
+	}
+	repo.reviews[pullRequestID] = append(repo.reviews[pullRequestID], &review)
+	return nil
+}
+
+func (f *FakeGitService) GetReviews(ctx context.Context, owner, repositoryName string, pullRequestID int) ([]*Review, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, _, err := f.pull(owner, repositoryName, pullRequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	var Reviews []*Review
+	for _, review := range repo.reviews[pullRequestID] {
+		r := *review
+		Reviews = append(Reviews, &r)
+	}
+	return Reviews, nil
+}
+
+func (f *FakeGitService) CreateReview(ctx context.Context, owner, repositoryName string, pullRequestID int, event ReviewEvent, body string, comments ...LineComment) (*Review, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, pr, err := f.pull(owner, repositoryName, pullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	state, ok := reviewStates[event]
+	if !ok {
+		return nil, fmt.Errorf("review event %q: %w", event, ErrValidation)
+	}
+	if state != ReviewStateCommented && pr.Author == f.currentUser {
+		return nil, fmt.Errorf("%s on own pull request %d: %w", event, pullRequestID, ErrValidation)
+	}
+	for _, c := range comments {
+		if err := c.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	review := f.addReview(repo, pr, state, body)
+	for _, c := range comments {
+		f.addLineThread(repo, pr, review, c)
+	}
+	r := *review
+	return &r, nil
+}
+
+func (f *FakeGitService) CreateLineComment(ctx context.Context, owner, repositoryName string, pullRequestID int, comment LineComment) (*Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, pr, err := f.pull(owner, repositoryName, pullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	if err := comment.validate(); err != nil {
+		return nil, err
+	}
+
+	// Как и на GitHub, отдельный комментарий создает ревью с одним этим комментарием
+	thread := f.addLineThread(repo, pr, f.addReview(repo, pr, ReviewStateCommented, ""), comment)
+	c := thread.Conversation[0]
+	return &c, nil
+}
+
+func (f *FakeGitService) ReplyToThread(ctx context.Context, owner, repositoryName string, pullRequestID int, threadID int64, body string) (*Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, pr, err := f.pull(owner, repositoryName, pullRequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	var thread *Thread
+	for _, t := range repo.threads[pullRequestID] {
+		if t.ID == threadID {
+			thread = t
+			break
+		}
+	}
+
+	now := f.now()
+	comment := Comment{ID: f.newID(), Author: f.currentUser, Body: body, CreatedAt: now, UpdatedAt: now}
+	if threadID == 0 {
+		if thread == nil {
+			// Общее обсуждение идет первым, как в gitHubService
+			thread = &Thread{}
+			repo.threads[pullRequestID] = append([]*Thread{thread}, repo.threads[pullRequestID]...)
+		}
+		comment.Link = fmt.Sprintf("https://github.com/%s/%s/pull/%d#issuecomment-%d", repo.owner, repo.info.Name, pullRequestID, comment.ID)
+	} else {
+		if thread == nil {
+			return nil, fmt.Errorf("thread %d: %w", threadID, ErrNotFound)
+		}
+		// Ответ относится к тому же месту кода, что и обсуждение
+		if len(thread.Conversation) > 0 {
+			first := thread.Conversation[0]
+			comment.Side, comment.StartLine, comment.EndLine = first.Side, first.StartLine, first.EndLine
+			comment.CommitSHA, comment.DiffHunk = first.CommitSHA, first.DiffHunk
+		}
+		comment.InReplyTo = threadID
+		comment.ReviewID = f.addReview(repo, pr, ReviewStateCommented, "").ID
+		comment.Outdated, comment.Resolved = thread.Outdated, thread.Resolved
+		comment.Link = fmt.Sprintf("https://github.com/%s/%s/pull/%d#discussion_r%d", repo.owner, repo.info.Name, pullRequestID, comment.ID)
+	}
+
+	thread.Conversation = append(thread.Conversation, comment)
+	thread.Comments = append(thread.Comments, body)
+	return &comment, nil
+}
+
+func (f *FakeGitService) EditComment(ctx context.Context, owner, repositoryName string, pullRequestID int, commentID int64, body string) (*Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	thread, i, err := f.ownComment(owner, repositoryName, pullRequestID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &thread.Conversation[i]
+	c.Body = body
+	c.UpdatedAt = f.now()
+	thread.Comments[i] = body
+	result := *c
+	return &result, nil
+}
+
+func (f *FakeGitService) DeleteComment(ctx context.Context, owner, repositoryName string, pullRequestID int, commentID int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	thread, i, err := f.ownComment(owner, repositoryName, pullRequestID, commentID)
+	if err != nil {
+		return err
+	}
+
+	thread.Conversation = append(thread.Conversation[:i], thread.Conversation[i+1:]...)
+	thread.Comments = append(thread.Comments[:i], thread.Comments[i+1:]...)
+	if len(thread.Conversation) == 0 {
+		repo := f.repos[repoKey(owner, repositoryName)]
+		threads := repo.threads[pullRequestID]
+		for j := range threads {
+			if threads[j] == thread {
+				repo.threads[pullRequestID] = append(threads[:j], threads[j+1:]...)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (f *FakeGitService) DismissReview(ctx context.Context, owner, repositoryName string, pullRequestID int, reviewID int64, message string) (*Review, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	repo, _, err := f.pull(owner, repositoryName, pullRequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, review := range repo.reviews[pullRequestID] {
+		if review.ID != reviewID {
+			continue
+		}
+		if message == "" {
+			return nil, fmt.Errorf("dismiss review %d without message: %w", reviewID, ErrValidation)
+		}
+		if review.State != ReviewStateApproved && review.State != ReviewStateChangesRequested {
+			return nil, fmt.Errorf("dismiss %s review %d: %w", review.State, reviewID, ErrValidation)
+		}
+
+		review.State = ReviewStateDismissed
+		r := *review
+		return &r, nil
+	}
+	return nil, fmt.Errorf("review %d: %w", reviewID, ErrNotFound)
+}
+
+// pull находит запрос на слияние репозитория по номеру
+func (f *FakeGitService) pull(owner, repositoryName string, pullRequestID int) (*fakeRepository, *PullRequest, error) {
+	repo, err := f.repo(owner, repositoryName)
+	if err != nil {
+		return nil, nil, err
+	}
+	pr := repo.pullRequest(pullRequestID)
+	if pr == nil {
+		return nil, nil, fmt.Errorf("pull request %d: %w", pullRequestID, ErrNotFound)
+	}
+	return repo, pr, nil
+}
+
+// addReview добавляет ревью текущего пользователя к последнему коммиту ветки запроса на слияние
+func (f *FakeGitService) addReview(repo *fakeRepository, pr *PullRequest, state, body string) *Review {
+	review := &Review{
+		ID:          f.newID(),
+		Author:      f.currentUser,
+		Body:        body,
+		State:       state,
+		CommitSHA:   repo.branches[pr.SourceBranch],
+		SubmittedAt: f.now(),
+	}
+	review.Link = fmt.Sprintf("https://github.com/%s/%s/pull/%d#pullrequestreview-%d", repo.owner, repo.info.Name, pr.Number, review.ID)
+	repo.reviews[pr.Number] = append(repo.reviews[pr.Number], review)
+	return review
+}
+
+// addLineThread открывает обсуждение комментарием c из ревью review
+func (f *FakeGitService) addLineThread(repo *fakeRepository, pr *PullRequest, review *Review, c LineComment) *Thread {
+	comment := Comment{
+		ID:        f.newID(),
+		ReviewID:  review.ID,
+		Author:    f.currentUser,
+		Body:      c.Body,
+		Side:      Side(c.side()),
+		StartLine: c.StartLine,
+		EndLine:   c.Line,
+		CommitSHA: review.CommitSHA,
+		CreatedAt: review.SubmittedAt,
+		UpdatedAt: review.SubmittedAt,
+	}
+	if comment.StartLine == 0 {
+		comment.StartLine = comment.EndLine
+	}
+	comment.Link = fmt.Sprintf("https://github.com/%s/%s/pull/%d#discussion_r%d", repo.owner, repo.info.Name, pr.Number, comment.ID)
+
+	thread := &Thread{
+		Filename:     c.Path,
+		LineOfCode:   uint64(c.Line),
+		Comments:     []string{c.Body},
+		ID:           comment.ID,
+		Conversation: []Comment{comment},
+	}
+	repo.threads[pr.Number] = append(repo.threads[pr.Number], thread)
+	return thread
+}
+
+// ownComment находит комментарий текущего пользователя в обсуждениях запроса на слияние:
+// возвращает обсуждение и индекс комментария в нем
+func (f *FakeGitService) ownComment(owner, repositoryName string, pullRequestID int, commentID int64) (*Thread, int, error) {
+	repo, _, err := f.pull(owner, repositoryName, pullRequestID)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	for _, thread := range repo.threads[pullRequestID] {
+		for i, c := range thread.Conversation {
+			if c.ID != commentID {
+				continue
+			}
+			if c.Author != f.currentUser {
+				return nil, -1, fmt.Errorf("comment %d by %s: %w", commentID, c.Author, ErrForbidden)
+			}
+			return thread, i, nil
+		}
+	}
+	return nil, -1, fmt.Errorf("comment %d: %w", commentID, ErrNotFound)
+}
+
+// validate проверяет комментарий так же, как GitHub перед созданием
+func (c LineComment) validate() error {
+	if c.Path == "" || c.Line < 1 || c.StartLine > c.Line {
+		return fmt.Errorf("line comment %s:%d-%d: %w", c.Path, c.StartLine, c.Line, ErrValidation)
+	}
+	return nil
+}
@@ -0,0 +1,558 @@
+package notgogithub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestFake создает FakeGitService с репозиторием jostanise/rsa_encrypted_local_chat,
+// история которого содержит слияние:
+//
+//	c1 - c2 - c4 (main)
+//	   \    /
+//	     c3
+func newTestFake(t *testing.T) *FakeGitService {
+	t.Helper()
+
+	fake := NewFakeGitService("jostanise")
+	fake.AddUser(User{UserName: "jostanise", FullName: "Mikhail Chestneyshy"})
+	fake.AddUser(User{UserName: "PeakIntegral"})
+	fake.AddRepository("jostanise", Repository{Name: "rsa_encrypted_local_chat"})
+
+	base := time.Date(2021, 10, 12, 15, 0, 0, 0, time.UTC)
+	commits := []struct {
+		sha     string
+		parents []string
+	}{
+		{"c1", nil},
+		{"c2", []string{"c1"}},
+		{"c3", []string{"c1"}},
+		{"c4", []string{"c2", "c3"}},
+	}
+	for i, c := range commits {
+		commit := Commit{Hash: c.sha, Title: c.sha, CreatedAt: base.Add(time.Duration(i) * time.Minute)}
+		if err := fake.AddCommit("jostanise", "rsa_encrypted_local_chat", commit, c.parents...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := fake.SetBranch("jostanise", "rsa_encrypted_local_chat", "main", "c4"); err != nil {
+		t.Fatal(err)
+	}
+
+	return fake
+}
+
+func TestFakeBranchCommits(t *testing.T) {
+	fake := newTestFake(t)
+
+	commits, err := fake.GetBranchCommits(context.Background(), "jostanise", "rsa_encrypted_local_chat", "main")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"c4", "c3", "c2", "c1"}
+	if len(commits) != len(expected) {
+		t.Fatalf("expected %v commits, got %v", len(expected), len(commits))
+	}
+	for i, commit := range commits {
+		if commit.Hash != expected[i] {
+			t.Errorf("commit %v: expected %v, got %v", i, expected[i], commit.Hash)
+		}
+	}
+	if fmt.Sprint(commits[0].Parents) != "[c2 c3]" {
+		t.Errorf("unexpected parents of merge commit: %v", commits[0].Parents)
+	}
+
+	if err := fake.AddCommitFiles("jostanise", "rsa_encrypted_local_chat", "c2", "docs/README.md"); err != nil {
+		t.Fatalf("add commit files: %v", err)
+	}
+	filtered, _ := fake.GetBranchCommits(context.Background(), "jostanise", "rsa_encrypted_local_chat", "main", WithPath("docs"))
+	if len(filtered) != 1 || filtered[0].Hash != "c2" {
+		t.Errorf("unexpected commits for path filter: %v", filtered)
+	}
+	filtered, _ = fake.GetBranchCommits(context.Background(), "jostanise", "rsa_encrypted_local_chat", "main", WithMaxDepth(2))
+	if len(filtered) != 2 || filtered[1].Hash != "c3" {
+		t.Errorf("unexpected commits for max depth: %v", filtered)
+	}
+}
+
+func TestFakeMutations(t *testing.T) {
+	ctx := context.Background()
+	fake := newTestFake(t)
+
+	if err := fake.CreateBranch(ctx, "jostanise", "rsa_encrypted_local_chat", "feature", "c2"); err != nil {
+		t.Fatalf("create branch: %v", err)
+	}
+	if err := fake.CreateBranch(ctx, "jostanise", "rsa_encrypted_local_chat", "feature", "c2"); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected duplicate branch error, got %v", err)
+	}
+
+	branches, _ := fake.GetRepositoryBranches(ctx, "jostanise", "rsa_encrypted_local_chat")
+	if len(branches) != 2 || branches[0].Name != "feature" || branches[1].Name != "main" {
+		t.Errorf("unexpected branches after CreateBranch: %v", branches)
+	}
+	if branches[0].Enriched != EnrichCommitDates || branches[0].UpdatedAt.IsZero() {
+		t.Errorf("expected branch commit date, got %v", branches[0])
+	}
+	branches, _ = fake.GetRepositoryBranches(ctx, "jostanise", "rsa_encrypted_local_chat", WithEnrichment(EnrichNone))
+	if branches[0].Enriched != EnrichNone || !branches[0].UpdatedAt.IsZero() {
+		t.Errorf("expected branch without commit date, got %v", branches[0])
+	}
+
+	if _, err := fake.CreatePullRequest(ctx, "jostanise", "rsa_encrypted_local_chat", "feature", "main", "Feature"); err != nil {
+		t.Fatalf("create pull request: %v", err)
+	}
+	prs, _ := fake.GetRepositoryPullRequests(ctx, "jostanise", "rsa_encrypted_local_chat")
+	if len(prs) != 1 || prs[0].Number != 1 || prs[0].SourceBranch != "feature" || prs[0].State != StateOpen {
+		t.Errorf("unexpected pull requests: %v", prs)
+	}
+	if prs, _ := fake.GetRepositoryPullRequests(ctx, "jostanise", "rsa_encrypted_local_chat", WithState(StateMerged)); len(prs) != 0 {
+		t.Errorf("expected no merged pull requests, got %v", prs)
+	}
+
+	if err := fake.DeleteBranch(ctx, "jostanise", "rsa_encrypted_local_chat", "feature"); err != nil {
+		t.Fatalf("delete branch: %v", err)
+	}
+	if _, err := fake.CreatePullRequest(ctx, "jostanise", "rsa_encrypted_local_chat", "feature", "main", "Gone"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected missing branch error, got %v", err)
+	}
+
+	if err := fake.CreateTag(ctx, "jostanise", "rsa_encrypted_local_chat", "v1.0", "c4"); err != nil {
+		t.Fatalf("create tag: %v", err)
+	}
+	if err := fake.DeleteTag(ctx, "jostanise", "rsa_encrypted_local_chat", "v1.0"); err != nil {
+		t.Fatalf("delete tag: %v", err)
+	}
+	if tags, _ := fake.GetRepositoryTags(ctx, "jostanise", "rsa_encrypted_local_chat"); len(tags) != 0 {
+		t.Errorf("expected no tags after DeleteTag, got %v", tags)
+	}
+
+	if err := fake.SetAccessToRepository(ctx, "jostanise", "rsa_encrypted_local_chat", "PeakIntegral"); err != nil {
+		t.Fatalf("set access: %v", err)
+	}
+	if names, _ := fake.Collaborators("jostanise", "rsa_encrypted_local_chat"); len(names) != 1 || names[0] != "PeakIntegral" {
+		t.Errorf("unexpected collaborators: %v", names)
+	}
+	if err := fake.DenyAccessToRepository(ctx, "jostanise", "rsa_encrypted_local_chat", "PeakIntegral"); err != nil {
+		t.Fatalf("deny access: %v", err)
+	}
+	if names, _ := fake.Collaborators("jostanise", "rsa_encrypted_local_chat"); len(names) != 0 {
+		t.Errorf("expected no collaborators, got %v", names)
+	}
+
+	if err := fake.CreateRepository(ctx, "tessst"); err != nil {
+		t.Fatalf("create repository: %v", err)
+	}
+	repos, _ := fake.GetUserRepositories(ctx, "")
+	if len(repos) != 2 || repos[1].Name != "tessst" {
+		t.Errorf("unexpected repositories: %v", repos)
+	}
+}
+
+func TestFakeTagsAndReleases(t *testing.T) {
+	fake := newTestFake(t)
+	ctx := context.Background()
+	taggedAt := time.Date(2021, 10, 12, 16, 0, 0, 0, time.UTC)
+
+	// Arrange
+	if err := fake.AddTag("jostanise", "rsa_encrypted_local_chat", Tag{Title: "v0.9", Hash: "c2"}); err != nil {
+		t.Fatal(err)
+	}
+	annotated := Tag{Title: "v1.0", Hash: "c4", Annotated: true, Tagger: "jostanise", Message: "v1.0", TaggedAt: taggedAt}
+	if err := fake.AddTag("jostanise", "rsa_encrypted_local_chat", annotated); err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.AddRelease("jostanise", "rsa_encrypted_local_chat", Release{ID: 1, TagName: "v1.0", Body: "notes", CreatedAt: taggedAt}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.AddRelease("jostanise", "rsa_encrypted_local_chat", Release{TagName: "v2.0"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected missing tag error, got %v", err)
+	}
+
+	// Act
+	tags, err := fake.GetRepositoryTags(ctx, "jostanise", "rsa_encrypted_local_chat")
+
+	// Assert
+	if err != nil || len(tags) != 2 {
+		t.Fatalf("unexpected tags: %v, %v", tags, err)
+	}
+	if tags[0].Release != nil || tags[0].Annotated {
+		t.Errorf("expected lightweight tag without release, got %+v", tags[0])
+	}
+	if tags[1].Release == nil || tags[1].Release.Body != "notes" || tags[1].Description != "notes" || !tags[1].Annotated {
+		t.Errorf("expected annotated tag with release, got %+v", tags[1])
+	}
+
+	tags, _ = fake.GetRepositoryTags(ctx, "jostanise", "rsa_encrypted_local_chat", WithEnrichment(EnrichNone))
+	if tags[1].Release != nil || tags[1].Annotated || tags[1].Tagger != "" {
+		t.Errorf("expected tag without enrichment, got %+v", tags[1])
+	}
+
+	if _, err := fake.GetReleaseByTag(ctx, "jostanise", "rsa_encrypted_local_chat", "v0.9"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a tag without release, got %v", err)
+	}
+	if releases, _ := fake.GetRepositoryReleases(ctx, "jostanise", "rsa_encrypted_local_chat"); len(releases) != 1 || releases[0].ID != 1 {
+		t.Errorf("unexpected releases: %v", releases)
+	}
+}
+
+func TestFakeReleaseLifecycle(t *testing.T) {
+	fake := newTestFake(t)
+	ctx := context.Background()
+	const owner, repo = "jostanise", "rsa_encrypted_local_chat"
+
+	// Arrange
+	draft, err := fake.CreateRelease(ctx, owner, repo, "v1.1", WithReleaseDraft(true), WithTargetCommitish("missing"))
+	if err != nil {
+		t.Fatalf("CreateRelease: %v", err)
+	}
+	if _, err := fake.UploadReleaseAsset(ctx, owner, repo, draft.ID, "chat.zip", "application/zip", strings.NewReader("zip")); err != nil {
+		t.Fatalf("UploadReleaseAsset: %v", err)
+	}
+	if _, err := fake.UploadReleaseAsset(ctx, owner, repo, draft.ID, "chat.zip", "application/zip", strings.NewReader("zip")); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrConflict for a duplicate asset, got %v", err)
+	}
+
+	// Act
+	_, publishErr := fake.PublishRelease(ctx, owner, repo, draft.ID)
+	published, err := fake.EditRelease(ctx, owner, repo, draft.ID, WithReleaseDraft(false), WithTargetCommitish("main"), WithReleaseLatest(true))
+
+	// Assert
+	if !errors.Is(publishErr, ErrValidation) {
+		t.Errorf("expected ErrValidation for a missing target, got %v", publishErr)
+	}
+	if err != nil || published.Draft || published.PublishedAt.IsZero() {
+		t.Fatalf("unexpected published release: %+v, %v", published, err)
+	}
+	if latest, err := fake.GetLatestRelease(ctx, owner, repo); err != nil || latest.ID != draft.ID {
+		t.Errorf("unexpected latest release: %+v, %v", latest, err)
+	}
+	if byTag, err := fake.GetReleaseByTag(ctx, owner, repo, "v1.1"); err != nil || len(byTag.Assets) != 1 {
+		t.Errorf("unexpected release by tag: %+v, %v", byTag, err)
+	}
+	rc, err := fake.DownloadReleaseAsset(ctx, owner, repo, draft.ID, "chat.zip")
+	if err != nil {
+		t.Fatalf("DownloadReleaseAsset: %v", err)
+	}
+	if content, _ := io.ReadAll(rc); string(content) != "zip" {
+		t.Errorf("Incorrect asset content: %q", content)
+	}
+
+	if err := fake.DeleteRelease(ctx, owner, repo, draft.ID); err != nil {
+		t.Fatalf("DeleteRelease: %v", err)
+	}
+	if _, err := fake.GetLatestRelease(ctx, owner, repo); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound without releases, got %v", err)
+	}
+}
+
+func TestFakeThreads(t *testing.T) {
+	fake := newTestFake(t)
+	ctx := context.Background()
+	const owner, repo = "jostanise", "rsa_encrypted_local_chat"
+
+	// Arrange
+	if err := fake.AddPullRequest(owner, repo, PullRequest{Number: 1, Title: "feature"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.AddThread(owner, repo, 1, Thread{Filename: "main.go", LineOfCode: 7, Comments: []string{"Почему так?", "Так быстрее"}}); err != nil {
+		t.Fatal(err)
+	}
+	conversation := []Comment{{Author: "PeakIntegral", Body: "Первый"}, {Author: "jostanise", Body: "Второй", InReplyTo: 42}}
+	if err := fake.AddThread(owner, repo, 1, Thread{Filename: "go.mod", LineOfCode: 3, Resolved: true, Conversation: conversation}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	threads, err := fake.GetThreadsInfo(ctx, owner, repo, 1)
+
+	// Assert
+	if err != nil || len(threads) != 2 {
+		t.Fatalf("unexpected threads: %v, %v", threads, err)
+	}
+	legacy := threads[0]
+	if len(legacy.Conversation) != 2 || legacy.ID == 0 || legacy.Conversation[0].ID != legacy.ID {
+		t.Fatalf("Incorrect thread built from Comments: %+v", legacy)
+	}
+	if reply := legacy.Conversation[1]; reply.Body != "Так быстрее" || reply.InReplyTo != legacy.ID || reply.EndLine != 7 {
+		t.Errorf("Incorrect reply: %+v", reply)
+	}
+	resolved := threads[1]
+	if fmt.Sprint(resolved.Comments) != "[Первый Второй]" || !resolved.Conversation[0].Resolved || resolved.Conversation[1].InReplyTo != 42 {
+		t.Errorf("Incorrect thread built from Conversation: %+v", resolved)
+	}
+
+	threads[1].Conversation[0].Body = "changed"
+	if again, _ := fake.GetThreadsInfo(ctx, owner, repo, 1); again[1].Conversation[0].Body != "Первый" {
+		t.Errorf("GetThreadsInfo returned shared state")
+	}
+}
+
+func TestFakeReviews(t *testing.T) {
+	fake := newTestFake(t)
+	ctx := context.Background()
+	const owner, repo = "jostanise", "rsa_encrypted_local_chat"
+
+	// Arrange
+	if err := fake.AddPullRequest(owner, repo, PullRequest{Number: 1, Author: "PeakIntegral", SourceBranch: "main"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.AddPullRequest(owner, repo, PullRequest{Number: 2, Author: "jostanise"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.AddThread(owner, repo, 1, Thread{Filename: "go.mod", LineOfCode: 3, Conversation: []Comment{{Author: "PeakIntegral", Body: "Зачем?"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	review, err := fake.CreateReview(ctx, owner, repo, 1, ReviewApprove, "Хорошо", LineComment{Path: "main.go", StartLine: 5, Line: 6, Body: Suggestion("return nil")})
+	if err != nil {
+		t.Fatalf("CreateReview: %v", err)
+	}
+	general, err := fake.ReplyToThread(ctx, owner, repo, 1, 0, "Готово")
+	if err != nil {
+		t.Fatalf("ReplyToThread: %v", err)
+	}
+	if _, err := fake.EditComment(ctx, owner, repo, 1, general.ID, "Готово к слиянию"); err != nil {
+		t.Fatalf("EditComment: %v", err)
+	}
+	if _, err := fake.DismissReview(ctx, owner, repo, 1, review.ID, "Передумал"); err != nil {
+		t.Fatalf("DismissReview: %v", err)
+	}
+	threads, err := fake.GetThreadsInfo(ctx, owner, repo, 1)
+
+	// Assert
+	if err != nil || len(threads) != 3 {
+		t.Fatalf("unexpected threads: %v, %v", threads, err)
+	}
+	if threads[0].ID != 0 || fmt.Sprint(threads[0].Comments) != "[Готово к слиянию]" {
+		t.Errorf("Incorrect general thread: %+v", threads[0])
+	}
+	if c := threads[2].Conversation[0]; c.ReviewID != review.ID || c.StartLine != 5 || c.EndLine != 6 || c.CommitSHA != "c4" {
+		t.Errorf("Incorrect review comment: %+v", c)
+	}
+	if reviews, _ := fake.GetReviews(ctx, owner, repo, 1); len(reviews) != 1 || reviews[0].State != ReviewStateDismissed {
+		t.Errorf("Incorrect reviews: %+v", reviews)
+	}
+
+	if _, err := fake.CreateReview(ctx, owner, repo, 2, ReviewRequestChanges, "x"); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected ErrValidation for own pull request, got %v", err)
+	}
+	if _, err := fake.EditComment(ctx, owner, repo, 1, threads[1].ID, "x"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("expected ErrForbidden for a comment of another user, got %v", err)
+	}
+	if err := fake.DeleteComment(ctx, owner, repo, 2, general.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a comment of another pull request, got %v", err)
+	}
+}
+
+func TestFakePullRequestLifecycle(t *testing.T) {
+	fake := newTestFake(t)
+	ctx := context.Background()
+	const owner, repo = "jostanise", "rsa_encrypted_local_chat"
+
+	// Arrange
+	if err := fake.SetBranch(owner, repo, "feature", "c3"); err != nil {
+		t.Fatal(err)
+	}
+	pr, err := fake.CreatePullRequest(ctx, owner, repo, "feature", "main", "Feature", WithPullRequestDraft(true), WithPullRequestLabels("enhancement"))
+	if err != nil {
+		t.Fatalf("CreatePullRequest: %v", err)
+	}
+
+	// Act
+	_, draftErr := fake.MergePullRequest(ctx, owner, repo, pr.Number, MergeMethodMerge, "")
+	if _, err := fake.MarkPullRequestReadyForReview(ctx, owner, repo, pr.Number); err != nil {
+		t.Fatalf("MarkPullRequestReadyForReview: %v", err)
+	}
+	_, staleErr := fake.MergePullRequest(ctx, owner, repo, pr.Number, MergeMethodMerge, "c1")
+	sha, err := fake.MergePullRequest(ctx, owner, repo, pr.Number, MergeMethodMerge, pr.HeadSHA)
+	if err != nil {
+		t.Fatalf("MergePullRequest: %v", err)
+	}
+	merged, err := fake.GetPullRequest(ctx, owner, repo, pr.Number)
+	if err != nil {
+		t.Fatalf("GetPullRequest: %v", err)
+	}
+	commits, err := fake.GetBranchCommits(ctx, owner, repo, "main")
+
+	// Assert
+	if pr.State != StateDraft || pr.HeadSHA != "c3" || fmt.Sprint(pr.Labels) != "[enhancement]" {
+		t.Errorf("Incorrect opened pull request: %+v", *pr)
+	}
+	if !errors.Is(draftErr, ErrConflict) || !errors.Is(staleErr, ErrConflict) {
+		t.Errorf("expected ErrConflict for a draft and a stale head, got %v, %v", draftErr, staleErr)
+	}
+	if merged.State != StateMerged || merged.MergedAt.IsZero() {
+		t.Errorf("Incorrect merged pull request: %+v", *merged)
+	}
+	if err != nil || len(commits) != 5 || commits[0].Hash != sha || fmt.Sprint(commits[0].Parents) != "[c4 c3]" {
+		t.Errorf("Incorrect history after merge: %v, %v", commits, err)
+	}
+
+	if _, err := fake.ClosePullRequest(ctx, owner, repo, pr.Number); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected ErrValidation for closing a merged pull request, got %v", err)
+	}
+	if pr, err := fake.CreatePullRequest(ctx, owner, repo, "feature", "main", "x", WithReviewers("PeakIntegral")); pr == nil || !errors.Is(err, ErrValidation) {
+		t.Errorf("expected the pull request and ErrValidation for a review from a non-collaborator, got %v, %v", pr, err)
+	}
+}
+
+func TestFakePullRequestChanges(t *testing.T) {
+	fake := newTestFake(t)
+	ctx := context.Background()
+	const owner, repo = "jostanise", "rsa_encrypted_local_chat"
+
+	// Arrange: c2 и c6 меняют один файл, поэтому ветка c6 конфликтует с main
+	createdAt := time.Date(2021, 10, 12, 16, 0, 0, 0, time.UTC)
+	for sha, parent := range map[string]string{"c5": "c4", "c6": "c1"} {
+		if err := fake.AddCommit(owner, repo, Commit{Hash: sha, Title: sha, CreatedAt: createdAt}, parent); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for sha, path := range map[string]string{"c2": "chat.py", "c5": "README.md", "c6": "chat.py"} {
+		if err := fake.AddCommitFiles(owner, repo, sha, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for branch, sha := range map[string]string{"readme": "c5", "old": "c6"} {
+		if err := fake.SetBranch(owner, repo, branch, sha); err != nil {
+			t.Fatal(err)
+		}
+	}
+	readme, err := fake.CreatePullRequest(ctx, owner, repo, "readme", "main", "README")
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, err := fake.CreatePullRequest(ctx, owner, repo, "old", "main", "Old", WithPullRequestDraft(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.AddPullRequestFiles(owner, repo, readme.Number, PullRequestFile{Filename: "README.md", Status: FileAdded, Additions: 1, Patch: "@@ -0,0 +1 @@\n+# Chat"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	files, err := fake.GetPullRequestFiles(ctx, owner, repo, readme.Number)
+	if err != nil {
+		t.Fatalf("GetPullRequestFiles: %v", err)
+	}
+	diff, err := fake.GetPullRequestDiff(ctx, owner, repo, readme.Number)
+	if err != nil {
+		t.Fatalf("GetPullRequestDiff: %v", err)
+	}
+	commits, err := fake.GetPullRequestCommits(ctx, owner, repo, readme.Number)
+	if err != nil {
+		t.Fatalf("GetPullRequestCommits: %v", err)
+	}
+	clean, err := fake.GetPullRequestMergeability(ctx, owner, repo, readme.Number)
+	if err != nil {
+		t.Fatalf("GetPullRequestMergeability: %v", err)
+	}
+	dirty, err := fake.GetPullRequestMergeability(ctx, owner, repo, old.Number)
+	if err != nil {
+		t.Fatalf("GetPullRequestMergeability: %v", err)
+	}
+
+	// Assert
+	if len(files) != 1 || files[0].Changes != 1 {
+		t.Errorf("Incorrect files: %+v", files)
+	}
+	if diff != "diff --git a/README.md b/README.md\nnew file mode 100644\n--- /dev/null\n+++ b/README.md\n@@ -0,0 +1 @@\n+# Chat\n" {
+		t.Errorf("Incorrect diff:\n%s", diff)
+	}
+	if len(commits) != 1 || commits[0].Hash != "c5" {
+		t.Errorf("Incorrect commits: %+v", commits)
+	}
+	if !clean.Mergeable || clean.State != MergeableClean || clean.HeadSHA != "c5" {
+		t.Errorf("Incorrect mergeability: %+v", *clean)
+	}
+	if dirty.Mergeable || !dirty.Conflicts || dirty.State != MergeableDraft {
+		t.Errorf("Incorrect mergeability of conflicting draft: %+v", *dirty)
+	}
+}
+
+func TestFakeIssueManagement(t *testing.T) {
+	fake := newTestFake(t)
+	ctx := context.Background()
+	const owner, repo = "jostanise", "rsa_encrypted_local_chat"
+
+	// Arrange: в чужом репозитории у jostanise нет доступа
+	fake.AddRepository("PeakIntegral", Repository{Name: "cppLessons"})
+	if err := fake.AddIssue("PeakIntegral", "cppLessons", Issue{Number: 1, Title: "Flame war", Locked: true, LockReason: LockTooHeated}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.AddIssue(owner, repo, Issue{Number: 1, Title: "Keys leak"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.AddIssueComment(owner, repo, 1, Comment{ID: 100, Author: "PeakIntegral", Body: "Подтверждаю"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	issue, err := fake.CreateIssue(ctx, owner, repo, "Encrypt files", WithIssueBody("AES"), WithIssueAssignees("jostanise"), WithIssueMilestone(1))
+	if err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	_, assigneeErr := fake.CreateIssue(ctx, owner, repo, "Encrypt folders", WithIssueAssignees("PeakIntegral"))
+	comment, err := fake.CreateIssueComment(ctx, owner, repo, 1, "Исправлено в c4")
+	if err != nil {
+		t.Fatalf("CreateIssueComment: %v", err)
+	}
+	_, foreignErr := fake.EditIssueComment(ctx, owner, repo, 1, 100, "Чужой")
+	closed, err := fake.CloseIssue(ctx, owner, repo, 1, "")
+	if err != nil {
+		t.Fatalf("CloseIssue: %v", err)
+	}
+	_, reasonErr := fake.CloseIssue(ctx, owner, repo, issue.Number, "wontfix")
+	_, lockedErr := fake.CreateIssueComment(ctx, "PeakIntegral", "cppLessons", 1, "+1")
+	lockErr := fake.LockIssue(ctx, "PeakIntegral", "cppLessons", 1, LockSpam)
+	issues, err := fake.GetIssues(ctx, owner, repo, WithState(StateClosed))
+	if err != nil {
+		t.Fatalf("GetIssues: %v", err)
+	}
+
+	// Assert
+	if issue.Number != 2 || issue.Author != "jostanise" || issue.Body != "AES" || issue.Milestone != 1 || fmt.Sprint(issue.Assignees) != "[jostanise]" {
+		t.Errorf("Incorrect created issue: %+v", *issue)
+	}
+	if !errors.Is(assigneeErr, ErrValidation) || !errors.Is(reasonErr, ErrValidation) {
+		t.Errorf("Incorrect validation errors: %v, %v", assigneeErr, reasonErr)
+	}
+	if comment.Author != "jostanise" || !strings.HasSuffix(comment.Link, "/issues/1#issuecomment-"+fmt.Sprint(comment.ID)) {
+		t.Errorf("Incorrect comment: %+v", *comment)
+	}
+	if !errors.Is(foreignErr, ErrForbidden) || !errors.Is(lockedErr, ErrForbidden) || !errors.Is(lockErr, ErrForbidden) {
+		t.Errorf("Incorrect access errors: %v, %v, %v", foreignErr, lockedErr, lockErr)
+	}
+	if closed.State != StateClosed || closed.ClosedAt.IsZero() || closed.Comments != 2 {
+		t.Errorf("Incorrect closed issue: %+v", *closed)
+	}
+	if len(issues) != 1 || issues[0].Number != 1 || issues[0].Comments != 2 {
+		t.Errorf("Incorrect closed issues: %+v", issues)
+	}
+}
+
+func TestFakeIterator(t *testing.T) {
+	ctx := context.Background()
+	fake := newTestFake(t)
+
+	it := fake.IterateBranchCommits(ctx, "jostanise", "rsa_encrypted_local_chat", "main")
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if it.Err() != nil || count != 4 {
+		t.Errorf("expected 4 commits, got %v (err %v)", count, it.Err())
+	}
+
+	it = fake.IterateBranchCommits(ctx, "jostanise", "rsa_encrypted_local_chat", "missing")
+	if it.Next() || !errors.Is(it.Err(), ErrNotFound) {
+		t.Errorf("expected not found error, got %v", it.Err())
+	}
+}
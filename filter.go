@@ -0,0 +1,127 @@
+package notgogithub
+
+import "time"
+
+// State - состояние запроса на слияние или проблемы
+type State string
+
+const (
+	StateOpen   State = "open"   // Открыт
+	StateClosed State = "closed" // Закрыт (запрос на слияние - без слияния)
+	StateMerged State = "merged" // Запрос на слияние закрыт слиянием
+	StateDraft  State = "draft"  // Запрос на слияние открыт как черновик
+)
+
+// ListOption настраивает отдельный вызов списочного метода, например отбор по состоянию
+type ListOption func(*listOptions)
+
+// listOptions хранит настройки отдельного вызова списочного метода
+type listOptions struct {
+	states   []State    // Допустимые состояния (пусто - любые)
+	since    time.Time  // Коммиты не раньше этого момента (нулевое значение - без ограничения)
+	until    time.Time  // Коммиты не позже этого момента (нулевое значение - без ограничения)
+	path     string     // Только коммиты, изменившие этот файл или каталог
+	author   string     // Только коммиты автора с этим логином GitHub или email
+	maxDepth int        // Максимальное количество коммитов истории (0 - без ограничений)
+	skip     Enrichment // Дополнительные данные, которые не нужно загружать
+}
+
+// newListOptions применяет opts к настройкам по умолчанию
+func newListOptions(opts []ListOption) listOptions {
+	var lo listOptions
+	for _, opt := range opts {
+		opt(&lo)
+	}
+	return lo
+}
+
+// WithState оставляет только элементы в одном из состояний states.
+// У проблем бывают только состояния StateOpen и StateClosed
+func WithState(states ...State) ListOption {
+	return func(lo *listOptions) {
+		lo.states = append(lo.states, states...)
+	}
+}
+
+// WithSince оставляет только коммиты, созданные не раньше t
+func WithSince(t time.Time) ListOption {
+	return func(lo *listOptions) {
+		lo.since = t
+	}
+}
+
+// WithUntil оставляет только коммиты, созданные не позже t
+func WithUntil(t time.Time) ListOption {
+	return func(lo *listOptions) {
+		lo.until = t
+	}
+}
+
+// WithPath оставляет только коммиты, изменившие файл или каталог path
+func WithPath(path string) ListOption {
+	return func(lo *listOptions) {
+		lo.path = path
+	}
+}
+
+// WithAuthor оставляет только коммиты автора с логином GitHub или email author
+func WithAuthor(author string) ListOption {
+	return func(lo *listOptions) {
+		lo.author = author
+	}
+}
+
+// WithMaxDepth ограничивает обход истории n последними коммитами, как git log -n.
+// Значение n <= 0 снимает ограничение
+func WithMaxDepth(n int) ListOption {
+	return func(lo *listOptions) {
+		if n < 0 {
+			n = 0
+		}
+		lo.maxDepth = n
+	}
+}
+
+// limit возвращает ограничение на количество элементов с учетом глубины истории и maxItems сервиса
+func (lo *listOptions) limit(maxItems int) int {
+	if lo.maxDepth > 0 && (maxItems <= 0 || lo.maxDepth < maxItems) {
+		return lo.maxDepth
+	}
+	return maxItems
+}
+
+// matchState сообщает, проходит ли состояние s отбор
+func (lo *listOptions) matchState(s State) bool {
+	if len(lo.states) == 0 {
+		return true
+	}
+	for _, state := range lo.states {
+		if state == s {
+			return true
+		}
+	}
+	return false
+}
+
+// apiState возвращает значение параметра state запроса к API, при котором GitHub
+// отдает все элементы в нужных состояниях. Черновики GitHub считает открытыми,
+// а слитые запросы - закрытыми, поэтому точный отбор выполняет matchState
+func (lo *listOptions) apiState() string {
+	open, closed := false, false
+	for _, state := range lo.states {
+		switch state {
+		case StateOpen, StateDraft:
+			open = true
+		case StateClosed, StateMerged:
+			closed = true
+		}
+	}
+
+	switch {
+	case open && !closed:
+		return "open"
+	case closed && !open:
+		return "closed"
+	}
+	return "all"
+}
@@ -0,0 +1,225 @@
+package ghsim
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Fixtures описывает начальное состояние симулятора GitHub API
+type Fixtures struct {
+	AuthenticatedUser string       `json:"authenticated_user"` // Логин пользователя, от имени которого выполняются запросы к /user
+	Users             []User       `json:"users"`
+	Repositories      []Repository `json:"repositories"`
+}
+
+// User - пользователь GitHub
+type User struct {
+	Login     string `json:"login"`
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Followers int    `json:"followers"`
+	Following int    `json:"following"`
+}
+
+// Repository - репозиторий со всем его содержимым
+type Repository struct {
+	Owner         string            `json:"owner"`
+	Name          string            `json:"name"`
+	Description   string            `json:"description"`
+	Private       bool              `json:"private"`
+	Stars         int               `json:"stargazers_count"`
+	Forks         int               `json:"forks_count"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+	Languages     map[string]int    `json:"languages"` // Язык -> количество байт кода
+	Commits       []Commit          `json:"commits"`
+	Branches      map[string]string `json:"branches"` // Имя ветки -> SHA последнего коммита
+	PullRequests  []PullRequest     `json:"pull_requests"`
+	Reviews       []Review          `json:"reviews"`
+	Issues        []Issue           `json:"issues"`
+	Milestones    []Milestone       `json:"milestones"`
+	IssueComments []IssueComment    `json:"issue_comments"`
+	Contributors  []string          `json:"contributors"` // Логины в порядке убывания вклада
+	Tags          []Tag             `json:"tags"`
+	Releases      []Release         `json:"releases"`
+	Collaborators []string          `json:"collaborators"`
+	Invitations   []Invitation      `json:"invitations"`
+}
+
+// Commit - коммит git
+type Commit struct {
+	SHA         string    `json:"sha"`
+	Message     string    `json:"message"`
+	AuthorName  string    `json:"author_name"`
+	AuthorEmail string    `json:"author_email"`
+	AuthorLogin string    `json:"author_login"` // Аккаунт GitHub автора ("" - коммит не связан с аккаунтом)
+	Date        time.Time `json:"date"`
+	Parents     []string  `json:"parents"`
+	Files       []string  `json:"files"` // Измененные файлы
+}
+
+// PullRequest - запрос на слияние
+type PullRequest struct {
+	ID        int64      `json:"id"`
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	State     string     `json:"state"` // open или closed
+	Draft     bool       `json:"draft"`
+	Locked    bool       `json:"locked"`
+	User      string     `json:"user"`
+	Head      string     `json:"head"`
+	Base      string     `json:"base"`
+	Labels    []string   `json:"labels"`
+	Assignees []string   `json:"assignees"`
+	Reviewers []string   `json:"requested_reviewers"` // Логины пользователей, у которых запрошено ревью
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	ClosedAt  *time.Time `json:"closed_at"`
+	MergedAt  *time.Time `json:"merged_at"` // Задается только для слитых запросов
+	BaseSHA   string     `json:"base_sha"`  // SHA ветки-назначения перед слиянием ("" - текущий)
+	Files     []PullFile `json:"files"`
+
+	// MergeableState подменяет вычисленное состояние слияния, например "blocked" или "unknown"
+	MergeableState string `json:"mergeable_state"`
+}
+
+// PullFile - файл, измененный запросом на слияние
+type PullFile struct {
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename"`
+	Status           string `json:"status"` // added, removed, modified, renamed ("" - modified)
+	Additions        int    `json:"additions"`
+	Deletions        int    `json:"deletions"`
+	Patch            string `json:"patch"`
+}
+
+// Review - ревью запроса на слияние вместе с его комментариями
+type Review struct {
+	ID          int64           `json:"id"`
+	PullNumber  int             `json:"pull_number"`
+	User        string          `json:"user"`
+	State       string          `json:"state"` // APPROVED, CHANGES_REQUESTED, COMMENTED или DISMISSED
+	Body        string          `json:"body"`
+	CommitID    string          `json:"commit_id"`    // "" - последний коммит ветки запроса на слияние
+	SubmittedAt time.Time       `json:"submitted_at"` // Нулевая - дата первого комментария ревью
+	Comments    []ReviewComment `json:"comments"`
+}
+
+// ReviewComment - комментарий к строке или диапазону строк кода
+type ReviewComment struct {
+	ID        int64      `json:"id"`
+	User      string     `json:"user"`
+	Path      string     `json:"path"`
+	StartLine int        `json:"start_line"` // Первая строка диапазона (0 - комментарий к одной строке Line)
+	Line      int        `json:"line"`       // 0 - комментарий ко всему файлу
+	Side      string     `json:"side"`       // LEFT или RIGHT ("" - RIGHT)
+	Body      string     `json:"body"`
+	InReplyTo int64      `json:"in_reply_to"`
+	CommitID  string     `json:"commit_id"` // "" - последний коммит ветки запроса на слияние
+	DiffHunk  string     `json:"diff_hunk"`
+	Outdated  bool       `json:"outdated"` // Код под комментарием изменился
+	Resolved  bool       `json:"resolved"` // Обсуждение решено; учитывается у первого комментария обсуждения
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"` // nil - совпадает с CreatedAt
+}
+
+// Issue - проблема репозитория
+type Issue struct {
+	ID          int64      `json:"id"`
+	Number      int        `json:"number"`
+	Title       string     `json:"title"`
+	Body        string     `json:"body"`
+	State       string     `json:"state"`        // open или closed
+	StateReason string     `json:"state_reason"` // completed, not_planned или reopened
+	Locked      bool       `json:"locked"`
+	LockReason  string     `json:"active_lock_reason"`
+	User        string     `json:"user"`
+	Labels      []string   `json:"labels"`
+	Assignees   []string   `json:"assignees"`
+	Milestone   int        `json:"milestone"`    // Номер вехи (0 - без вехи)
+	PullRequest bool       `json:"pull_request"` // Проблема является запросом на слияние
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ClosedAt    *time.Time `json:"closed_at"`
+}
+
+// Milestone - веха, к которой привязываются проблемы
+type Milestone struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"` // open или closed
+}
+
+// IssueComment - комментарий в общем обсуждении проблемы или запроса на слияние
+type IssueComment struct {
+	ID          int64      `json:"id"`
+	IssueNumber int        `json:"issue_number"`
+	User        string     `json:"user"`
+	Body        string     `json:"body"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"` // nil - совпадает с CreatedAt
+}
+
+// Tag - тег. Без Annotation тег легковесный: его ссылка указывает прямо на коммит SHA
+type Tag struct {
+	Name       string      `json:"name"`
+	SHA        string      `json:"sha"` // SHA коммита
+	Annotation *Annotation `json:"annotation"`
+}
+
+// Annotation - объект аннотированного тега git, на который указывает ссылка тега
+type Annotation struct {
+	SHA         string    `json:"sha"` // SHA объекта тега
+	Message     string    `json:"message"`
+	TaggerName  string    `json:"tagger_name"`
+	TaggerEmail string    `json:"tagger_email"`
+	Date        time.Time `json:"date"`
+}
+
+// Release - релиз, привязанный к тегу
+type Release struct {
+	ID          int64      `json:"id"`
+	TagName     string     `json:"tag_name"`
+	Target      string     `json:"target_commitish"` // "" - ветка main
+	Name        string     `json:"name"`
+	Body        string     `json:"body"`
+	Draft       bool       `json:"draft"`
+	Prerelease  bool       `json:"prerelease"`
+	Author      string     `json:"author"` // Логин автора ("" - владелец репозитория)
+	Latest      bool       `json:"latest"` // Релиз явно отмечен последним (make_latest)
+	Assets      []Asset    `json:"assets"`
+	CreatedAt   time.Time  `json:"created_at"`
+	PublishedAt *time.Time `json:"published_at"` // nil у черновика; у опубликованного релиза по умолчанию CreatedAt
+}
+
+// Asset - файл, прикрепленный к релизу
+type Asset struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	ContentType   string    `json:"content_type"`
+	Content       []byte    `json:"content"` // В JSON - base64
+	DownloadCount int       `json:"download_count"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Invitation - приглашение в соавторы, которое пользователь еще не принял
+type Invitation struct {
+	ID      int64  `json:"id"`
+	Invitee string `json:"invitee"`
+}
+
+// LoadFixtures читает начальное состояние симулятора из JSON-файла
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f Fixtures
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &f, nil
+}
@@ -0,0 +1,379 @@
+// Package ghsim - локальный HTTP-сервер, эмулирующий ту часть GitHub REST API v3,
+// которую использует gitHubService: пользователи, репозитории, ветки, коммиты, запросы на слияние,
+// ревью и обсуждения (их решенность - через GraphQL API), проблемы, соавторы, теги и релизы.
+//
+// Сервер наполняется из Fixtures, отдает заголовки пагинации Link и X-RateLimit-*,
+// умеет исчерпывать лимит запросов и возвращать заранее заданные ошибки.
+// Сервис направляется на симулятор опцией WithEnterpriseURLs(server.URL, "")
+package ghsim
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apiPrefix и uploadPrefix - префиксы, которые go-github добавляет к адресам GitHub Enterprise Server
+const (
+	apiPrefix    = "/api/v3"
+	uploadPrefix = "/api/uploads"
+)
+
+// Server - симулятор GitHub API
+type Server struct {
+	URL string // Адрес сервера, например http://127.0.0.1:1234
+
+	srv    *httptest.Server
+	routes []route
+
+	mu       sync.Mutex
+	state    Fixtures
+	nextID   int64
+	requests []string
+	injected []injectedError
+
+	rateLimit     int
+	rateRemaining int
+	rateReset     time.Time
+	rateWindow    time.Duration
+}
+
+// injectedError - ошибка, которую сервер вернет на ближайший подходящий запрос
+type injectedError struct {
+	method     string
+	path       string
+	status     int
+	message    string
+	retryAfter time.Duration // Для ошибок вторичного лимита
+	abuse      bool
+}
+
+// New запускает симулятор с начальным состоянием f. Fixtures копируются,
+// поэтому изменения состояния сервера не затрагивают f
+func New(f *Fixtures) *Server {
+	s := &Server{
+		nextID:        1000000,
+		rateLimit:     5000,
+		rateRemaining: 5000,
+		rateWindow:    time.Hour,
+	}
+	s.rateReset = time.Now().Add(s.rateWindow)
+
+	if f != nil {
+		// Глубокая копия через JSON
+		data, err := json.Marshal(f)
+		if err != nil {
+			panic(fmt.Sprintf("ghsim: copy fixtures: %v", err))
+		}
+		if err := json.Unmarshal(data, &s.state); err != nil {
+			panic(fmt.Sprintf("ghsim: copy fixtures: %v", err))
+		}
+	}
+
+	s.normalize()
+	s.registerRoutes()
+	s.srv = httptest.NewServer(s)
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close останавливает сервер
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Requests возвращает журнал обработанных запросов в виде "GET /repos/owner/repo"
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+// ResetRequests очищает журнал запросов
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = nil
+}
+
+// InjectError заставляет сервер ответить ошибкой status на следующий запрос method к path
+// (путь без префикса /api/v3 или /api/uploads, например "/repos/owner/repo"). Каждый вызов срабатывает один раз
+func (s *Server) InjectError(method, path string, status int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.injected = append(s.injected, injectedError{method: method, path: path, status: status, message: message})
+}
+
+// InjectAbuseLimit отвечает на следующий запрос method к path ошибкой вторичного лимита с Retry-After
+func (s *Server) InjectAbuseLimit(method, path string, retryAfter time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.injected = append(s.injected, injectedError{
+		method:     method,
+		path:       path,
+		status:     http.StatusForbidden,
+		message:    "You have exceeded a secondary rate limit.",
+		retryAfter: retryAfter,
+		abuse:      true,
+	})
+}
+
+// SetRateLimit задает лимит запросов: после remaining запросов сервер отвечает 403 до момента reset,
+// после чего лимит восстанавливается до limit
+func (s *Server) SetRateLimit(limit, remaining int, reset time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rateLimit, s.rateRemaining, s.rateReset = limit, remaining, reset
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, apiPrefix), uploadPrefix)
+	s.requests = append(s.requests, r.Method+" "+p)
+	// Как и GitHub, сервер помечает каждый ответ идентификатором запроса
+	w.Header().Set("X-GitHub-Request-Id", fmt.Sprintf("SIM:%d", len(s.requests)))
+
+	if p != "/rate_limit" && !s.consumeRate(w) {
+		return
+	}
+
+	for i, inj := range s.injected {
+		if inj.method == r.Method && inj.path == p {
+			s.injected = append(s.injected[:i], s.injected[i+1:]...)
+			writeInjected(w, inj)
+			return
+		}
+	}
+
+	segments := strings.Split(strings.Trim(p, "/"), "/")
+	for _, rt := range s.routes {
+		if params, ok := rt.match(r.Method, segments); ok {
+			rt.handler(w, r, params)
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+// consumeRate расходует один запрос из лимита и пишет заголовки X-RateLimit-*.
+// Возвращает false, если лимит исчерпан и ответ уже отправлен
+func (s *Server) consumeRate(w http.ResponseWriter) bool {
+	now := time.Now()
+	if now.After(s.rateReset) {
+		s.rateRemaining = s.rateLimit
+		s.rateReset = now.Add(s.rateWindow)
+	}
+
+	exhausted := s.rateRemaining == 0
+	if !exhausted {
+		s.rateRemaining--
+	}
+
+	h := w.Header()
+	h.Set("X-RateLimit-Limit", strconv.Itoa(s.rateLimit))
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(s.rateRemaining))
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(s.rateReset.Unix(), 10))
+	h.Set("X-RateLimit-Used", strconv.Itoa(s.rateLimit-s.rateRemaining))
+
+	if exhausted {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message":           "API rate limit exceeded.",
+			"documentation_url": "https://docs.github.com/rest/overview/resources-in-the-rest-api#rate-limiting",
+		})
+		return false
+	}
+	return true
+}
+
+func writeInjected(w http.ResponseWriter, inj injectedError) {
+	if !inj.abuse {
+		writeError(w, inj.status, inj.message)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", strconv.Itoa(int(inj.retryAfter/time.Second)))
+	w.WriteHeader(inj.status)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message":           inj.message,
+		"documentation_url": "https://docs.github.com/rest/overview/resources-in-the-rest-api#secondary-rate-limits",
+	})
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message":           message,
+		"documentation_url": "https://docs.github.com/rest",
+	})
+}
+
+// writeValidationError отвечает 422, как GitHub при некорректных полях запроса
+func writeValidationError(w http.ResponseWriter, resource, field, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Validation Failed",
+		"errors": []map[string]string{
+			{"resource": resource, "field": field, "code": code},
+		},
+		"documentation_url": "https://docs.github.com/rest",
+	})
+}
+
+// writeText отвечает текстом, например diff
+func writeText(w http.ResponseWriter, text string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, text)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writePage отдает страницу items согласно параметрам page и per_page и пишет заголовок Link
+func writePage[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	q := r.URL.Query()
+	perPage := atoiDefault(q.Get("per_page"), 30)
+	if perPage < 1 {
+		perPage = 30
+	}
+	if perPage > 100 {
+		perPage = 100
+	}
+	page := atoiDefault(q.Get("page"), 1)
+	if page < 1 {
+		page = 1
+	}
+
+	last := (len(items) + perPage - 1) / perPage
+	if last < 1 {
+		last = 1
+	}
+
+	start := (page - 1) * perPage
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + perPage
+	if end > len(items) {
+		end = len(items)
+	}
+
+	var links []string
+	link := func(p int, rel string) {
+		u := *r.URL
+		values := u.Query()
+		values.Set("page", strconv.Itoa(p))
+		values.Set("per_page", strconv.Itoa(perPage))
+		u.RawQuery = values.Encode()
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, absoluteURL(r, &u), rel))
+	}
+	if page < last {
+		link(page+1, "next")
+		link(last, "last")
+	}
+	if page > 1 {
+		link(1, "first")
+		link(page-1, "prev")
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+
+	writeJSON(w, http.StatusOK, items[start:end])
+}
+
+func absoluteURL(r *http.Request, u *url.URL) string {
+	return "http://" + r.Host + u.RequestURI()
+}
+
+func atoiDefault(s string, def int) int {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// route - обработчик запросов, путь которых совпадает с шаблоном.
+// Сегмент {name} совпадает с одним сегментом пути, {name*} - с остатком пути
+type route struct {
+	method  string
+	pattern []string
+	handler func(w http.ResponseWriter, r *http.Request, p params)
+}
+
+type params map[string]string
+
+func (rt route) match(method string, segments []string) (params, bool) {
+	if rt.method != method {
+		return nil, false
+	}
+
+	p := params{}
+	for i, seg := range rt.pattern {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "*}") {
+			if i >= len(segments) {
+				return nil, false
+			}
+			p[seg[1:len(seg)-2]] = strings.Join(segments[i:], "/")
+			return p, true
+		}
+		if i >= len(segments) {
+			return nil, false
+		}
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			p[seg[1:len(seg)-1]] = segments[i]
+			continue
+		}
+		if seg != segments[i] {
+			return nil, false
+		}
+	}
+
+	if len(segments) != len(rt.pattern) {
+		return nil, false
+	}
+	return p, true
+}
+
+func (s *Server) handle(method, pattern string, handler func(w http.ResponseWriter, r *http.Request, p params)) {
+	s.routes = append(s.routes, route{
+		method:  method,
+		pattern: strings.Split(strings.Trim(pattern, "/"), "/"),
+		handler: handler,
+	})
+}
+
+// newID выдает идентификатор для созданного объекта
+func (s *Server) newID() int64 {
+	s.nextID++
+	return s.nextID
+}
+
+// apiURL строит адрес ресурса API симулятора
+func (s *Server) apiURL(format string, args ...interface{}) string {
+	return s.URL + apiPrefix + "/" + fmt.Sprintf(format, args...)
+}
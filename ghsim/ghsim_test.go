@@ -0,0 +1,139 @@
+package ghsim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func get(t *testing.T, s *Server, path string, v interface{}) *http.Response {
+	t.Helper()
+
+	resp, err := http.Get(s.URL + apiPrefix + path)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("decode %s: %v", path, err)
+		}
+	}
+	return resp
+}
+
+func TestPagination(t *testing.T) {
+	// Arrange
+	f := &Fixtures{Repositories: []Repository{{Owner: "octocat", Name: "hello"}}}
+	for i := 0; i < 5; i++ {
+		f.Repositories[0].Contributors = append(f.Repositories[0].Contributors, strings.Repeat("u", i+1))
+	}
+	s := New(f)
+	defer s.Close()
+
+	testTable := []struct {
+		query    string
+		expected int
+		rels     []string
+	}{
+		{query: "?per_page=2", expected: 2, rels: []string{`rel="next"`, `rel="last"`}},
+		{query: "?per_page=2&page=2", expected: 2, rels: []string{`rel="next"`, `rel="prev"`}},
+		{query: "?per_page=2&page=3", expected: 1, rels: []string{`rel="first"`, `rel="prev"`}},
+		{query: "", expected: 5, rels: nil},
+	}
+
+	for _, testCase := range testTable {
+		// Act
+		var items []map[string]interface{}
+		resp := get(t, s, "/repos/octocat/hello/contributors"+testCase.query, &items)
+
+		// Assert
+		if len(items) != testCase.expected {
+			t.Errorf("Incorrect amount of items for %q: expected %v, got %v", testCase.query, testCase.expected, len(items))
+		}
+		link := resp.Header.Get("Link")
+		for _, rel := range testCase.rels {
+			if !strings.Contains(link, rel) {
+				t.Errorf("Link for %q does not contain %s: %v", testCase.query, rel, link)
+			}
+		}
+		if testCase.rels == nil && link != "" {
+			t.Errorf("Unexpected Link for %q: %v", testCase.query, link)
+		}
+	}
+}
+
+func TestHistory(t *testing.T) {
+	// Arrange: c1 <- c2, c1 <- c3, c4 = merge(c2, c3)
+	day := func(d int) time.Time { return time.Date(2022, 3, d, 0, 0, 0, 0, time.UTC) }
+	repo := Repository{Commits: []Commit{
+		{SHA: "c1", Date: day(1)},
+		{SHA: "c2", Date: day(3), Parents: []string{"c1"}},
+		{SHA: "c3", Date: day(2), Parents: []string{"c1"}},
+		{SHA: "c4", Date: day(4), Parents: []string{"c2", "c3"}},
+	}}
+
+	// Act
+	var shas []string
+	for _, c := range repo.history("c4") {
+		shas = append(shas, c.SHA)
+	}
+
+	// Assert
+	if strings.Join(shas, ",") != "c4,c2,c3,c1" {
+		t.Errorf("Incorrect history: %v", shas)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	s := New(&Fixtures{Users: []User{{Login: "octocat"}}})
+	defer s.Close()
+	s.SetRateLimit(10, 1, time.Now().Add(time.Hour))
+
+	if resp := get(t, s, "/users/octocat", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %v", resp.StatusCode)
+	}
+
+	resp := get(t, s, "/users/octocat", nil)
+	if resp.StatusCode != http.StatusForbidden || resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("expected exhausted rate limit, got %v %v", resp.StatusCode, resp.Header)
+	}
+
+	// Запрос состояния лимита не расходует его
+	var limits struct {
+		Resources struct {
+			Core struct {
+				Limit     int `json:"limit"`
+				Remaining int `json:"remaining"`
+			} `json:"core"`
+		} `json:"resources"`
+	}
+	get(t, s, "/rate_limit", &limits)
+	if limits.Resources.Core.Limit != 10 || limits.Resources.Core.Remaining != 0 {
+		t.Errorf("Incorrect rate limit: %+v", limits)
+	}
+}
+
+func TestInjectError(t *testing.T) {
+	s := New(&Fixtures{Users: []User{{Login: "octocat"}}})
+	defer s.Close()
+	s.InjectError("GET", "/users/octocat", http.StatusBadGateway, "bad gateway")
+
+	if resp := get(t, s, "/users/octocat", nil); resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("expected 502, got %v", resp.StatusCode)
+	}
+	if resp := get(t, s, "/users/octocat", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %v", resp.StatusCode)
+	}
+	if resp := get(t, s, "/users/nobody", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404, got %v", resp.StatusCode)
+	}
+
+	expected := "GET /users/octocat,GET /users/octocat,GET /users/nobody"
+	if requests := strings.Join(s.Requests(), ","); requests != expected {
+		t.Errorf("Incorrect requests: %v", requests)
+	}
+}
@@ -0,0 +1,126 @@
+package ghsim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// graphql отвечает на запросы GraphQL API, которые делает gitHubService: обсуждения запроса
+// на слияние (reviewThreads) с признаком решенности и перевод запроса в черновик и обратно.
+// Все обсуждения умещаются на одну страницу. Как и GitHub, без токена GraphQL API отвечает 401
+func (s *Server) graphql(w http.ResponseWriter, r *http.Request, p params) {
+	if r.Header.Get("Authorization") == "" {
+		writeError(w, http.StatusUnauthorized, "This endpoint requires you to be authenticated.")
+		return
+	}
+	var body struct {
+		Query     string `json:"query"`
+		Variables struct {
+			Owner  string `json:"owner"`
+			Repo   string `json:"repo"`
+			Number int    `json:"number"`
+			ID     string `json:"id"`
+		} `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeGraphQLError(w, "", "Problems parsing JSON")
+		return
+	}
+	switch {
+	case strings.Contains(body.Query, "convertPullRequestToDraft"):
+		s.setPullDraft(w, "convertPullRequestToDraft", body.Variables.ID, true)
+		return
+	case strings.Contains(body.Query, "markPullRequestReadyForReview"):
+		s.setPullDraft(w, "markPullRequestReadyForReview", body.Variables.ID, false)
+		return
+	case !strings.Contains(body.Query, "reviewThreads"):
+		writeGraphQLError(w, "", "ghsim supports only reviewThreads, convertPullRequestToDraft and markPullRequestReadyForReview")
+		return
+	}
+
+	// Как и GitHub, GraphQL API сообщает о ненайденных объектах в поле errors ответа 200
+	repo := s.repo(body.Variables.Owner, body.Variables.Repo)
+	if repo == nil {
+		writeGraphQLError(w, "NOT_FOUND", "Could not resolve to a Repository with the name '"+body.Variables.Owner+"/"+body.Variables.Repo+"'.")
+		return
+	}
+	if repo.pull(body.Variables.Number) == nil {
+		writeGraphQLError(w, "NOT_FOUND", "Could not resolve to a PullRequest.")
+		return
+	}
+
+	type node = map[string]interface{}
+	nodes := []node{}
+	for _, review := range repo.Reviews {
+		if review.PullNumber != body.Variables.Number {
+			continue
+		}
+		for _, c := range review.Comments {
+			if c.InReplyTo == 0 {
+				nodes = append(nodes, node{
+					"isResolved": c.Resolved,
+					"isOutdated": c.Outdated,
+					"comments":   node{"nodes": []node{{"databaseId": c.ID}}},
+				})
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, node{
+		"data": node{
+			"repository": node{
+				"pullRequest": node{
+					"reviewThreads": node{
+						"pageInfo": node{"hasNextPage": false, "endCursor": nil},
+						"nodes":    nodes,
+					},
+				},
+			},
+		},
+	})
+}
+
+// setPullDraft выполняет мутацию mutation: делает запрос на слияние с глобальным идентификатором id
+// черновиком или снимает с него эту отметку
+func (s *Server) setPullDraft(w http.ResponseWriter, mutation, id string, draft bool) {
+	var pr *PullRequest
+	for ri := range s.state.Repositories {
+		repo := &s.state.Repositories[ri]
+		for i := range repo.PullRequests {
+			if pullNodeID(&repo.PullRequests[i]) == id {
+				pr = &repo.PullRequests[i]
+			}
+		}
+	}
+	if pr == nil {
+		writeGraphQLError(w, "NOT_FOUND", "Could not resolve to a node with the global id of '"+id+"'")
+		return
+	}
+	if pr.State != "open" {
+		writeGraphQLError(w, "UNPROCESSABLE", "Pull request is closed")
+		return
+	}
+
+	if pr.Draft != draft {
+		pr.Draft = draft
+		pr.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	}
+	type node = map[string]interface{}
+	writeJSON(w, http.StatusOK, node{
+		"data": node{mutation: node{"pullRequest": node{"isDraft": pr.Draft}}},
+	})
+}
+
+// writeGraphQLError отвечает ошибкой GraphQL API с типом kind ("" - без типа, как у ошибок разбора запроса)
+func writeGraphQLError(w http.ResponseWriter, kind, message string) {
+	e := map[string]string{"message": message}
+	if kind != "" {
+		e["type"] = kind
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":   nil,
+		"errors": []map[string]string{e},
+	})
+}
@@ -0,0 +1,2137 @@
+package ghsim
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/jellybebra/not-go-github/internal/gitmodel"
+)
+
+func (s *Server) registerRoutes() {
+	s.handle("GET", "/rate_limit", s.getRateLimit)
+	s.handle("POST", "/api/graphql", s.graphql)
+
+	s.handle("GET", "/user", s.getAuthenticatedUser)
+	s.handle("GET", "/user/repos", s.listAuthenticatedUserRepos)
+	s.handle("POST", "/user/repos", s.createRepo)
+	s.handle("GET", "/user/{id}", s.getUserByID)
+	s.handle("GET", "/users/{user}", s.getUser)
+	s.handle("GET", "/users/{user}/repos", s.listUserRepos)
+
+	s.handle("GET", "/repos/{owner}/{repo}", s.getRepo)
+	s.handle("GET", "/repos/{owner}/{repo}/languages", s.listLanguages)
+	s.handle("GET", "/repos/{owner}/{repo}/contributors", s.listContributors)
+
+	s.handle("GET", "/repos/{owner}/{repo}/branches", s.listBranches)
+	s.handle("GET", "/repos/{owner}/{repo}/branches/{branch}", s.getBranch)
+	s.handle("GET", "/repos/{owner}/{repo}/commits", s.listCommits)
+	s.handle("GET", "/repos/{owner}/{repo}/git/commits/{sha}", s.getGitCommit)
+	s.handle("POST", "/repos/{owner}/{repo}/git/refs", s.createRef)
+	s.handle("DELETE", "/repos/{owner}/{repo}/git/refs/{ref*}", s.deleteRef)
+	s.handle("GET", "/repos/{owner}/{repo}/git/matching-refs/{ref*}", s.listMatchingRefs)
+	s.handle("GET", "/repos/{owner}/{repo}/git/tags/{sha}", s.getGitTag)
+
+	s.handle("GET", "/repos/{owner}/{repo}/pulls", s.listPulls)
+	s.handle("POST", "/repos/{owner}/{repo}/pulls", s.createPull)
+	s.handle("GET", "/repos/{owner}/{repo}/pulls/comments/{id}", s.getReviewComment)
+	s.handle("GET", "/repos/{owner}/{repo}/pulls/{number}", s.getPull)
+	s.handle("PATCH", "/repos/{owner}/{repo}/pulls/{number}", s.editPull)
+	s.handle("GET", "/repos/{owner}/{repo}/pulls/{number}/files", s.listPullFiles)
+	s.handle("GET", "/repos/{owner}/{repo}/pulls/{number}/commits", s.listPullCommits)
+	s.handle("PUT", "/repos/{owner}/{repo}/pulls/{number}/merge", s.mergePull)
+	s.handle("POST", "/repos/{owner}/{repo}/pulls/{number}/requested_reviewers", s.requestReviewers)
+	s.handle("PATCH", "/repos/{owner}/{repo}/pulls/comments/{id}", s.editReviewComment)
+	s.handle("DELETE", "/repos/{owner}/{repo}/pulls/comments/{id}", s.deleteReviewComment)
+	s.handle("GET", "/repos/{owner}/{repo}/pulls/{number}/comments", s.listPullComments)
+	s.handle("POST", "/repos/{owner}/{repo}/pulls/{number}/comments", s.createPullComment)
+	s.handle("GET", "/repos/{owner}/{repo}/pulls/{number}/reviews", s.listReviews)
+	s.handle("POST", "/repos/{owner}/{repo}/pulls/{number}/reviews", s.createReview)
+	s.handle("PUT", "/repos/{owner}/{repo}/pulls/{number}/reviews/{id}/dismissals", s.dismissReview)
+	s.handle("GET", "/repos/{owner}/{repo}/pulls/{number}/reviews/{id}/comments", s.listReviewComments)
+
+	s.handle("GET", "/repos/{owner}/{repo}/issues", s.listIssues)
+	s.handle("POST", "/repos/{owner}/{repo}/issues", s.createIssue)
+	s.handle("GET", "/repos/{owner}/{repo}/issues/{number}", s.getIssue)
+	s.handle("PATCH", "/repos/{owner}/{repo}/issues/{number}", s.editIssue)
+	s.handle("PUT", "/repos/{owner}/{repo}/issues/{number}/lock", s.lockIssue)
+	s.handle("DELETE", "/repos/{owner}/{repo}/issues/{number}/lock", s.unlockIssue)
+	s.handle("GET", "/repos/{owner}/{repo}/issues/{number}/comments", s.listIssueComments)
+	s.handle("POST", "/repos/{owner}/{repo}/issues/{number}/comments", s.createIssueComment)
+	s.handle("POST", "/repos/{owner}/{repo}/issues/{number}/labels", s.addLabels)
+	s.handle("GET", "/repos/{owner}/{repo}/issues/comments/{id}", s.getIssueComment)
+	s.handle("PATCH", "/repos/{owner}/{repo}/issues/comments/{id}", s.editIssueComment)
+	s.handle("DELETE", "/repos/{owner}/{repo}/issues/comments/{id}", s.deleteIssueComment)
+
+	s.handle("GET", "/repos/{owner}/{repo}/tags", s.listTags)
+	s.handle("GET", "/repos/{owner}/{repo}/releases", s.listReleases)
+	s.handle("POST", "/repos/{owner}/{repo}/releases", s.createRelease)
+	s.handle("GET", "/repos/{owner}/{repo}/releases/latest", s.getLatestRelease)
+	s.handle("GET", "/repos/{owner}/{repo}/releases/tags/{tag}", s.getReleaseByTag)
+	s.handle("GET", "/repos/{owner}/{repo}/releases/assets/{id}", s.getReleaseAsset)
+	s.handle("GET", "/repos/{owner}/{repo}/releases/{id}", s.getRelease)
+	s.handle("PATCH", "/repos/{owner}/{repo}/releases/{id}", s.editRelease)
+	s.handle("DELETE", "/repos/{owner}/{repo}/releases/{id}", s.deleteRelease)
+	s.handle("GET", "/repos/{owner}/{repo}/releases/{id}/assets", s.listReleaseAssets)
+	s.handle("POST", "/repos/{owner}/{repo}/releases/{id}/assets", s.uploadReleaseAsset)
+	// Ссылка browser_download_url файла релиза, на которую перенаправляет скачивание
+	s.handle("GET", "/{owner}/{repo}/releases/download/{tag}/{name}", s.downloadReleaseAsset)
+
+	s.handle("PUT", "/repos/{owner}/{repo}/collaborators/{user}", s.addCollaborator)
+	s.handle("DELETE", "/repos/{owner}/{repo}/collaborators/{user}", s.removeCollaborator)
+	s.handle("GET", "/repos/{owner}/{repo}/invitations", s.listInvitations)
+	s.handle("DELETE", "/repos/{owner}/{repo}/invitations/{id}", s.deleteInvitation)
+}
+
+// normalize выдает идентификаторы объектам фикстур, у которых они не заданы
+func (s *Server) normalize() {
+	for i := range s.state.Users {
+		if s.state.Users[i].ID == 0 {
+			s.state.Users[i].ID = s.newID()
+		}
+	}
+	for ri := range s.state.Repositories {
+		repo := &s.state.Repositories[ri]
+		if repo.Branches == nil {
+			repo.Branches = make(map[string]string)
+		}
+		for i := range repo.PullRequests {
+			pr := &repo.PullRequests[i]
+			if pr.ID == 0 {
+				pr.ID = s.newID()
+			}
+			for j := range pr.Files {
+				if pr.Files[j].Status == "" {
+					pr.Files[j].Status = "modified"
+				}
+			}
+		}
+		for i := range repo.Reviews {
+			review := &repo.Reviews[i]
+			if review.ID == 0 {
+				review.ID = s.newID()
+			}
+			if pr := repo.pull(review.PullNumber); review.CommitID == "" && pr != nil {
+				review.CommitID = repo.Branches[pr.Head]
+			}
+			if review.SubmittedAt.IsZero() && len(review.Comments) > 0 {
+				review.SubmittedAt = review.Comments[0].CreatedAt
+			}
+			for j := range review.Comments {
+				c := &review.Comments[j]
+				if c.ID == 0 {
+					c.ID = s.newID()
+				}
+				if c.Side == "" {
+					c.Side = "RIGHT"
+				}
+				if c.UpdatedAt == nil {
+					c.UpdatedAt = timePtr(c.CreatedAt)
+				}
+				if pr := repo.pull(review.PullNumber); c.CommitID == "" && pr != nil {
+					c.CommitID = repo.Branches[pr.Head]
+				}
+			}
+		}
+		for i := range repo.IssueComments {
+			c := &repo.IssueComments[i]
+			if c.ID == 0 {
+				c.ID = s.newID()
+			}
+			if c.UpdatedAt == nil {
+				c.UpdatedAt = timePtr(c.CreatedAt)
+			}
+		}
+		for i := range repo.Issues {
+			if repo.Issues[i].ID == 0 {
+				repo.Issues[i].ID = s.newID()
+			}
+		}
+		for i := range repo.Releases {
+			release := &repo.Releases[i]
+			if release.ID == 0 {
+				release.ID = s.newID()
+			}
+			if release.Author == "" {
+				release.Author = repo.Owner
+			}
+			if release.Target == "" {
+				release.Target = "main"
+			}
+			if !release.Draft && release.PublishedAt == nil {
+				release.PublishedAt = timePtr(release.CreatedAt)
+			}
+			for j := range release.Assets {
+				if release.Assets[j].ID == 0 {
+					release.Assets[j].ID = s.newID()
+				}
+			}
+		}
+		for i := range repo.Invitations {
+			if repo.Invitations[i].ID == 0 {
+				repo.Invitations[i].ID = s.newID()
+			}
+		}
+	}
+}
+
+func (s *Server) getRateLimit(w http.ResponseWriter, r *http.Request, p params) {
+	rate := &github.Rate{
+		Limit:     s.rateLimit,
+		Remaining: s.rateRemaining,
+		Reset:     github.Timestamp{Time: s.rateReset},
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"resources": &github.RateLimits{Core: rate},
+		"rate":      rate,
+	})
+}
+
+func (s *Server) getAuthenticatedUser(w http.ResponseWriter, r *http.Request, p params) {
+	user := s.user(s.state.AuthenticatedUser)
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Requires authentication")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.renderUser(user))
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request, p params) {
+	user := s.user(p["user"])
+	if user == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.renderUser(user))
+}
+
+func (s *Server) getUserByID(w http.ResponseWriter, r *http.Request, p params) {
+	id, _ := strconv.ParseInt(p["id"], 10, 64)
+	for i := range s.state.Users {
+		if s.state.Users[i].ID == id {
+			writeJSON(w, http.StatusOK, s.renderUser(&s.state.Users[i]))
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+func (s *Server) listUserRepos(w http.ResponseWriter, r *http.Request, p params) {
+	s.writeRepos(w, r, p["user"])
+}
+
+func (s *Server) listAuthenticatedUserRepos(w http.ResponseWriter, r *http.Request, p params) {
+	s.writeRepos(w, r, s.state.AuthenticatedUser)
+}
+
+func (s *Server) writeRepos(w http.ResponseWriter, r *http.Request, owner string) {
+	var repos []*github.Repository
+	for i := range s.state.Repositories {
+		if s.state.Repositories[i].Owner == owner {
+			repos = append(repos, s.renderRepo(&s.state.Repositories[i]))
+		}
+	}
+	sort.Slice(repos, func(i, j int) bool { return repos[i].GetName() < repos[j].GetName() })
+	writePage(w, r, repos)
+}
+
+func (s *Server) createRepo(w http.ResponseWriter, r *http.Request, p params) {
+	var body github.Repository
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.GetName() == "" {
+		writeValidationError(w, "Repository", "name", "missing_field")
+		return
+	}
+	if s.repo(s.state.AuthenticatedUser, body.GetName()) != nil {
+		writeValidationError(w, "Repository", "name", "already_exists")
+		return
+	}
+
+	s.state.Repositories = append(s.state.Repositories, Repository{
+		Owner:       s.state.AuthenticatedUser,
+		Name:        body.GetName(),
+		Description: body.GetDescription(),
+		Private:     body.GetPrivate(),
+		UpdatedAt:   time.Now().UTC().Truncate(time.Second),
+		Branches:    make(map[string]string),
+	})
+	writeJSON(w, http.StatusCreated, s.renderRepo(&s.state.Repositories[len(s.state.Repositories)-1]))
+}
+
+func (s *Server) getRepo(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.renderRepo(repo))
+}
+
+func (s *Server) listLanguages(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	languages := repo.Languages
+	if languages == nil {
+		languages = map[string]int{}
+	}
+	writeJSON(w, http.StatusOK, languages)
+}
+
+func (s *Server) listContributors(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	var contributors []*github.Contributor
+	for i, login := range repo.Contributors {
+		c := &github.Contributor{
+			Login:         github.String(login),
+			Contributions: github.Int(len(repo.Contributors) - i),
+		}
+		if user := s.user(login); user != nil {
+			c.ID = github.Int64(user.ID)
+		}
+		contributors = append(contributors, c)
+	}
+	writePage(w, r, contributors)
+}
+
+func (s *Server) listBranches(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	var branches []*github.Branch
+	for _, name := range repo.branchNames() {
+		sha := repo.Branches[name]
+		// Как и GitHub, список веток содержит только SHA и URL последнего коммита
+		branches = append(branches, &github.Branch{
+			Name: github.String(name),
+			Commit: &github.RepositoryCommit{
+				SHA: github.String(sha),
+				URL: github.String(s.apiURL("repos/%s/%s/commits/%s", repo.Owner, repo.Name, sha)),
+			},
+		})
+	}
+	writePage(w, r, branches)
+}
+
+func (s *Server) getBranch(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+	sha, ok := repo.Branches[p["branch"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Branch not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &github.Branch{
+		Name:   github.String(p["branch"]),
+		Commit: s.renderRepositoryCommit(repo, repo.commit(sha)),
+	})
+}
+
+func (s *Server) listCommits(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	head := r.URL.Query().Get("sha")
+	if head == "" {
+		head = "main"
+	}
+	if sha, ok := repo.Branches[head]; ok {
+		head = sha
+	}
+	if repo.commit(head) == nil {
+		writeError(w, http.StatusNotFound, "No commit found for SHA: "+head)
+		return
+	}
+
+	filter, err := parseCommitFilter(r.URL.Query())
+	if err != nil {
+		writeValidationError(w, "Commit", "since", "invalid")
+		return
+	}
+
+	var commits []*github.RepositoryCommit
+	for _, c := range repo.history(head) {
+		if filter.match(c) {
+			commits = append(commits, s.renderRepositoryCommit(repo, c))
+		}
+	}
+	writePage(w, r, commits)
+}
+
+// commitFilter - параметры отбора списка коммитов
+type commitFilter struct {
+	since, until time.Time
+	path         string
+	author       string
+}
+
+func parseCommitFilter(q url.Values) (commitFilter, error) {
+	f := commitFilter{path: q.Get("path"), author: q.Get("author")}
+	for name, t := range map[string]*time.Time{"since": &f.since, "until": &f.until} {
+		if v := q.Get(name); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return f, err
+			}
+			*t = parsed
+		}
+	}
+	return f, nil
+}
+
+func (f commitFilter) match(c *Commit) bool {
+	if !f.since.IsZero() && c.Date.Before(f.since) {
+		return false
+	}
+	if !f.until.IsZero() && c.Date.After(f.until) {
+		return false
+	}
+	if f.author != "" && f.author != c.AuthorLogin && f.author != c.AuthorEmail {
+		return false
+	}
+	if f.path == "" {
+		return true
+	}
+	for _, file := range c.Files {
+		if file == f.path || strings.HasPrefix(file, strings.TrimSuffix(f.path, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) getGitCommit(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+	c := repo.commit(p["sha"])
+	if c == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.renderGitCommit(repo, c))
+}
+
+func (s *Server) createRef(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	var body struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || !strings.HasPrefix(body.Ref, "refs/") {
+		writeValidationError(w, "Reference", "ref", "invalid")
+		return
+	}
+	if repo.commit(body.SHA) == nil {
+		writeValidationError(w, "Reference", "sha", "invalid")
+		return
+	}
+
+	name := strings.TrimPrefix(body.Ref, "refs/")
+	switch {
+	case strings.HasPrefix(name, "heads/"):
+		branch := strings.TrimPrefix(name, "heads/")
+		if _, ok := repo.Branches[branch]; ok {
+			writeError(w, http.StatusUnprocessableEntity, "Reference already exists")
+			return
+		}
+		repo.Branches[branch] = body.SHA
+	case strings.HasPrefix(name, "tags/"):
+		tag := strings.TrimPrefix(name, "tags/")
+		if repo.tag(tag) >= 0 {
+			writeError(w, http.StatusUnprocessableEntity, "Reference already exists")
+			return
+		}
+		repo.Tags = append(repo.Tags, Tag{Name: tag, SHA: body.SHA})
+	default:
+		writeValidationError(w, "Reference", "ref", "invalid")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, &github.Reference{
+		Ref: github.String(body.Ref),
+		URL: github.String(s.apiURL("repos/%s/%s/git/%s", repo.Owner, repo.Name, body.Ref)),
+		Object: &github.GitObject{
+			Type: github.String("commit"),
+			SHA:  github.String(body.SHA),
+		},
+	})
+}
+
+func (s *Server) deleteRef(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	ref := strings.TrimPrefix(p["ref"], "refs/")
+	switch {
+	case strings.HasPrefix(ref, "heads/"):
+		branch := strings.TrimPrefix(ref, "heads/")
+		if _, ok := repo.Branches[branch]; ok {
+			delete(repo.Branches, branch)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	case strings.HasPrefix(ref, "tags/"):
+		if i := repo.tag(strings.TrimPrefix(ref, "tags/")); i >= 0 {
+			repo.Tags = append(repo.Tags[:i], repo.Tags[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	writeError(w, http.StatusUnprocessableEntity, "Reference does not exist")
+}
+
+func (s *Server) listPulls(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	state := r.URL.Query().Get("state")
+	if state == "" {
+		state = "open"
+	}
+
+	var pulls []*github.PullRequest
+	for i := range repo.PullRequests {
+		pr := &repo.PullRequests[i]
+		if state == "all" || pr.State == state {
+			pulls = append(pulls, s.renderPull(repo, pr))
+		}
+	}
+	// Как и GitHub, сначала новые
+	sort.SliceStable(pulls, func(i, j int) bool { return pulls[i].GetNumber() > pulls[j].GetNumber() })
+	writePage(w, r, pulls)
+}
+
+func (s *Server) createPull(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	var body github.NewPullRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.GetTitle() == "" {
+		writeValidationError(w, "PullRequest", "title", "missing_field")
+		return
+	}
+	if _, ok := repo.Branches[body.GetHead()]; !ok {
+		writeValidationError(w, "PullRequest", "head", "invalid")
+		return
+	}
+	if _, ok := repo.Branches[body.GetBase()]; !ok || body.GetBase() == body.GetHead() {
+		writeValidationError(w, "PullRequest", "base", "invalid")
+		return
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	repo.PullRequests = append(repo.PullRequests, PullRequest{
+		ID:        s.newID(),
+		Number:    repo.nextNumber(),
+		Title:     body.GetTitle(),
+		Body:      body.GetBody(),
+		State:     "open",
+		Draft:     body.GetDraft(),
+		User:      s.state.AuthenticatedUser,
+		Head:      body.GetHead(),
+		Base:      body.GetBase(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	writeJSON(w, http.StatusCreated, s.renderPull(repo, &repo.PullRequests[len(repo.PullRequests)-1]))
+}
+
+func (s *Server) getPull(w http.ResponseWriter, r *http.Request, p params) {
+	repo, pr := s.pullOr404(w, p)
+	if pr == nil {
+		return
+	}
+
+	// Как и GitHub, по заголовку Accept отдается diff или patch вместо JSON
+	switch accept := r.Header.Get("Accept"); {
+	case strings.Contains(accept, ".diff"):
+		writeText(w, unifiedDiff(pr.Files))
+		return
+	case strings.Contains(accept, ".patch"):
+		writeText(w, repo.formatPatch(pr))
+		return
+	}
+
+	// Возможность слияния GitHub сообщает только для одного запроса, не в списке
+	pull := s.renderPull(repo, pr)
+	mergeable, state := repo.mergeability(pr)
+	pull.Mergeable, pull.Rebaseable, pull.MergeableState = mergeable, mergeable, github.String(state)
+	writeJSON(w, http.StatusOK, pull)
+}
+
+func (s *Server) listPullFiles(w http.ResponseWriter, r *http.Request, p params) {
+	repo, pr := s.pullOr404(w, p)
+	if pr == nil {
+		return
+	}
+
+	var files []*github.CommitFile
+	for i := range pr.Files {
+		files = append(files, s.renderPullFile(repo, pr, &pr.Files[i]))
+	}
+	writePage(w, r, files)
+}
+
+func (s *Server) listPullCommits(w http.ResponseWriter, r *http.Request, p params) {
+	repo, pr := s.pullOr404(w, p)
+	if pr == nil {
+		return
+	}
+
+	var commits []*github.RepositoryCommit
+	for _, c := range repo.pullCommits(pr) {
+		commits = append(commits, s.renderRepositoryCommit(repo, c))
+	}
+	writePage(w, r, commits)
+}
+
+// pullBody - тело запроса изменения запроса на слияние; nil - поле не меняется
+type pullBody struct {
+	Title *string `json:"title"`
+	Body  *string `json:"body"`
+	State *string `json:"state"`
+	Base  *string `json:"base"`
+}
+
+func (s *Server) editPull(w http.ResponseWriter, r *http.Request, p params) {
+	repo, pr := s.pullOr404(w, p)
+	if pr == nil {
+		return
+	}
+
+	var body pullBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeValidationError(w, "PullRequest", "body", "invalid")
+		return
+	}
+	if body.Title != nil && *body.Title == "" {
+		writeValidationError(w, "PullRequest", "title", "missing_field")
+		return
+	}
+	if body.State != nil && *body.State != "open" && *body.State != "closed" {
+		writeValidationError(w, "PullRequest", "state", "invalid")
+		return
+	}
+	if body.State != nil && pr.MergedAt != nil {
+		writeError(w, http.StatusUnprocessableEntity, "Cannot change the state of a merged pull request")
+		return
+	}
+	if body.State != nil && *body.State == "open" && pr.State == "closed" {
+		if _, ok := repo.Branches[pr.Head]; !ok {
+			writeError(w, http.StatusUnprocessableEntity, "Cannot reopen a pull request whose head branch was deleted")
+			return
+		}
+	}
+	if body.Base != nil {
+		if pr.State != "open" {
+			writeError(w, http.StatusUnprocessableEntity, "Cannot change base branch of closed pull request")
+			return
+		}
+		if _, ok := repo.Branches[*body.Base]; !ok || *body.Base == pr.Head {
+			writeValidationError(w, "PullRequest", "base", "invalid")
+			return
+		}
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if body.Title != nil {
+		pr.Title = *body.Title
+	}
+	if body.Body != nil {
+		pr.Body = *body.Body
+	}
+	if body.Base != nil {
+		pr.Base = *body.Base
+	}
+	if body.State != nil && *body.State != pr.State {
+		pr.State = *body.State
+		pr.ClosedAt = nil
+		if pr.State == "closed" {
+			pr.ClosedAt = timePtr(now)
+		}
+	}
+	pr.UpdatedAt = now
+	writeJSON(w, http.StatusOK, s.renderPull(repo, pr))
+}
+
+func (s *Server) mergePull(w http.ResponseWriter, r *http.Request, p params) {
+	repo, pr := s.pullOr404(w, p)
+	if pr == nil {
+		return
+	}
+
+	var body struct {
+		SHA         string `json:"sha"`
+		MergeMethod string `json:"merge_method"`
+		CommitTitle string `json:"commit_title"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeValidationError(w, "PullRequest", "body", "invalid")
+		return
+	}
+	if body.MergeMethod == "" {
+		body.MergeMethod = "merge"
+	}
+	if body.MergeMethod != "merge" && body.MergeMethod != "squash" && body.MergeMethod != "rebase" {
+		writeValidationError(w, "PullRequest", "merge_method", "invalid")
+		return
+	}
+	head, ok := repo.Branches[pr.Head]
+	if mergeable, _ := repo.mergeability(pr); pr.Draft || !ok || mergeable == nil || !*mergeable {
+		writeError(w, http.StatusMethodNotAllowed, "Pull Request is not mergeable")
+		return
+	}
+	if body.SHA != "" && body.SHA != head {
+		writeError(w, http.StatusConflict, "Head branch was modified. Review and try the merge again.")
+		return
+	}
+
+	// Слияние создает на ветке-назначении один коммит: с двумя родителями для merge и с одним для squash и rebase
+	now := time.Now().UTC().Truncate(time.Second)
+	commit := Commit{
+		SHA:         fmt.Sprintf("%040x", s.newID()),
+		Message:     fmt.Sprintf("%s (#%d)", pr.Title, pr.Number),
+		AuthorLogin: s.state.AuthenticatedUser,
+		AuthorName:  s.state.AuthenticatedUser,
+		Date:        now,
+		Parents:     []string{repo.Branches[pr.Base]},
+	}
+	if body.MergeMethod == "merge" {
+		commit.Message = fmt.Sprintf("Merge pull request #%d from %s/%s", pr.Number, repo.Owner, pr.Head)
+		commit.Parents = append(commit.Parents, head)
+	}
+	if body.CommitTitle != "" {
+		commit.Message = body.CommitTitle
+	}
+	repo.Commits = append(repo.Commits, commit)
+	pr.BaseSHA = repo.Branches[pr.Base]
+	repo.Branches[pr.Base] = commit.SHA
+
+	pr.State = "closed"
+	pr.ClosedAt, pr.MergedAt = timePtr(now), timePtr(now)
+	pr.UpdatedAt = now
+	writeJSON(w, http.StatusOK, &github.PullRequestMergeResult{
+		SHA:     github.String(commit.SHA),
+		Merged:  github.Bool(true),
+		Message: github.String("Pull Request successfully merged"),
+	})
+}
+
+func (s *Server) requestReviewers(w http.ResponseWriter, r *http.Request, p params) {
+	repo, pr := s.pullOr404(w, p)
+	if pr == nil {
+		return
+	}
+
+	var body github.ReviewersRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeValidationError(w, "PullRequest", "reviewers", "invalid")
+		return
+	}
+	for _, login := range body.Reviewers {
+		if login == pr.User {
+			writeError(w, http.StatusUnprocessableEntity, "Review cannot be requested from pull request author.")
+			return
+		}
+		if !repo.canPush(login) {
+			writeError(w, http.StatusUnprocessableEntity, "Reviews may only be requested from collaborators. One or more of the users or teams you specified is not a collaborator of the "+repo.Owner+"/"+repo.Name+" repository.")
+			return
+		}
+	}
+
+	for _, login := range body.Reviewers {
+		if !contains(pr.Reviewers, login) {
+			pr.Reviewers = append(pr.Reviewers, login)
+		}
+	}
+	writeJSON(w, http.StatusCreated, s.renderPull(repo, pr))
+}
+
+func (s *Server) listReviews(w http.ResponseWriter, r *http.Request, p params) {
+	repo, pr := s.pullOr404(w, p)
+	if pr == nil {
+		return
+	}
+
+	var reviews []*github.PullRequestReview
+	for i := range repo.Reviews {
+		review := &repo.Reviews[i]
+		if review.PullNumber == pr.Number {
+			reviews = append(reviews, s.renderReview(repo, review))
+		}
+	}
+	writePage(w, r, reviews)
+}
+
+func (s *Server) listReviewComments(w http.ResponseWriter, r *http.Request, p params) {
+	repo, pr := s.pullOr404(w, p)
+	if pr == nil {
+		return
+	}
+
+	id, _ := strconv.ParseInt(p["id"], 10, 64)
+	review := repo.review(pr.Number, id)
+	if review == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	var comments []*github.PullRequestComment
+	for i := range review.Comments {
+		c := s.renderReviewComment(repo, review, &review.Comments[i])
+		// Как и GitHub, список комментариев ревью не содержит номеров строк
+		c.StartLine, c.Line, c.OriginalStartLine, c.OriginalLine = nil, nil, nil, nil
+		comments = append(comments, c)
+	}
+	writePage(w, r, comments)
+}
+
+func (s *Server) listPullComments(w http.ResponseWriter, r *http.Request, p params) {
+	repo, pr := s.pullOr404(w, p)
+	if pr == nil {
+		return
+	}
+
+	var comments []*github.PullRequestComment
+	for i := range repo.Reviews {
+		review := &repo.Reviews[i]
+		if review.PullNumber != pr.Number {
+			continue
+		}
+		for j := range review.Comments {
+			comments = append(comments, s.renderReviewComment(repo, review, &review.Comments[j]))
+		}
+	}
+	// Как и GitHub, от старых к новым
+	sort.SliceStable(comments, func(i, j int) bool { return comments[i].GetCreatedAt().Before(comments[j].GetCreatedAt()) })
+	writePage(w, r, comments)
+}
+
+func (s *Server) getReviewComment(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	id, _ := strconv.ParseInt(p["id"], 10, 64)
+	review, i := repo.reviewComment(id)
+	if review == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.renderReviewComment(repo, review, &review.Comments[i]))
+}
+
+func (s *Server) createReview(w http.ResponseWriter, r *http.Request, p params) {
+	repo, pr := s.pullOr404(w, p)
+	if pr == nil {
+		return
+	}
+
+	var body github.PullRequestReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeValidationError(w, "PullRequestReview", "body", "invalid")
+		return
+	}
+	states := map[string]string{"APPROVE": "APPROVED", "REQUEST_CHANGES": "CHANGES_REQUESTED", "COMMENT": "COMMENTED"}
+	state, ok := states[body.GetEvent()]
+	if !ok {
+		writeValidationError(w, "PullRequestReview", "event", "invalid")
+		return
+	}
+	// Как и GitHub, автор не может сам одобрить свой запрос на слияние или запросить в нем исправления
+	if state != "COMMENTED" && pr.User == s.state.AuthenticatedUser {
+		message := "Can not approve your own pull request"
+		if state == "CHANGES_REQUESTED" {
+			message = "Can not request changes on your own pull request"
+		}
+		writeError(w, http.StatusUnprocessableEntity, message)
+		return
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	review := Review{
+		ID:          s.newID(),
+		PullNumber:  pr.Number,
+		User:        s.state.AuthenticatedUser,
+		State:       state,
+		Body:        body.GetBody(),
+		CommitID:    body.GetCommitID(),
+		SubmittedAt: now,
+	}
+	if review.CommitID == "" {
+		review.CommitID = repo.Branches[pr.Head]
+	}
+	for _, c := range body.Comments {
+		if c == nil || c.GetPath() == "" || c.GetLine() < 1 || c.GetStartLine() > c.GetLine() {
+			writeValidationError(w, "PullRequestReviewComment", "line", "invalid")
+			return
+		}
+		review.Comments = append(review.Comments, ReviewComment{
+			ID:        s.newID(),
+			User:      review.User,
+			Path:      c.GetPath(),
+			StartLine: c.GetStartLine(),
+			Line:      c.GetLine(),
+			Side:      sideOrRight(c.GetSide()),
+			Body:      c.GetBody(),
+			CommitID:  review.CommitID,
+			CreatedAt: now,
+			UpdatedAt: timePtr(now),
+		})
+	}
+
+	repo.Reviews = append(repo.Reviews, review)
+	writeJSON(w, http.StatusOK, s.renderReview(repo, &repo.Reviews[len(repo.Reviews)-1]))
+}
+
+func (s *Server) dismissReview(w http.ResponseWriter, r *http.Request, p params) {
+	repo, pr := s.pullOr404(w, p)
+	if pr == nil {
+		return
+	}
+
+	id, _ := strconv.ParseInt(p["id"], 10, 64)
+	review := repo.review(pr.Number, id)
+	if review == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	var body github.PullRequestReviewDismissalRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.GetMessage() == "" {
+		writeValidationError(w, "PullRequestReview", "message", "missing_field")
+		return
+	}
+	if review.State != "APPROVED" && review.State != "CHANGES_REQUESTED" {
+		writeError(w, http.StatusUnprocessableEntity, "Can not dismiss a "+strings.ToLower(review.State)+" pull request review")
+		return
+	}
+
+	review.State = "DISMISSED"
+	writeJSON(w, http.StatusOK, s.renderReview(repo, review))
+}
+
+// pullCommentBody - тело запроса создания комментария к коду: новый комментарий или ответ в обсуждение
+type pullCommentBody struct {
+	Body      string `json:"body"`
+	InReplyTo int64  `json:"in_reply_to"`
+	CommitID  string `json:"commit_id"`
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"`
+	Line      int    `json:"line"`
+	Side      string `json:"side"`
+}
+
+func (s *Server) createPullComment(w http.ResponseWriter, r *http.Request, p params) {
+	repo, pr := s.pullOr404(w, p)
+	if pr == nil {
+		return
+	}
+
+	var body pullCommentBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Body == "" {
+		writeValidationError(w, "PullRequestReviewComment", "body", "missing_field")
+		return
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	c := ReviewComment{
+		ID:        s.newID(),
+		User:      s.state.AuthenticatedUser,
+		Body:      body.Body,
+		CreatedAt: now,
+		UpdatedAt: timePtr(now),
+	}
+	if body.InReplyTo != 0 {
+		// Ответ наследует место первого комментария обсуждения
+		review, i := repo.reviewComment(body.InReplyTo)
+		if review == nil || review.PullNumber != pr.Number {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		parent := review.Comments[i]
+		c.InReplyTo = parent.ID
+		if parent.InReplyTo != 0 {
+			c.InReplyTo = parent.InReplyTo
+		}
+		c.Path, c.StartLine, c.Line, c.Side = parent.Path, parent.StartLine, parent.Line, parent.Side
+		c.CommitID, c.DiffHunk, c.Outdated = parent.CommitID, parent.DiffHunk, parent.Outdated
+	} else {
+		switch {
+		case body.CommitID == "":
+			writeValidationError(w, "PullRequestReviewComment", "commit_id", "missing_field")
+			return
+		case repo.commit(body.CommitID) == nil:
+			writeValidationError(w, "PullRequestReviewComment", "commit_id", "invalid")
+			return
+		case body.Path == "":
+			writeValidationError(w, "PullRequestReviewComment", "path", "missing_field")
+			return
+		case body.Line < 1 || body.StartLine > body.Line:
+			writeValidationError(w, "PullRequestReviewComment", "line", "invalid")
+			return
+		}
+		c.Path, c.StartLine, c.Line, c.Side, c.CommitID = body.Path, body.StartLine, body.Line, sideOrRight(body.Side), body.CommitID
+	}
+
+	// Как и на GitHub, отдельный комментарий создает ревью с одним этим комментарием
+	repo.Reviews = append(repo.Reviews, Review{
+		ID:          s.newID(),
+		PullNumber:  pr.Number,
+		User:        c.User,
+		State:       "COMMENTED",
+		CommitID:    c.CommitID,
+		SubmittedAt: now,
+		Comments:    []ReviewComment{c},
+	})
+	review := &repo.Reviews[len(repo.Reviews)-1]
+	writeJSON(w, http.StatusCreated, s.renderReviewComment(repo, review, &review.Comments[0]))
+}
+
+func (s *Server) editReviewComment(w http.ResponseWriter, r *http.Request, p params) {
+	repo, review, i := s.ownReviewCommentOr404(w, p)
+	if review == nil {
+		return
+	}
+
+	var body github.PullRequestComment
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.GetBody() == "" {
+		writeValidationError(w, "PullRequestReviewComment", "body", "missing_field")
+		return
+	}
+
+	c := &review.Comments[i]
+	c.Body = body.GetBody()
+	c.UpdatedAt = timePtr(time.Now().UTC().Truncate(time.Second))
+	writeJSON(w, http.StatusOK, s.renderReviewComment(repo, review, c))
+}
+
+func (s *Server) deleteReviewComment(w http.ResponseWriter, r *http.Request, p params) {
+	_, review, i := s.ownReviewCommentOr404(w, p)
+	if review == nil {
+		return
+	}
+
+	review.Comments = append(review.Comments[:i], review.Comments[i+1:]...)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ownReviewCommentOr404 находит комментарий к коду по {id}. Чужой комментарий, как и отсутствующий,
+// не возвращается: в ответ уже записана ошибка 403 или 404
+func (s *Server) ownReviewCommentOr404(w http.ResponseWriter, p params) (*Repository, *Review, int) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return nil, nil, -1
+	}
+
+	id, _ := strconv.ParseInt(p["id"], 10, 64)
+	review, i := repo.reviewComment(id)
+	if review == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return repo, nil, -1
+	}
+	if review.Comments[i].User != s.state.AuthenticatedUser {
+		writeError(w, http.StatusForbidden, "Must have admin rights to Repository.")
+		return repo, nil, -1
+	}
+	return repo, review, i
+}
+
+// sideOrRight возвращает сторону diff комментария, по умолчанию RIGHT
+func sideOrRight(side string) string {
+	if side == "" {
+		return "RIGHT"
+	}
+	return side
+}
+
+func (s *Server) listIssues(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	state := r.URL.Query().Get("state")
+	if state == "" {
+		state = "open"
+	}
+
+	var issues []*github.Issue
+	for i := range repo.Issues {
+		issue := &repo.Issues[i]
+		if state == "all" || issue.State == state {
+			issues = append(issues, s.renderIssue(repo, issue))
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].GetNumber() > issues[j].GetNumber() })
+	writePage(w, r, issues)
+}
+
+// issueBody - тело запроса создания или изменения проблемы; nil - поле не меняется
+type issueBody struct {
+	Title       *string   `json:"title"`
+	Body        *string   `json:"body"`
+	Labels      *[]string `json:"labels"`
+	Assignees   *[]string `json:"assignees"`
+	Milestone   *int      `json:"milestone"`
+	State       *string   `json:"state"`
+	StateReason *string   `json:"state_reason"`
+}
+
+// validate возвращает поле, которое GitHub отклонил бы, и код ошибки ("" - тело корректно)
+func (b *issueBody) validate(repo *Repository) (field, code string) {
+	switch {
+	case b.Title != nil && *b.Title == "":
+		return "title", "missing_field"
+	case b.Milestone != nil && repo.milestone(*b.Milestone) == nil:
+		return "milestone", "invalid"
+	case b.State != nil && *b.State != "open" && *b.State != "closed":
+		return "state", "invalid"
+	case b.StateReason != nil && *b.StateReason != "completed" && *b.StateReason != "not_planned" && *b.StateReason != "reopened":
+		return "state_reason", "invalid"
+	}
+	if b.Assignees != nil {
+		for _, login := range *b.Assignees {
+			if !repo.canPush(login) {
+				return "assignees", "invalid"
+			}
+		}
+	}
+	return "", ""
+}
+
+func (s *Server) createIssue(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	var body issueBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Title == nil {
+		writeValidationError(w, "Issue", "title", "missing_field")
+		return
+	}
+	if field, code := body.validate(repo); field != "" {
+		writeValidationError(w, "Issue", field, code)
+		return
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	repo.Issues = append(repo.Issues, Issue{
+		ID:        s.newID(),
+		Number:    repo.nextNumber(),
+		State:     "open",
+		User:      s.state.AuthenticatedUser,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	issue := &repo.Issues[len(repo.Issues)-1]
+	body.apply(issue, now)
+	writeJSON(w, http.StatusCreated, s.renderIssue(repo, issue))
+}
+
+func (s *Server) getIssue(w http.ResponseWriter, r *http.Request, p params) {
+	repo, issue := s.issueOr404(w, p)
+	if issue == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.renderIssue(repo, issue))
+}
+
+func (s *Server) editIssue(w http.ResponseWriter, r *http.Request, p params) {
+	repo, issue := s.issueOr404(w, p)
+	if issue == nil {
+		return
+	}
+
+	var body issueBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeValidationError(w, "Issue", "body", "invalid")
+		return
+	}
+	if field, code := body.validate(repo); field != "" {
+		writeValidationError(w, "Issue", field, code)
+		return
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	body.apply(issue, now)
+	issue.UpdatedAt = now
+	writeJSON(w, http.StatusOK, s.renderIssue(repo, issue))
+}
+
+// apply переносит в проблему поля, заданные в теле запроса
+func (b *issueBody) apply(issue *Issue, now time.Time) {
+	if b.Title != nil {
+		issue.Title = *b.Title
+	}
+	if b.Body != nil {
+		issue.Body = *b.Body
+	}
+	if b.Labels != nil {
+		issue.Labels = append([]string(nil), *b.Labels...)
+	}
+	if b.Assignees != nil {
+		issue.Assignees = append([]string(nil), *b.Assignees...)
+	}
+	if b.Milestone != nil {
+		issue.Milestone = *b.Milestone
+	}
+	if b.State != nil && *b.State != issue.State {
+		issue.State = *b.State
+		issue.ClosedAt = nil
+		if issue.State == "closed" {
+			issue.ClosedAt = timePtr(now)
+		}
+	}
+	if b.StateReason != nil {
+		issue.StateReason = *b.StateReason
+	}
+}
+
+func (s *Server) lockIssue(w http.ResponseWriter, r *http.Request, p params) {
+	repo, issue := s.issueOr404(w, p)
+	if issue == nil {
+		return
+	}
+
+	var body struct {
+		LockReason string `json:"lock_reason"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeValidationError(w, "Issue", "lock_reason", "invalid")
+			return
+		}
+	}
+	switch body.LockReason {
+	case "", "off-topic", "too heated", "resolved", "spam":
+	default:
+		writeValidationError(w, "Issue", "lock_reason", "invalid")
+		return
+	}
+	if !repo.canPush(s.state.AuthenticatedUser) {
+		writeError(w, http.StatusForbidden, "Must have push access to lock an issue.")
+		return
+	}
+
+	issue.Locked, issue.LockReason = true, body.LockReason
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) unlockIssue(w http.ResponseWriter, r *http.Request, p params) {
+	repo, issue := s.issueOr404(w, p)
+	if issue == nil {
+		return
+	}
+	if !repo.canPush(s.state.AuthenticatedUser) {
+		writeError(w, http.StatusForbidden, "Must have push access to unlock an issue.")
+		return
+	}
+
+	issue.Locked, issue.LockReason = false, ""
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listIssueComments(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	// Общее обсуждение есть и у проблем, и у запросов на слияние
+	number, _ := strconv.Atoi(p["number"])
+	if repo.pull(number) == nil && repo.issue(number) == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	var comments []*github.IssueComment
+	for i := range repo.IssueComments {
+		if repo.IssueComments[i].IssueNumber == number {
+			comments = append(comments, s.renderIssueComment(repo, &repo.IssueComments[i]))
+		}
+	}
+	sort.SliceStable(comments, func(i, j int) bool { return comments[i].GetCreatedAt().Before(comments[j].GetCreatedAt()) })
+	writePage(w, r, comments)
+}
+
+func (s *Server) createIssueComment(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	number, _ := strconv.Atoi(p["number"])
+	if repo.pull(number) == nil && repo.issue(number) == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	var body github.IssueComment
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.GetBody() == "" {
+		writeValidationError(w, "IssueComment", "body", "missing_field")
+		return
+	}
+	if issue := repo.issue(number); issue != nil && issue.Locked && !repo.canPush(s.state.AuthenticatedUser) {
+		writeError(w, http.StatusForbidden, "Unable to create comment because issue is locked.")
+		return
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	repo.IssueComments = append(repo.IssueComments, IssueComment{
+		ID:          s.newID(),
+		IssueNumber: number,
+		User:        s.state.AuthenticatedUser,
+		Body:        body.GetBody(),
+		CreatedAt:   now,
+		UpdatedAt:   timePtr(now),
+	})
+	writeJSON(w, http.StatusCreated, s.renderIssueComment(repo, &repo.IssueComments[len(repo.IssueComments)-1]))
+}
+
+func (s *Server) addLabels(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	number, _ := strconv.Atoi(p["number"])
+	var labels *[]string
+	if pr := repo.pull(number); pr != nil {
+		labels = &pr.Labels
+	} else if issue := repo.issue(number); issue != nil {
+		labels = &issue.Labels
+	} else {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	// Как и GitHub, принимается массив названий; отсутствующие в репозитории метки создаются
+	var names []string
+	if err := json.NewDecoder(r.Body).Decode(&names); err != nil || len(names) == 0 {
+		writeValidationError(w, "Label", "labels", "missing_field")
+		return
+	}
+	for _, name := range names {
+		if !contains(*labels, name) {
+			*labels = append(*labels, name)
+		}
+	}
+	writeJSON(w, http.StatusOK, renderLabels(*labels))
+}
+
+func (s *Server) getIssueComment(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	id, _ := strconv.ParseInt(p["id"], 10, 64)
+	i := repo.issueComment(id)
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.renderIssueComment(repo, &repo.IssueComments[i]))
+}
+
+func (s *Server) editIssueComment(w http.ResponseWriter, r *http.Request, p params) {
+	repo, i := s.ownIssueCommentOr404(w, p)
+	if i < 0 {
+		return
+	}
+
+	var body github.IssueComment
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.GetBody() == "" {
+		writeValidationError(w, "IssueComment", "body", "missing_field")
+		return
+	}
+
+	c := &repo.IssueComments[i]
+	c.Body = body.GetBody()
+	c.UpdatedAt = timePtr(time.Now().UTC().Truncate(time.Second))
+	writeJSON(w, http.StatusOK, s.renderIssueComment(repo, c))
+}
+
+func (s *Server) deleteIssueComment(w http.ResponseWriter, r *http.Request, p params) {
+	repo, i := s.ownIssueCommentOr404(w, p)
+	if i < 0 {
+		return
+	}
+
+	repo.IssueComments = append(repo.IssueComments[:i], repo.IssueComments[i+1:]...)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ownIssueCommentOr404 находит комментарий общего обсуждения по {id}, как ownReviewCommentOr404
+func (s *Server) ownIssueCommentOr404(w http.ResponseWriter, p params) (*Repository, int) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return nil, -1
+	}
+
+	id, _ := strconv.ParseInt(p["id"], 10, 64)
+	i := repo.issueComment(id)
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return repo, -1
+	}
+	if repo.IssueComments[i].User != s.state.AuthenticatedUser {
+		writeError(w, http.StatusForbidden, "Must have admin rights to Repository.")
+		return repo, -1
+	}
+	return repo, i
+}
+
+func (s *Server) listTags(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	var tags []*github.RepositoryTag
+	for _, tag := range repo.Tags {
+		tags = append(tags, &github.RepositoryTag{
+			Name: github.String(tag.Name),
+			Commit: &github.Commit{
+				SHA: github.String(tag.SHA),
+				URL: github.String(s.apiURL("repos/%s/%s/commits/%s", repo.Owner, repo.Name, tag.SHA)),
+			},
+			ZipballURL: github.String(s.apiURL("repos/%s/%s/zipball/refs/tags/%s", repo.Owner, repo.Name, tag.Name)),
+			TarballURL: github.String(s.apiURL("repos/%s/%s/tarball/refs/tags/%s", repo.Owner, repo.Name, tag.Name)),
+		})
+	}
+	writePage(w, r, tags)
+}
+
+func (s *Server) listMatchingRefs(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	var refs []*github.Reference
+	for _, name := range repo.branchNames() {
+		refs = append(refs, s.renderRef(repo, "heads/"+name, "commit", repo.Branches[name]))
+	}
+	for _, tag := range repo.Tags {
+		if tag.Annotation != nil {
+			refs = append(refs, s.renderRef(repo, "tags/"+tag.Name, "tag", tag.Annotation.SHA))
+		} else {
+			refs = append(refs, s.renderRef(repo, "tags/"+tag.Name, "commit", tag.SHA))
+		}
+	}
+
+	// Как и GitHub, отдаем ссылки, начинающиеся с указанного префикса
+	var matched []*github.Reference
+	for _, ref := range refs {
+		if strings.HasPrefix(ref.GetRef(), "refs/"+p["ref"]) {
+			matched = append(matched, ref)
+		}
+	}
+	writePage(w, r, matched)
+}
+
+func (s *Server) getGitTag(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	for _, tag := range repo.Tags {
+		if tag.Annotation != nil && tag.Annotation.SHA == p["sha"] {
+			writeJSON(w, http.StatusOK, s.renderGitTag(repo, tag))
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+func (s *Server) listReleases(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	var releases []*github.RepositoryRelease
+	for i := range repo.Releases {
+		releases = append(releases, s.renderRelease(repo, &repo.Releases[i]))
+	}
+	sort.SliceStable(releases, func(i, j int) bool { return releases[i].GetCreatedAt().After(releases[j].GetCreatedAt().Time) })
+	writePage(w, r, releases)
+}
+
+func (s *Server) getReleaseByTag(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	// Черновики по тегу не находятся
+	for i := range repo.Releases {
+		release := &repo.Releases[i]
+		if release.TagName == p["tag"] && !release.Draft {
+			writeJSON(w, http.StatusOK, s.renderRelease(repo, release))
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+// releaseBody - тело запроса создания или изменения релиза. Указатели отличают
+// отсутствующие поля от нулевых значений
+type releaseBody struct {
+	TagName         *string `json:"tag_name"`
+	TargetCommitish *string `json:"target_commitish"`
+	Name            *string `json:"name"`
+	Body            *string `json:"body"`
+	Draft           *bool   `json:"draft"`
+	Prerelease      *bool   `json:"prerelease"`
+	MakeLatest      *string `json:"make_latest"`
+}
+
+func (s *Server) createRelease(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	var body releaseBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.TagName == nil || *body.TagName == "" {
+		writeValidationError(w, "Release", "tag_name", "missing_field")
+		return
+	}
+	for _, release := range repo.Releases {
+		if release.TagName == *body.TagName {
+			writeValidationError(w, "Release", "tag_name", "already_exists")
+			return
+		}
+	}
+
+	repo.Releases = append(repo.Releases, Release{
+		ID:        s.newID(),
+		TagName:   *body.TagName,
+		Target:    "main",
+		Author:    s.state.AuthenticatedUser,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	})
+	release := &repo.Releases[len(repo.Releases)-1]
+	if !s.applyRelease(w, repo, release, &body) {
+		repo.Releases = repo.Releases[:len(repo.Releases)-1]
+		return
+	}
+	writeJSON(w, http.StatusCreated, s.renderRelease(repo, release))
+}
+
+func (s *Server) getRelease(w http.ResponseWriter, r *http.Request, p params) {
+	repo, release := s.releaseOr404(w, p)
+	if release == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.renderRelease(repo, release))
+}
+
+func (s *Server) getLatestRelease(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	// Явно отмеченный релиз, иначе самый новый опубликованный не предварительный
+	var latest *Release
+	for i := range repo.Releases {
+		release := &repo.Releases[i]
+		if release.Draft {
+			continue
+		}
+		if release.Latest {
+			latest = release
+			break
+		}
+		if !release.Prerelease && (latest == nil || release.CreatedAt.After(latest.CreatedAt)) {
+			latest = release
+		}
+	}
+	if latest == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.renderRelease(repo, latest))
+}
+
+func (s *Server) editRelease(w http.ResponseWriter, r *http.Request, p params) {
+	repo, release := s.releaseOr404(w, p)
+	if release == nil {
+		return
+	}
+
+	var body releaseBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeValidationError(w, "Release", "body", "invalid")
+		return
+	}
+	if body.TagName != nil && *body.TagName != release.TagName {
+		writeValidationError(w, "Release", "tag_name", "invalid")
+		return
+	}
+
+	// Изменения применяются к копии, чтобы ошибка проверки не оставила релиз измененным наполовину
+	edited := *release
+	if !s.applyRelease(w, repo, &edited, &body) {
+		return
+	}
+	*release = edited
+	writeJSON(w, http.StatusOK, s.renderRelease(repo, release))
+}
+
+// applyRelease переносит в release поля запроса. Как и GitHub, при публикации создает тег релиза
+// на release.Target (по умолчанию - ветка main), если тега еще нет.
+// Возвращает false, если поля некорректны и ответ уже отправлен
+func (s *Server) applyRelease(w http.ResponseWriter, repo *Repository, release *Release, body *releaseBody) bool {
+	if body.MakeLatest != nil && *body.MakeLatest != "true" && *body.MakeLatest != "false" && *body.MakeLatest != "legacy" {
+		writeValidationError(w, "Release", "make_latest", "invalid")
+		return false
+	}
+	if body.TargetCommitish != nil {
+		release.Target = *body.TargetCommitish
+	}
+	if body.Name != nil {
+		release.Name = *body.Name
+	}
+	if body.Body != nil {
+		release.Body = *body.Body
+	}
+	if body.Prerelease != nil {
+		release.Prerelease = *body.Prerelease
+	}
+	if body.Draft != nil {
+		release.Draft = *body.Draft
+	}
+
+	if release.Draft {
+		release.PublishedAt = nil
+	} else if release.PublishedAt == nil {
+		if repo.tag(release.TagName) < 0 {
+			sha, ok := repo.Branches[release.Target]
+			if !ok {
+				sha = release.Target
+			}
+			if repo.commit(sha) == nil {
+				writeValidationError(w, "Release", "target_commitish", "invalid")
+				return false
+			}
+			repo.Tags = append(repo.Tags, Tag{Name: release.TagName, SHA: sha})
+		}
+		release.PublishedAt = timePtr(time.Now().UTC().Truncate(time.Second))
+	}
+
+	if body.MakeLatest != nil {
+		if *body.MakeLatest == "true" {
+			for i := range repo.Releases {
+				repo.Releases[i].Latest = false
+			}
+		}
+		release.Latest = *body.MakeLatest == "true"
+	}
+	return true
+}
+
+func (s *Server) deleteRelease(w http.ResponseWriter, r *http.Request, p params) {
+	repo, release := s.releaseOr404(w, p)
+	if release == nil {
+		return
+	}
+
+	for i := range repo.Releases {
+		if &repo.Releases[i] == release {
+			repo.Releases = append(repo.Releases[:i], repo.Releases[i+1:]...)
+			break
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listReleaseAssets(w http.ResponseWriter, r *http.Request, p params) {
+	repo, release := s.releaseOr404(w, p)
+	if release == nil {
+		return
+	}
+
+	var assets []*github.ReleaseAsset
+	for i := range release.Assets {
+		assets = append(assets, s.renderAsset(repo, release, &release.Assets[i]))
+	}
+	writePage(w, r, assets)
+}
+
+func (s *Server) uploadReleaseAsset(w http.ResponseWriter, r *http.Request, p params) {
+	repo, release := s.releaseOr404(w, p)
+	if release == nil {
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		writeValidationError(w, "ReleaseAsset", "name", "missing_field")
+		return
+	}
+	for _, asset := range release.Assets {
+		if asset.Name == name {
+			writeValidationError(w, "ReleaseAsset", "name", "already_exists")
+			return
+		}
+	}
+	content, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	release.Assets = append(release.Assets, Asset{
+		ID:          s.newID(),
+		Name:        name,
+		ContentType: contentType,
+		Content:     content,
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+	})
+	writeJSON(w, http.StatusCreated, s.renderAsset(repo, release, &release.Assets[len(release.Assets)-1]))
+}
+
+func (s *Server) getReleaseAsset(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	id, _ := strconv.ParseInt(p["id"], 10, 64)
+	for i := range repo.Releases {
+		release := &repo.Releases[i]
+		for j := range release.Assets {
+			asset := &release.Assets[j]
+			if asset.ID != id {
+				continue
+			}
+			// Как и GitHub, за содержимым файла перенаправляем на browser_download_url
+			if r.Header.Get("Accept") == "application/octet-stream" {
+				http.Redirect(w, r, s.downloadURL(repo, release, asset), http.StatusFound)
+				return
+			}
+			writeJSON(w, http.StatusOK, s.renderAsset(repo, release, asset))
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+func (s *Server) downloadReleaseAsset(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repo(p["owner"], p["repo"])
+	if repo == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	for i := range repo.Releases {
+		release := &repo.Releases[i]
+		if release.TagName != p["tag"] {
+			continue
+		}
+		for j := range release.Assets {
+			asset := &release.Assets[j]
+			if asset.Name == p["name"] {
+				asset.DownloadCount++
+				w.Header().Set("Content-Type", asset.ContentType)
+				w.Header().Set("Content-Length", strconv.Itoa(len(asset.Content)))
+				w.WriteHeader(http.StatusOK)
+				w.Write(asset.Content)
+				return
+			}
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+func (s *Server) addCollaborator(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	login := p["user"]
+	if s.user(login) == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	for _, c := range repo.Collaborators {
+		if c == login {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	for _, inv := range repo.Invitations {
+		if inv.Invitee == login {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	// Как и GitHub, доступ открывается только после принятия приглашения
+	inv := Invitation{ID: s.newID(), Invitee: login}
+	repo.Invitations = append(repo.Invitations, inv)
+	writeJSON(w, http.StatusCreated, s.renderInvitation(repo, &inv))
+}
+
+func (s *Server) removeCollaborator(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	for i, c := range repo.Collaborators {
+		if c == p["user"] {
+			repo.Collaborators = append(repo.Collaborators[:i], repo.Collaborators[i+1:]...)
+			break
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listInvitations(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	var invitations []*github.RepositoryInvitation
+	for i := range repo.Invitations {
+		invitations = append(invitations, s.renderInvitation(repo, &repo.Invitations[i]))
+	}
+	writePage(w, r, invitations)
+}
+
+func (s *Server) deleteInvitation(w http.ResponseWriter, r *http.Request, p params) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return
+	}
+
+	id, _ := strconv.ParseInt(p["id"], 10, 64)
+	for i := range repo.Invitations {
+		if repo.Invitations[i].ID == id {
+			repo.Invitations = append(repo.Invitations[:i], repo.Invitations[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+func (s *Server) user(login string) *User {
+	for i := range s.state.Users {
+		if s.state.Users[i].Login == login {
+			return &s.state.Users[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) repo(owner, name string) *Repository {
+	for i := range s.state.Repositories {
+		if s.state.Repositories[i].Owner == owner && s.state.Repositories[i].Name == name {
+			return &s.state.Repositories[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) repoOr404(w http.ResponseWriter, p params) *Repository {
+	repo := s.repo(p["owner"], p["repo"])
+	if repo == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+	return repo
+}
+
+func (s *Server) pullOr404(w http.ResponseWriter, p params) (*Repository, *PullRequest) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return nil, nil
+	}
+
+	number, _ := strconv.Atoi(p["number"])
+	if pr := repo.pull(number); pr != nil {
+		return repo, pr
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+	return repo, nil
+}
+
+func (s *Server) issueOr404(w http.ResponseWriter, p params) (*Repository, *Issue) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return nil, nil
+	}
+
+	number, _ := strconv.Atoi(p["number"])
+	if issue := repo.issue(number); issue != nil {
+		return repo, issue
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+	return repo, nil
+}
+
+func (s *Server) releaseOr404(w http.ResponseWriter, p params) (*Repository, *Release) {
+	repo := s.repoOr404(w, p)
+	if repo == nil {
+		return nil, nil
+	}
+
+	id, _ := strconv.ParseInt(p["id"], 10, 64)
+	for i := range repo.Releases {
+		if repo.Releases[i].ID == id {
+			return repo, &repo.Releases[i]
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+	return repo, nil
+}
+
+func (r *Repository) pull(number int) *PullRequest {
+	for i := range r.PullRequests {
+		if r.PullRequests[i].Number == number {
+			return &r.PullRequests[i]
+		}
+	}
+	return nil
+}
+
+func (r *Repository) issue(number int) *Issue {
+	for i := range r.Issues {
+		if r.Issues[i].Number == number {
+			return &r.Issues[i]
+		}
+	}
+	return nil
+}
+
+func (r *Repository) milestone(number int) *Milestone {
+	for i := range r.Milestones {
+		if r.Milestones[i].Number == number {
+			return &r.Milestones[i]
+		}
+	}
+	return nil
+}
+
+// issueCommentCount возвращает количество комментариев в общем обсуждении проблемы
+func (r *Repository) issueCommentCount(number int) int {
+	count := 0
+	for _, c := range r.IssueComments {
+		if c.IssueNumber == number {
+			count++
+		}
+	}
+	return count
+}
+
+// canPush сообщает, может ли пользователь login изменять репозиторий
+func (r *Repository) canPush(login string) bool {
+	return login == r.Owner || contains(r.Collaborators, login)
+}
+
+func (r *Repository) commit(sha string) *Commit {
+	for i := range r.Commits {
+		if r.Commits[i].SHA == sha {
+			return &r.Commits[i]
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// branchNames возвращает отсортированные имена веток
+func (r *Repository) branchNames() []string {
+	var names []string
+	for name := range r.Branches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *Repository) tag(name string) int {
+	for i, tag := range r.Tags {
+		if tag.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *Repository) review(pullNumber int, id int64) *Review {
+	for i := range r.Reviews {
+		if r.Reviews[i].PullNumber == pullNumber && r.Reviews[i].ID == id {
+			return &r.Reviews[i]
+		}
+	}
+	return nil
+}
+
+// reviewComment находит комментарий к коду по идентификатору: возвращает его ревью и индекс
+// в нем или nil, -1
+func (r *Repository) reviewComment(id int64) (*Review, int) {
+	for i := range r.Reviews {
+		for j := range r.Reviews[i].Comments {
+			if r.Reviews[i].Comments[j].ID == id {
+				return &r.Reviews[i], j
+			}
+		}
+	}
+	return nil, -1
+}
+
+func (r *Repository) issueComment(id int64) int {
+	for i := range r.IssueComments {
+		if r.IssueComments[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// pullCommits возвращает коммиты ветки-источника, которых нет в ветке-назначении, от старых к новым.
+// Для слитого запроса ветка-назначение берется в состоянии до слияния
+func (r *Repository) pullCommits(pr *PullRequest) []*Commit {
+	head, ok := r.Branches[pr.Head]
+	if !ok {
+		return nil
+	}
+	base := pr.BaseSHA
+	if base == "" {
+		base = r.Branches[pr.Base]
+	}
+
+	var commits []*Commit
+	for _, sha := range gitmodel.Ahead(r.graph, head, base) {
+		commits = append(commits, r.commit(sha))
+	}
+	return commits
+}
+
+// mergeability вычисляет возможность слияния запроса, как GitHub: nil и unknown для закрытого,
+// false и dirty, если обе ветки после общего предка меняли одни и те же файлы
+func (r *Repository) mergeability(pr *PullRequest) (*bool, string) {
+	head, ok := r.Branches[pr.Head]
+	if pr.State != "open" || !ok || pr.MergeableState == "unknown" {
+		return nil, "unknown"
+	}
+
+	conflicts := gitmodel.Conflicts(r.graph, head, r.Branches[pr.Base])
+	mergeable := !conflicts
+	switch {
+	case pr.MergeableState != "":
+		return &mergeable, pr.MergeableState
+	case conflicts:
+		return &mergeable, "dirty"
+	case pr.Draft:
+		return &mergeable, "draft"
+	}
+	return &mergeable, "clean"
+}
+
+// formatPatch собирает изменения запроса в формате git format-patch
+func (r *Repository) formatPatch(pr *PullRequest) string {
+	var commits []gitmodel.PatchCommit
+	for _, c := range r.pullCommits(pr) {
+		commits = append(commits, gitmodel.PatchCommit{SHA: c.SHA, Author: c.AuthorName + " <" + c.AuthorEmail + ">", Date: c.Date, Subject: c.Message})
+	}
+	return gitmodel.FormatPatch(commits, unifiedDiff(pr.Files))
+}
+
+// unifiedDiff собирает изменения файлов в один diff в формате git
+func unifiedDiff(files []PullFile) string {
+	var diff []gitmodel.File
+	for _, f := range files {
+		diff = append(diff, gitmodel.File{Filename: f.Filename, PreviousFilename: f.PreviousFilename, Status: f.Status, Patch: f.Patch})
+	}
+	return gitmodel.UnifiedDiff(diff)
+}
+
+// nextNumber выдает номер для нового запроса на слияние или проблемы: они делят одну нумерацию
+func (r *Repository) nextNumber() int {
+	number := 1
+	for _, pr := range r.PullRequests {
+		if pr.Number >= number {
+			number = pr.Number + 1
+		}
+	}
+	for _, issue := range r.Issues {
+		if issue.Number >= number {
+			number = issue.Number + 1
+		}
+	}
+	return number
+}
+
+// history возвращает коммиты, достижимые из head, в порядке gitmodel.History
+func (r *Repository) history(head string) []*Commit {
+	var commits []*Commit
+	for _, sha := range gitmodel.History(r.graph, head) {
+		commits = append(commits, r.commit(sha))
+	}
+	return commits
+}
+
+// graph находит коммит репозитория для gitmodel
+func (r *Repository) graph(sha string) (gitmodel.Node, bool) {
+	c := r.commit(sha)
+	if c == nil {
+		return gitmodel.Node{}, false
+	}
+	return gitmodel.Node{Parents: c.Parents, Date: c.Date, Files: c.Files}, true
+}
@@ -0,0 +1,374 @@
+package ghsim
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// Функции render* строят ответы в формате GitHub API из состояния симулятора
+
+func (s *Server) renderUser(u *User) *github.User {
+	return &github.User{
+		Login:     github.String(u.Login),
+		ID:        github.Int64(u.ID),
+		Name:      github.String(u.Name),
+		Followers: github.Int(u.Followers),
+		Following: github.Int(u.Following),
+		Type:      github.String("User"),
+		URL:       github.String(s.apiURL("users/%s", u.Login)),
+		HTMLURL:   github.String(htmlURL("%s", u.Login)),
+	}
+}
+
+// renderLogin возвращает краткое представление пользователя, как во вложенных объектах API
+func (s *Server) renderLogin(login string) *github.User {
+	u := &github.User{
+		Login: github.String(login),
+		Type:  github.String("User"),
+		URL:   github.String(s.apiURL("users/%s", login)),
+	}
+	if user := s.user(login); user != nil {
+		u.ID = github.Int64(user.ID)
+	}
+	return u
+}
+
+func (s *Server) renderLogins(logins []string) []*github.User {
+	var users []*github.User
+	for _, login := range logins {
+		users = append(users, s.renderLogin(login))
+	}
+	return users
+}
+
+func renderLabels(names []string) []*github.Label {
+	var labels []*github.Label
+	for _, name := range names {
+		labels = append(labels, &github.Label{Name: github.String(name)})
+	}
+	return labels
+}
+
+func (s *Server) renderRepo(r *Repository) *github.Repository {
+	return &github.Repository{
+		Name:            github.String(r.Name),
+		FullName:        github.String(r.Owner + "/" + r.Name),
+		Owner:           s.renderLogin(r.Owner),
+		Description:     github.String(r.Description),
+		Private:         github.Bool(r.Private),
+		StargazersCount: github.Int(r.Stars),
+		ForksCount:      github.Int(r.Forks),
+		UpdatedAt:       &github.Timestamp{Time: r.UpdatedAt},
+		URL:             github.String(s.apiURL("repos/%s/%s", r.Owner, r.Name)),
+		HTMLURL:         github.String(htmlURL("%s/%s", r.Owner, r.Name)),
+	}
+}
+
+func (s *Server) renderGitCommit(r *Repository, c *Commit) *github.Commit {
+	commit := &github.Commit{
+		SHA:     github.String(c.SHA),
+		Message: github.String(c.Message),
+		Author:  renderAuthor(c),
+		URL:     github.String(s.apiURL("repos/%s/%s/git/commits/%s", r.Owner, r.Name, c.SHA)),
+		HTMLURL: github.String(htmlURL("%s/%s/commit/%s", r.Owner, r.Name, c.SHA)),
+	}
+	for _, parent := range c.Parents {
+		commit.Parents = append(commit.Parents, &github.Commit{
+			SHA: github.String(parent),
+			URL: github.String(s.apiURL("repos/%s/%s/git/commits/%s", r.Owner, r.Name, parent)),
+		})
+	}
+	return commit
+}
+
+func (s *Server) renderRepositoryCommit(r *Repository, c *Commit) *github.RepositoryCommit {
+	commit := &github.RepositoryCommit{
+		SHA: github.String(c.SHA),
+		// Как и GitHub, вложенный коммит git не содержит SHA, только адрес
+		Commit: &github.Commit{
+			Message: github.String(c.Message),
+			Author:  renderAuthor(c),
+			URL:     github.String(s.apiURL("repos/%s/%s/git/commits/%s", r.Owner, r.Name, c.SHA)),
+		},
+		URL:     github.String(s.apiURL("repos/%s/%s/commits/%s", r.Owner, r.Name, c.SHA)),
+		HTMLURL: github.String(htmlURL("%s/%s/commit/%s", r.Owner, r.Name, c.SHA)),
+	}
+	if c.AuthorLogin != "" {
+		commit.Author = s.renderLogin(c.AuthorLogin)
+	}
+	for _, parent := range c.Parents {
+		commit.Parents = append(commit.Parents, &github.Commit{
+			SHA: github.String(parent),
+			URL: github.String(s.apiURL("repos/%s/%s/commits/%s", r.Owner, r.Name, parent)),
+		})
+	}
+	return commit
+}
+
+func renderAuthor(c *Commit) *github.CommitAuthor {
+	date := c.Date
+	return &github.CommitAuthor{
+		Name:  github.String(c.AuthorName),
+		Email: github.String(c.AuthorEmail),
+		Date:  &date,
+	}
+}
+
+func (s *Server) renderPull(r *Repository, pr *PullRequest) *github.PullRequest {
+	return &github.PullRequest{
+		ID:                 github.Int64(pr.ID),
+		NodeID:             github.String(pullNodeID(pr)),
+		Number:             github.Int(pr.Number),
+		Title:              github.String(pr.Title),
+		Body:               github.String(pr.Body),
+		State:              github.String(pr.State),
+		Draft:              github.Bool(pr.Draft),
+		Merged:             github.Bool(pr.MergedAt != nil),
+		Locked:             github.Bool(pr.Locked),
+		User:               s.renderLogin(pr.User),
+		Head:               s.renderPullBranch(r, pr.Head),
+		Base:               s.renderPullBranch(r, pr.Base),
+		Labels:             renderLabels(pr.Labels),
+		Assignees:          s.renderLogins(pr.Assignees),
+		RequestedReviewers: s.renderLogins(pr.Reviewers),
+		CreatedAt:          timePtr(pr.CreatedAt),
+		UpdatedAt:          timePtr(pr.UpdatedAt),
+		ClosedAt:           pr.ClosedAt,
+		MergedAt:           pr.MergedAt,
+		URL:                github.String(s.apiURL("repos/%s/%s/pulls/%d", r.Owner, r.Name, pr.Number)),
+		HTMLURL:            github.String(htmlURL("%s/%s/pull/%d", r.Owner, r.Name, pr.Number)),
+	}
+}
+
+func (s *Server) renderPullFile(r *Repository, pr *PullRequest, f *PullFile) *github.CommitFile {
+	file := &github.CommitFile{
+		Filename:  github.String(f.Filename),
+		Status:    github.String(f.Status),
+		Additions: github.Int(f.Additions),
+		Deletions: github.Int(f.Deletions),
+		Changes:   github.Int(f.Additions + f.Deletions),
+		BlobURL:   github.String(htmlURL("%s/%s/blob/%s/%s", r.Owner, r.Name, r.Branches[pr.Head], f.Filename)),
+	}
+	if f.PreviousFilename != "" {
+		file.PreviousFilename = github.String(f.PreviousFilename)
+	}
+	if f.Patch != "" {
+		file.Patch = github.String(f.Patch)
+	}
+	return file
+}
+
+// pullNodeID - глобальный идентификатор запроса на слияние в GraphQL API
+func pullNodeID(pr *PullRequest) string {
+	return fmt.Sprintf("PR_%d", pr.ID)
+}
+
+func (s *Server) renderPullBranch(r *Repository, branch string) *github.PullRequestBranch {
+	return &github.PullRequestBranch{
+		Label: github.String(r.Owner + ":" + branch),
+		Ref:   github.String(branch),
+		SHA:   github.String(r.Branches[branch]),
+		Repo:  s.renderRepo(r),
+		User:  s.renderLogin(r.Owner),
+	}
+}
+
+func (s *Server) renderReview(r *Repository, review *Review) *github.PullRequestReview {
+	return &github.PullRequestReview{
+		ID:             github.Int64(review.ID),
+		User:           s.renderLogin(review.User),
+		Body:           github.String(review.Body),
+		State:          github.String(review.State),
+		CommitID:       github.String(review.CommitID),
+		SubmittedAt:    timePtr(review.SubmittedAt),
+		HTMLURL:        github.String(htmlURL("%s/%s/pull/%d#pullrequestreview-%d", r.Owner, r.Name, review.PullNumber, review.ID)),
+		PullRequestURL: github.String(s.apiURL("repos/%s/%s/pulls/%d", r.Owner, r.Name, review.PullNumber)),
+	}
+}
+
+func (s *Server) renderReviewComment(r *Repository, review *Review, c *ReviewComment) *github.PullRequestComment {
+	comment := &github.PullRequestComment{
+		ID:                  github.Int64(c.ID),
+		PullRequestReviewID: github.Int64(review.ID),
+		User:                s.renderLogin(c.User),
+		Path:                github.String(c.Path),
+		Side:                github.String(c.Side),
+		CommitID:            github.String(c.CommitID),
+		OriginalCommitID:    github.String(c.CommitID),
+		DiffHunk:            github.String(c.DiffHunk),
+		Body:                github.String(c.Body),
+		CreatedAt:           timePtr(c.CreatedAt),
+		UpdatedAt:           c.UpdatedAt,
+		URL:                 github.String(s.apiURL("repos/%s/%s/pulls/comments/%d", r.Owner, r.Name, c.ID)),
+		HTMLURL:             github.String(htmlURL("%s/%s/pull/%d#discussion_r%d", r.Owner, r.Name, review.PullNumber, c.ID)),
+		PullRequestURL:      github.String(s.apiURL("repos/%s/%s/pulls/%d", r.Owner, r.Name, review.PullNumber)),
+	}
+	// Как и у GitHub, у устаревшего комментария остаются только исходные номера строк и позиция,
+	// а у комментария ко всему файлу нет ни тех, ни других
+	if c.Line != 0 {
+		comment.OriginalLine = github.Int(c.Line)
+		comment.OriginalPosition = github.Int(c.Line)
+	}
+	if c.Line != 0 && !c.Outdated {
+		comment.Line = github.Int(c.Line)
+		comment.Position = github.Int(c.Line)
+	}
+	if c.StartLine != 0 {
+		comment.OriginalStartLine = github.Int(c.StartLine)
+		comment.StartSide = github.String(c.Side)
+		if !c.Outdated {
+			comment.StartLine = github.Int(c.StartLine)
+		}
+	}
+	if c.InReplyTo != 0 {
+		comment.InReplyTo = github.Int64(c.InReplyTo)
+	}
+	return comment
+}
+
+func (s *Server) renderIssueComment(r *Repository, c *IssueComment) *github.IssueComment {
+	return &github.IssueComment{
+		ID:        github.Int64(c.ID),
+		User:      s.renderLogin(c.User),
+		Body:      github.String(c.Body),
+		CreatedAt: timePtr(c.CreatedAt),
+		UpdatedAt: c.UpdatedAt,
+		URL:       github.String(s.apiURL("repos/%s/%s/issues/comments/%d", r.Owner, r.Name, c.ID)),
+		HTMLURL:   github.String(htmlURL("%s/%s/issues/%d#issuecomment-%d", r.Owner, r.Name, c.IssueNumber, c.ID)),
+		IssueURL:  github.String(s.apiURL("repos/%s/%s/issues/%d", r.Owner, r.Name, c.IssueNumber)),
+	}
+}
+
+func (s *Server) renderIssue(r *Repository, issue *Issue) *github.Issue {
+	gi := &github.Issue{
+		ID:        github.Int64(issue.ID),
+		Number:    github.Int(issue.Number),
+		Title:     github.String(issue.Title),
+		Body:      github.String(issue.Body),
+		State:     github.String(issue.State),
+		Locked:    github.Bool(issue.Locked),
+		User:      s.renderLogin(issue.User),
+		Labels:    renderLabels(issue.Labels),
+		Assignees: s.renderLogins(issue.Assignees),
+		Comments:  github.Int(r.issueCommentCount(issue.Number)),
+		CreatedAt: timePtr(issue.CreatedAt),
+		UpdatedAt: timePtr(issue.UpdatedAt),
+		ClosedAt:  issue.ClosedAt,
+		URL:       github.String(s.apiURL("repos/%s/%s/issues/%d", r.Owner, r.Name, issue.Number)),
+		HTMLURL:   github.String(htmlURL("%s/%s/issues/%d", r.Owner, r.Name, issue.Number)),
+	}
+	if issue.LockReason != "" {
+		gi.ActiveLockReason = github.String(issue.LockReason)
+	}
+	if m := r.milestone(issue.Milestone); m != nil {
+		gi.Milestone = &github.Milestone{
+			Number:  github.Int(m.Number),
+			Title:   github.String(m.Title),
+			State:   github.String(m.State),
+			HTMLURL: github.String(htmlURL("%s/%s/milestone/%d", r.Owner, r.Name, m.Number)),
+		}
+	}
+	if issue.PullRequest {
+		gi.PullRequestLinks = &github.PullRequestLinks{
+			URL:     github.String(s.apiURL("repos/%s/%s/pulls/%d", r.Owner, r.Name, issue.Number)),
+			HTMLURL: github.String(htmlURL("%s/%s/pull/%d", r.Owner, r.Name, issue.Number)),
+		}
+	}
+	return gi
+}
+
+func (s *Server) renderRelease(r *Repository, release *Release) *github.RepositoryRelease {
+	gr := &github.RepositoryRelease{
+		ID:              github.Int64(release.ID),
+		TagName:         github.String(release.TagName),
+		TargetCommitish: github.String(release.Target),
+		Name:            github.String(release.Name),
+		Body:            github.String(release.Body),
+		Draft:           github.Bool(release.Draft),
+		Prerelease:      github.Bool(release.Prerelease),
+		Author:          s.renderLogin(release.Author),
+		URL:             github.String(s.apiURL("repos/%s/%s/releases/%d", r.Owner, r.Name, release.ID)),
+		HTMLURL:         github.String(htmlURL("%s/%s/releases/tag/%s", r.Owner, r.Name, release.TagName)),
+		CreatedAt:       &github.Timestamp{Time: release.CreatedAt},
+	}
+	if release.PublishedAt != nil {
+		gr.PublishedAt = &github.Timestamp{Time: *release.PublishedAt}
+	}
+	for i := range release.Assets {
+		gr.Assets = append(gr.Assets, s.renderAsset(r, release, &release.Assets[i]))
+	}
+	return gr
+}
+
+func (s *Server) renderAsset(r *Repository, release *Release, asset *Asset) *github.ReleaseAsset {
+	return &github.ReleaseAsset{
+		ID:                 github.Int64(asset.ID),
+		Name:               github.String(asset.Name),
+		ContentType:        github.String(asset.ContentType),
+		Size:               github.Int(len(asset.Content)),
+		DownloadCount:      github.Int(asset.DownloadCount),
+		State:              github.String("uploaded"),
+		URL:                github.String(s.apiURL("repos/%s/%s/releases/assets/%d", r.Owner, r.Name, asset.ID)),
+		BrowserDownloadURL: github.String(s.downloadURL(r, release, asset)),
+		CreatedAt:          &github.Timestamp{Time: asset.CreatedAt},
+		Uploader:           s.renderLogin(release.Author),
+	}
+}
+
+// downloadURL строит ссылку browser_download_url. У GitHub она ведет на github.com,
+// у симулятора - на сам симулятор, чтобы скачивание работало без сети
+func (s *Server) downloadURL(r *Repository, release *Release, asset *Asset) string {
+	return s.URL + "/" + fmt.Sprintf("%s/%s/releases/download/%s/%s",
+		r.Owner, r.Name, url.PathEscape(release.TagName), url.PathEscape(asset.Name))
+}
+
+func (s *Server) renderRef(r *Repository, name, objectType, sha string) *github.Reference {
+	return &github.Reference{
+		Ref: github.String("refs/" + name),
+		URL: github.String(s.apiURL("repos/%s/%s/git/refs/%s", r.Owner, r.Name, name)),
+		Object: &github.GitObject{
+			Type: github.String(objectType),
+			SHA:  github.String(sha),
+		},
+	}
+}
+
+func (s *Server) renderGitTag(r *Repository, tag Tag) *github.Tag {
+	return &github.Tag{
+		Tag:     github.String(tag.Name),
+		SHA:     github.String(tag.Annotation.SHA),
+		URL:     github.String(s.apiURL("repos/%s/%s/git/tags/%s", r.Owner, r.Name, tag.Annotation.SHA)),
+		Message: github.String(tag.Annotation.Message),
+		Tagger: &github.CommitAuthor{
+			Name:  github.String(tag.Annotation.TaggerName),
+			Email: github.String(tag.Annotation.TaggerEmail),
+			Date:  timePtr(tag.Annotation.Date),
+		},
+		Object: &github.GitObject{
+			Type: github.String("commit"),
+			SHA:  github.String(tag.SHA),
+		},
+	}
+}
+
+func (s *Server) renderInvitation(r *Repository, inv *Invitation) *github.RepositoryInvitation {
+	return &github.RepositoryInvitation{
+		ID:          github.Int64(inv.ID),
+		Repo:        s.renderRepo(r),
+		Invitee:     s.renderLogin(inv.Invitee),
+		Inviter:     s.renderLogin(r.Owner),
+		Permissions: github.String("read"),
+	}
+}
+
+// htmlURL строит адрес страницы на github.com
+func htmlURL(format string, args ...interface{}) string {
+	return "https://github.com/" + fmt.Sprintf(format, args...)
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
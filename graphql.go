@@ -0,0 +1,66 @@
+package notgogithub
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// graphQLResponse - ответ GraphQL API с данными типа T
+type graphQLResponse[T any] struct {
+	Data   T              `json:"data"`
+	Errors []graphQLError `json:"errors"`
+}
+
+// graphQLError - ошибка из поля errors ответа GraphQL API. GraphQL API отвечает на нее
+// статусом 200, поэтому вид ошибки определяется по полю type
+type graphQLError struct {
+	Type    string `json:"type"` // NOT_FOUND, FORBIDDEN, UNPROCESSABLE и т.д. ("" у ошибок разбора запроса)
+	Message string `json:"message"`
+}
+
+func (e *graphQLError) Error() string {
+	return e.Message
+}
+
+// kind определяет вид ошибки по ее типу
+func (e *graphQLError) kind() error {
+	switch e.Type {
+	case "NOT_FOUND":
+		return ErrNotFound
+	case "FORBIDDEN":
+		return ErrForbidden
+	case "UNPROCESSABLE":
+		return ErrValidation
+	}
+	return nil
+}
+
+// graphQL выполняет запрос op к GraphQL API: то, чего нет в REST API (решенность обсуждений,
+// перевод запроса на слияние в черновик). Ошибки из поля errors ответа возвращаются как *Error
+func graphQL[T any](ctx context.Context, ghs *gitHubService, op, query string, variables map[string]interface{}) (*T, error) {
+	body := map[string]interface{}{"query": query, "variables": variables}
+	page, _, err := callAPI(ctx, ghs, op, func() (*graphQLResponse[T], *github.Response, error) {
+		// GraphQL API находится рядом с REST API: /graphql у github.com и /api/graphql у GitHub Enterprise Server
+		req, err := ghs.client.NewRequest(http.MethodPost, "../graphql", body)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		page := new(graphQLResponse[T])
+		resp, err := ghs.client.Do(ctx, req, page)
+		if resp != nil {
+			// У GraphQL API свой лимит запросов, он не должен подменять лимит REST API
+			resp.Rate = github.Rate{}
+		}
+		if err == nil && len(page.Errors) > 0 {
+			err = &page.Errors[0]
+		}
+		return page, resp, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &page.Data, nil
+}
@@ -0,0 +1,69 @@
+package notgogithub
+
+import "container/heap"
+
+// linearizeHistory оставляет каждый коммит один раз и упорядочивает коммиты топологически:
+// потомок всегда идет раньше своих родителей. Среди коммитов, готовых к выдаче, сохраняется
+// исходный порядок (GitHub отдает историю от новых коммитов к старым), поэтому расхождение
+// часов у авторов не нарушает порядок. Родители, не попавшие в commits (например, из-за
+// фильтра по пути или ограничения глубины), не учитываются. Циклов в истории git не бывает
+func linearizeHistory(commits []*Commit) []*Commit {
+	// Убираем повторы, которые возникают, если история изменилась между загрузкой страниц
+	index := make(map[string]int, len(commits))
+	var unique []*Commit
+	for _, c := range commits {
+		if _, seen := index[c.Hash]; seen {
+			continue
+		}
+		index[c.Hash] = len(unique)
+		unique = append(unique, c)
+	}
+
+	// Количество еще не выданных потомков каждого коммита
+	children := make([]int, len(unique))
+	for _, c := range unique {
+		for _, parent := range c.Parents {
+			if i, ok := index[parent]; ok {
+				children[i]++
+			}
+		}
+	}
+
+	// Алгоритм Кана: на каждом шаге выдаем самый ранний по исходному порядку коммит без невыданных потомков
+	ready := &indexHeap{}
+	for i := range unique {
+		if children[i] == 0 {
+			heap.Push(ready, i)
+		}
+	}
+
+	sorted := make([]*Commit, 0, len(unique))
+	for ready.Len() > 0 {
+		c := unique[heap.Pop(ready).(int)]
+		sorted = append(sorted, c)
+		for _, parent := range c.Parents {
+			if i, ok := index[parent]; ok {
+				children[i]--
+				if children[i] == 0 {
+					heap.Push(ready, i)
+				}
+			}
+		}
+	}
+
+	return sorted
+}
+
+// indexHeap - очередь индексов, из которой первым извлекается наименьший
+type indexHeap []int
+
+func (h indexHeap) Len() int            { return len(h) }
+func (h indexHeap) Less(i, j int) bool  { return h[i] < h[j] }
+func (h indexHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *indexHeap) Push(x interface{}) { *h = append(*h, x.(int)) }
+func (h *indexHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,58 @@
+package notgogithub
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLinearizeHistory(t *testing.T) {
+	// Arrange: c1 <- c2, c1 <- c3, c4 = merge(c2, c3)
+	commit := func(hash string, parents ...string) *Commit {
+		return &Commit{Hash: hash, Parents: parents}
+	}
+	testTable := []struct {
+		name     string
+		commits  []*Commit
+		expected string
+	}{
+		{
+			name:     "already ordered",
+			commits:  []*Commit{commit("c4", "c2", "c3"), commit("c3", "c1"), commit("c2", "c1"), commit("c1")},
+			expected: "[c4 c3 c2 c1]",
+		},
+		{
+			// У автора c1 спешили часы, поэтому по дате он оказался раньше потомков
+			name:     "clock skew",
+			commits:  []*Commit{commit("c1"), commit("c4", "c2", "c3"), commit("c2", "c1"), commit("c3", "c1")},
+			expected: "[c4 c2 c3 c1]",
+		},
+		{
+			name:     "duplicates",
+			commits:  []*Commit{commit("c4", "c2", "c3"), commit("c2", "c1"), commit("c3", "c1"), commit("c2", "c1"), commit("c1")},
+			expected: "[c4 c2 c3 c1]",
+		},
+		{
+			name:     "missing parents",
+			commits:  []*Commit{commit("c3", "c1"), commit("c2", "c1")},
+			expected: "[c3 c2]",
+		},
+		{
+			name:     "empty",
+			commits:  nil,
+			expected: "[]",
+		},
+	}
+
+	for _, testCase := range testTable {
+		// Act
+		var hashes []string
+		for _, c := range linearizeHistory(testCase.commits) {
+			hashes = append(hashes, c.Hash)
+		}
+
+		// Assert
+		if fmt.Sprint(hashes) != testCase.expected {
+			t.Errorf("Incorrect order for %s: expected %v, got %v", testCase.name, testCase.expected, hashes)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Package gitmodel - общая для FakeGitService и симулятора ghsim модель истории git и diff:
+// обход истории как у git log, коммиты запроса на слияние, поиск конфликтов и сборка diff и patch.
+// Хранилища коммитов у FakeGitService и ghsim свои, поэтому история читается через Graph
+package gitmodel
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Node - коммит в графе истории
+type Node struct {
+	Parents []string  // SHA родителей
+	Date    time.Time // Дата коммита: из готовых к выдаче коммитов первым идет более новый
+	Files   []string  // Файлы, измененные коммитом
+}
+
+// Graph находит коммит по SHA. false - коммита в репозитории нет
+type Graph func(sha string) (Node, bool)
+
+// History возвращает SHA коммитов, достижимых из head, как git log: каждый коммит один раз,
+// потомки раньше родителей, из готовых к выдаче первым идет более новый.
+// Родители, которых нет в графе, пропускаются
+func History(g Graph, head string) []string {
+	if _, ok := g(head); !ok {
+		return nil
+	}
+
+	// Считаем, сколько потомков в достижимой части истории у каждого коммита
+	children := map[string]int{head: 0}
+	reachable := []string{head}
+	for i := 0; i < len(reachable); i++ {
+		c, _ := g(reachable[i])
+		for _, parent := range c.Parents {
+			if _, seen := children[parent]; !seen {
+				reachable = append(reachable, parent)
+			}
+			children[parent]++
+		}
+	}
+
+	var history []string
+	ready := []string{head}
+	for len(ready) > 0 {
+		sort.SliceStable(ready, func(i, j int) bool {
+			a, _ := g(ready[i])
+			b, _ := g(ready[j])
+			return a.Date.After(b.Date)
+		})
+		sha := ready[0]
+		ready = ready[1:]
+		history = append(history, sha)
+
+		c, _ := g(sha)
+		for _, parent := range c.Parents {
+			children[parent]--
+			if _, ok := g(parent); ok && children[parent] == 0 {
+				ready = append(ready, parent)
+			}
+		}
+	}
+	return history
+}
+
+// Reachable возвращает множество коммитов, достижимых из sha
+func Reachable(g Graph, sha string) map[string]bool {
+	seen := make(map[string]bool)
+	stack := []string{sha}
+	for len(stack) > 0 {
+		sha := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		c, ok := g(sha)
+		if !ok || seen[sha] {
+			continue
+		}
+		seen[sha] = true
+		stack = append(stack, c.Parents...)
+	}
+	return seen
+}
+
+// Ahead возвращает SHA коммитов, достижимых из head, но не из base, от старых к новым:
+// так GitHub перечисляет коммиты запроса на слияние
+func Ahead(g Graph, head, base string) []string {
+	inBase := Reachable(g, base)
+	var ahead []string
+	for _, sha := range History(g, head) {
+		if !inBase[sha] {
+			ahead = append([]string{sha}, ahead...)
+		}
+	}
+	return ahead
+}
+
+// Conflicts сообщает, меняли ли head и base одни и те же файлы после их общего предка
+func Conflicts(g Graph, head, base string) bool {
+	inHead, inBase := Reachable(g, head), Reachable(g, base)
+	changedInBase := make(map[string]bool)
+	for sha := range inBase {
+		if c, _ := g(sha); !inHead[sha] {
+			for _, f := range c.Files {
+				changedInBase[f] = true
+			}
+		}
+	}
+
+	for sha := range inHead {
+		if c, _ := g(sha); !inBase[sha] {
+			for _, f := range c.Files {
+				if changedInBase[f] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// File - изменение файла в diff
+type File struct {
+	Filename         string
+	PreviousFilename string // Прежнее имя переименованного файла
+	Status           string // added, removed, renamed или modified
+	Patch            string // Фрагменты diff без заголовков ("" у двоичного файла)
+}
+
+// UnifiedDiff собирает изменения файлов в один diff в формате git
+func UnifiedDiff(files []File) string {
+	var b strings.Builder
+	for _, f := range files {
+		from, to := "a/"+f.Filename, "b/"+f.Filename
+		if f.PreviousFilename != "" {
+			from = "a/" + f.PreviousFilename
+		}
+		fmt.Fprintf(&b, "diff --git %s %s\n", from, to)
+		switch f.Status {
+		case "added":
+			b.WriteString("new file mode 100644\n")
+			from = "/dev/null"
+		case "removed":
+			b.WriteString("deleted file mode 100644\n")
+			to = "/dev/null"
+		case "renamed":
+			fmt.Fprintf(&b, "rename from %s\nrename to %s\n", f.PreviousFilename, f.Filename)
+		}
+		if f.Patch != "" {
+			fmt.Fprintf(&b, "--- %s\n+++ %s\n%s\n", from, to, strings.TrimSuffix(f.Patch, "\n"))
+		}
+	}
+	return b.String()
+}
+
+// PatchCommit - коммит в выводе FormatPatch
+type PatchCommit struct {
+	SHA     string
+	Author  string // Автор в строке From:, например "Имя <почта>"
+	Date    time.Time
+	Subject string
+}
+
+// FormatPatch собирает изменения коммитов в формате git format-patch. Изменения отдельных
+// коммитов не хранятся, поэтому весь diff приложен к последнему коммиту
+func FormatPatch(commits []PatchCommit, diff string) string {
+	var b strings.Builder
+	for i, c := range commits {
+		fmt.Fprintf(&b, "From %s Mon Sep 17 00:00:00 2001\nFrom: %s\nDate: %s\nSubject: [PATCH %d/%d] %s\n\n---\n",
+			c.SHA, c.Author, c.Date.Format(time.RFC1123Z), i+1, len(commits), c.Subject)
+		if i == len(commits)-1 {
+			b.WriteString(diff)
+		}
+		b.WriteString("--\n\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,87 @@
+package gitmodel
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testGraph - история c1 <- c2 <- c4 (main) и c1 <- c3 (feature), c2 и c3 сделаны в одну секунду
+func testGraph() Graph {
+	day := time.Date(2022, 3, 5, 0, 0, 0, 0, time.UTC)
+	nodes := map[string]Node{
+		"c1": {Date: day, Files: []string{"README.md"}},
+		"c2": {Parents: []string{"c1"}, Date: day.Add(time.Hour), Files: []string{"main.cpp"}},
+		"c3": {Parents: []string{"c1"}, Date: day.Add(time.Hour), Files: []string{"README.md"}},
+		"c4": {Parents: []string{"c2", "c3"}, Date: day.Add(2 * time.Hour), Files: []string{"main.cpp"}},
+		"c5": {Parents: []string{"c1", "missing"}, Date: day.Add(3 * time.Hour), Files: []string{"README.md"}},
+	}
+	return func(sha string) (Node, bool) {
+		n, ok := nodes[sha]
+		return n, ok
+	}
+}
+
+func TestHistory(t *testing.T) {
+	g := testGraph()
+
+	// Act
+	merged := History(g, "c4")
+	withMissingParent := History(g, "c5")
+	missing := History(g, "missing")
+
+	// Assert
+	// При равных датах сохраняется порядок родителей
+	if fmt.Sprint(merged) != "[c4 c2 c3 c1]" {
+		t.Errorf("Incorrect history: %v", merged)
+	}
+	if fmt.Sprint(withMissingParent) != "[c5 c1]" {
+		t.Errorf("Incorrect history with missing parent: %v", withMissingParent)
+	}
+	if missing != nil {
+		t.Errorf("Incorrect history of missing commit: %v", missing)
+	}
+}
+
+func TestAheadAndConflicts(t *testing.T) {
+	g := testGraph()
+
+	// Act
+	ahead := Ahead(g, "c4", "c3")
+	conflicts := Conflicts(g, "c5", "c3")
+	clean := Conflicts(g, "c2", "c3")
+
+	// Assert
+	if fmt.Sprint(ahead) != "[c2 c4]" {
+		t.Errorf("Incorrect commits ahead: %v", ahead)
+	}
+	if !conflicts || clean {
+		t.Errorf("Incorrect conflicts: %v, %v", conflicts, clean)
+	}
+}
+
+func TestUnifiedDiffAndPatch(t *testing.T) {
+	// Arrange
+	files := []File{
+		{Filename: "docs/README.md", PreviousFilename: "README.md", Status: "renamed"},
+		{Filename: "main.cpp", Status: "added", Patch: "@@ -0,0 +1 @@\n+int main() {}\n"},
+	}
+
+	// Act
+	diff := UnifiedDiff(files)
+	patch := FormatPatch([]PatchCommit{
+		{SHA: "c1", Author: "PeakIntegral", Date: time.Date(2022, 3, 5, 0, 0, 0, 0, time.UTC), Subject: "Init"},
+		{SHA: "c2", Author: "PeakIntegral", Date: time.Date(2022, 3, 6, 0, 0, 0, 0, time.UTC), Subject: "Add main"},
+	}, diff)
+
+	// Assert
+	expected := "diff --git a/README.md b/docs/README.md\nrename from README.md\nrename to docs/README.md\n" +
+		"diff --git a/main.cpp b/main.cpp\nnew file mode 100644\n--- /dev/null\n+++ b/main.cpp\n@@ -0,0 +1 @@\n+int main() {}\n"
+	if diff != expected {
+		t.Errorf("Incorrect diff:\n%v", diff)
+	}
+	if strings.Count(patch, diff) != 1 || !strings.Contains(patch, "Subject: [PATCH 2/2] Add main\n\n---\n"+diff) {
+		t.Errorf("Incorrect patch:\n%v", patch)
+	}
+}
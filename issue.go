@@ -0,0 +1,229 @@
+package notgogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// IssueStateReason - причина закрытия проблемы
+type IssueStateReason string
+
+const (
+	IssueCompleted  IssueStateReason = "completed"   // Проблема решена
+	IssueNotPlanned IssueStateReason = "not_planned" // Проблема не будет решаться: дубликат, устарела и т.д.
+	issueReopened   IssueStateReason = "reopened"
+)
+
+// LockReason - причина блокировки обсуждения проблемы
+type LockReason string
+
+const (
+	LockOffTopic  LockReason = "off-topic"
+	LockTooHeated LockReason = "too heated"
+	LockResolved  LockReason = "resolved"
+	LockSpam      LockReason = "spam"
+)
+
+// IssueOption задает поле проблемы в CreateIssue и EditIssue.
+// EditIssue меняет только поля, для которых передана опция
+type IssueOption func(*issueRequest)
+
+// WithIssueTitle задает тему проблемы
+func WithIssueTitle(title string) IssueOption {
+	return func(r *issueRequest) {
+		r.Title = &title
+	}
+}
+
+// WithIssueBody задает описание проблемы
+func WithIssueBody(body string) IssueOption {
+	return func(r *issueRequest) {
+		r.Body = &body
+	}
+}
+
+// WithIssueLabels заменяет метки проблемы на labels. Отсутствующие в репозитории метки GitHub создаст
+func WithIssueLabels(labels ...string) IssueOption {
+	return func(r *issueRequest) {
+		labels := append([]string{}, labels...)
+		r.Labels = &labels
+	}
+}
+
+// WithIssueAssignees заменяет исполнителей проблемы на пользователей с логинами logins
+func WithIssueAssignees(logins ...string) IssueOption {
+	return func(r *issueRequest) {
+		logins := append([]string{}, logins...)
+		r.Assignees = &logins
+	}
+}
+
+// WithIssueMilestone привязывает проблему к вехе с номером number
+func WithIssueMilestone(number int) IssueOption {
+	return func(r *issueRequest) {
+		r.Milestone = &number
+	}
+}
+
+// issueRequest - тело запроса создания или изменения проблемы. В github.IssueRequest
+// из go-github v45 нет поля state_reason, поэтому запрос отправляется с собственным телом
+type issueRequest struct {
+	Title       *string   `json:"title,omitempty"`
+	Body        *string   `json:"body,omitempty"`
+	Labels      *[]string `json:"labels,omitempty"`
+	Assignees   *[]string `json:"assignees,omitempty"`
+	Milestone   *int      `json:"milestone,omitempty"`
+	State       *string   `json:"state,omitempty"`
+	StateReason *string   `json:"state_reason,omitempty"`
+}
+
+func newIssueRequest(opts []IssueOption) *issueRequest {
+	var r issueRequest
+	for _, opt := range opts {
+		opt(&r)
+	}
+	return &r
+}
+
+func (ghs *gitHubService) CreateIssue(ctx context.Context, owner, repositoryName, title string, opts ...IssueOption) (*Issue, error) {
+	body := newIssueRequest(opts)
+	body.Title = &title
+	u := fmt.Sprintf("repos/%s/%s/issues", owner, repositoryName)
+	return ghs.sendIssue(ctx, "create issue", http.MethodPost, u, body)
+}
+
+func (ghs *gitHubService) GetIssue(ctx context.Context, owner, repositoryName string, issueNumber int) (*Issue, error) {
+	issue, _, err := callAPI(ctx, ghs, "get issue", func() (*github.Issue, *github.Response, error) {
+		return ghs.client.Issues.Get(ctx, owner, repositoryName, issueNumber)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newIssue(issue), nil
+}
+
+func (ghs *gitHubService) EditIssue(ctx context.Context, owner, repositoryName string, issueNumber int, opts ...IssueOption) (*Issue, error) {
+	u := fmt.Sprintf("repos/%s/%s/issues/%d", owner, repositoryName, issueNumber)
+	return ghs.sendIssue(ctx, "edit issue", http.MethodPatch, u, newIssueRequest(opts))
+}
+
+func (ghs *gitHubService) CloseIssue(ctx context.Context, owner, repositoryName string, issueNumber int, reason IssueStateReason) (*Issue, error) {
+	if reason == "" {
+		reason = IssueCompleted
+	}
+	return ghs.setIssueState(ctx, owner, repositoryName, issueNumber, "close issue", StateClosed, reason)
+}
+
+func (ghs *gitHubService) ReopenIssue(ctx context.Context, owner, repositoryName string, issueNumber int) (*Issue, error) {
+	return ghs.setIssueState(ctx, owner, repositoryName, issueNumber, "reopen issue", StateOpen, issueReopened)
+}
+
+// setIssueState меняет состояние проблемы, указывая причину reason
+func (ghs *gitHubService) setIssueState(ctx context.Context, owner, repositoryName string, issueNumber int, op string, state State, reason IssueStateReason) (*Issue, error) {
+	s, r := string(state), string(reason)
+	u := fmt.Sprintf("repos/%s/%s/issues/%d", owner, repositoryName, issueNumber)
+	return ghs.sendIssue(ctx, op, http.MethodPatch, u, &issueRequest{State: &s, StateReason: &r})
+}
+
+// sendIssue отправляет запрос создания или изменения проблемы
+func (ghs *gitHubService) sendIssue(ctx context.Context, op, method, u string, body *issueRequest) (*Issue, error) {
+	issue, _, err := callAPI(ctx, ghs, op, func() (*github.Issue, *github.Response, error) {
+		req, err := ghs.client.NewRequest(method, u, body)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		issue := new(github.Issue)
+		resp, err := ghs.client.Do(ctx, req, issue)
+		return issue, resp, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newIssue(issue), nil
+}
+
+func (ghs *gitHubService) LockIssue(ctx context.Context, owner, repositoryName string, issueNumber int, reason LockReason) error {
+	return ghs.do(ctx, "lock issue", func() (*github.Response, error) {
+		return ghs.client.Issues.Lock(ctx, owner, repositoryName, issueNumber, &github.LockIssueOptions{LockReason: string(reason)})
+	})
+}
+
+func (ghs *gitHubService) UnlockIssue(ctx context.Context, owner, repositoryName string, issueNumber int) error {
+	return ghs.do(ctx, "unlock issue", func() (*github.Response, error) {
+		return ghs.client.Issues.Unlock(ctx, owner, repositoryName, issueNumber)
+	})
+}
+
+func (ghs *gitHubService) GetIssueComments(ctx context.Context, owner, repositoryName string, issueNumber int) ([]*Comment, error) {
+	comments, err := collectPages(ctx, 0, func(lo github.ListOptions) ([]*github.IssueComment, *github.Response, error) {
+		opts := github.IssueListCommentsOptions{ListOptions: lo}
+		return callAPI(ctx, ghs, "list issue comments", func() ([]*github.IssueComment, *github.Response, error) {
+			return ghs.client.Issues.ListComments(ctx, owner, repositoryName, issueNumber, &opts)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var Comments []*Comment
+	for _, c := range nonNil(comments) {
+		comment := newIssueComment(c)
+		Comments = append(Comments, &comment)
+	}
+	return Comments, nil
+}
+
+func (ghs *gitHubService) CreateIssueComment(ctx context.Context, owner, repositoryName string, issueNumber int, body string) (*Comment, error) {
+	c, _, err := callAPI(ctx, ghs, "create issue comment", func() (*github.IssueComment, *github.Response, error) {
+		return ghs.client.Issues.CreateComment(ctx, owner, repositoryName, issueNumber, &github.IssueComment{Body: &body})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := newIssueComment(c)
+	return &result, nil
+}
+
+func (ghs *gitHubService) EditIssueComment(ctx context.Context, owner, repositoryName string, issueNumber int, commentID int64, body string) (*Comment, error) {
+	if err := ghs.findIssueComment(ctx, owner, repositoryName, issueNumber, commentID); err != nil {
+		return nil, err
+	}
+
+	c, _, err := callAPI(ctx, ghs, "edit issue comment", func() (*github.IssueComment, *github.Response, error) {
+		return ghs.client.Issues.EditComment(ctx, owner, repositoryName, commentID, &github.IssueComment{Body: &body})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := newIssueComment(c)
+	return &result, nil
+}
+
+func (ghs *gitHubService) DeleteIssueComment(ctx context.Context, owner, repositoryName string, issueNumber int, commentID int64) error {
+	if err := ghs.findIssueComment(ctx, owner, repositoryName, issueNumber, commentID); err != nil {
+		return err
+	}
+
+	return ghs.do(ctx, "delete issue comment", func() (*github.Response, error) {
+		return ghs.client.Issues.DeleteComment(ctx, owner, repositoryName, commentID)
+	})
+}
+
+// findIssueComment проверяет, что комментарий commentID оставлен в обсуждении проблемы issueNumber
+func (ghs *gitHubService) findIssueComment(ctx context.Context, owner, repositoryName string, issueNumber int, commentID int64) error {
+	c, _, err := callAPI(ctx, ghs, "get issue comment", func() (*github.IssueComment, *github.Response, error) {
+		return ghs.client.Issues.GetComment(ctx, owner, repositoryName, commentID)
+	})
+	if err != nil {
+		return err
+	}
+	return commentOf(c.GetIssueURL(), fmt.Sprintf("/issues/%d", issueNumber), commentID, fmt.Sprintf("issue %d", issueNumber))
+}
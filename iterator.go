@@ -0,0 +1,204 @@
+package notgogithub
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// Cursor указывает позицию в постраничном списке, с которой итератор может продолжить обход
+type Cursor struct {
+	Page   int // Номер страницы (начиная с 1)
+	Offset int // Количество элементов этой страницы, которые уже были обработаны
+}
+
+// PageError - ошибка загрузки одной из страниц списка
+type PageError struct {
+	Page int   // Номер страницы, которую не удалось загрузить
+	Err  error // Исходная ошибка
+}
+
+func (e *PageError) Error() string {
+	return fmt.Sprintf("page %d: %v", e.Page, e.Err)
+}
+
+func (e *PageError) Unwrap() error {
+	return e.Err
+}
+
+// Iterator лениво обходит постраничный список: очередная страница загружается
+// только после того, как закончились элементы предыдущей.
+//
+// Типичное использование:
+//
+//	it := ghs.IterateIssues(ctx, "google", "go-github")
+//	for it.Next() {
+//		issue := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		// продолжить можно позже с it.Cursor()
+//	}
+type Iterator[T any] struct {
+	ctx   context.Context
+	fetch pageFetcher[T]
+
+	page    int // Номер страницы, элементы которой лежат в buf
+	next    int // Номер следующей страницы (0 - страниц больше нет)
+	skip    int // Сколько элементов пропустить на следующей странице при возобновлении обхода
+	buf     []T
+	offset  int // Количество уже выданных элементов из buf
+	cur     T
+	err     error
+	stopped bool
+}
+
+// Итераторы по спискам, которые возвращает GitServiceIFace
+type (
+	RepositoryIterator  = Iterator[*Repository]
+	BranchIterator      = Iterator[*Branch]
+	CommitIterator      = Iterator[*Commit]
+	PullRequestIterator = Iterator[*PullRequest]
+	IssueIterator       = Iterator[*Issue]
+	UserIterator        = Iterator[*User]
+	TagIterator         = Iterator[*Tag]
+	ReleaseIterator     = Iterator[*Release]
+)
+
+func newIterator[T any](ctx context.Context, fetch pageFetcher[T]) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, fetch: fetch, next: 1}
+}
+
+// Next переходит к следующему элементу. Возвращает false, когда элементы закончились,
+// обход остановлен через Stop или при загрузке страницы произошла ошибка (см. Err)
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil || it.stopped {
+		return false
+	}
+
+	for it.offset >= len(it.buf) {
+		if it.next == 0 {
+			return false
+		}
+		if err := it.load(); err != nil {
+			it.err = &PageError{Page: it.next, Err: err}
+			return false
+		}
+	}
+
+	it.cur = it.buf[it.offset]
+	it.offset++
+	return true
+}
+
+// load загружает страницу it.next
+func (it *Iterator[T]) load() error {
+	if err := it.ctx.Err(); err != nil {
+		return err
+	}
+
+	items, resp, err := it.fetch(github.ListOptions{Page: it.next, PerPage: perPage})
+	if err != nil {
+		return err
+	}
+
+	it.page, it.buf, it.offset = it.next, items, it.skip
+	it.skip = 0
+	it.next = 0
+	if resp != nil {
+		it.next = resp.NextPage
+	}
+	return nil
+}
+
+// Value возвращает текущий элемент
+func (it *Iterator[T]) Value() T {
+	return it.cur
+}
+
+// Err возвращает ошибку загрузки страницы (*PageError), из-за которой обход прервался
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Stop досрочно завершает обход: последующие вызовы Next возвращают false,
+// а уже загруженная страница освобождается
+func (it *Iterator[T]) Stop() {
+	c := it.Cursor()
+	it.stopped = true
+	it.page, it.next, it.skip = 0, c.Page, c.Offset
+	it.buf, it.offset = nil, 0
+}
+
+// Cursor возвращает позицию, начиная с которой Seek продолжит обход после текущего элемента.
+// Если обход прервался ошибкой, курсор указывает на страницу, которую не удалось загрузить
+func (it *Iterator[T]) Cursor() Cursor {
+	if it.offset < len(it.buf) || it.next == 0 {
+		return Cursor{Page: it.page, Offset: it.offset}
+	}
+	return Cursor{Page: it.next, Offset: it.skip}
+}
+
+// Seek сбрасывает ошибку и остановку и продолжает обход с позиции c.
+// Страница 0 означает начало списка. Повторный Seek(it.Cursor()) после ошибки
+// повторяет загрузку неудавшейся страницы
+func (it *Iterator[T]) Seek(c Cursor) {
+	if c.Page <= 0 {
+		c = Cursor{Page: 1}
+	}
+
+	it.page, it.next, it.skip = 0, c.Page, c.Offset
+	it.buf, it.offset = nil, 0
+	it.err, it.stopped = nil, false
+}
+
+func (ghs *gitHubService) IterateUserRepositories(ctx context.Context, userName string, opts ...ListOption) *RepositoryIterator {
+	return newIterator(ctx, convertPages(ghs.repositoryPages(ctx, userName), func(repos []*github.Repository) ([]*Repository, error) {
+		return ghs.convertRepositories(ctx, repos, enrichment(opts))
+	}))
+}
+
+func (ghs *gitHubService) IterateRepositoryBranches(ctx context.Context, owner, repositoryName string, opts ...ListOption) *BranchIterator {
+	return newIterator(ctx, convertPages(ghs.branchPages(ctx, owner, repositoryName), func(branches []*github.Branch) ([]*Branch, error) {
+		return ghs.convertBranches(ctx, owner, repositoryName, branches, enrichment(opts))
+	}))
+}
+
+func (ghs *gitHubService) IterateBranchCommits(ctx context.Context, userName, repositoryName, branchName string, opts ...ListOption) *CommitIterator {
+	return newIterator(ctx, convertPages(ghs.commitPages(ctx, userName, repositoryName, branchName, newListOptions(opts)), func(commits []*github.RepositoryCommit) ([]*Commit, error) {
+		return convertRepositoryCommits(commits), nil
+	}))
+}
+
+func (ghs *gitHubService) IterateRepositoryPullRequests(ctx context.Context, userName, repositoryName string, opts ...ListOption) *PullRequestIterator {
+	return newIterator(ctx, convertPages(ghs.pullRequestPages(ctx, userName, repositoryName, newListOptions(opts)), func(pullRequests []*github.PullRequest) ([]*PullRequest, error) {
+		return convertPullRequests(pullRequests), nil
+	}))
+}
+
+func (ghs *gitHubService) IterateIssues(ctx context.Context, userName, repositoryName string, opts ...ListOption) *IssueIterator {
+	return newIterator(ctx, convertPages(ghs.issuePages(ctx, userName, repositoryName, newListOptions(opts)), func(issues []*github.Issue) ([]*Issue, error) {
+		return convertIssues(issues), nil
+	}))
+}
+
+func (ghs *gitHubService) IterateRepositoryContributors(ctx context.Context, userName, repositoryName string, opts ...ListOption) *UserIterator {
+	return newIterator(ctx, convertPages(ghs.contributorPages(ctx, userName, repositoryName), func(contributors []*github.Contributor) ([]*User, error) {
+		return ghs.convertContributors(ctx, contributors, enrichment(opts))
+	}))
+}
+
+func (ghs *gitHubService) IterateRepositoryTags(ctx context.Context, userName, repositoryName string, opts ...ListOption) *TagIterator {
+	// Аннотированные теги загружаются один раз на итератор, при первой странице
+	annotatedTags := ghs.annotatedTagsOnce(ctx, userName, repositoryName)
+	return newIterator(ctx, convertPages(ghs.tagPages(ctx, userName, repositoryName), func(tags []*github.RepositoryTag) ([]*Tag, error) {
+		return ghs.convertTags(ctx, userName, repositoryName, tags, enrichment(opts), annotatedTags)
+	}))
+}
+
+func (ghs *gitHubService) IterateRepositoryReleases(ctx context.Context, owner, repositoryName string) *ReleaseIterator {
+	return newIterator(ctx, convertPages(ghs.releasePages(ctx, owner, repositoryName), func(releases []*github.RepositoryRelease) ([]*Release, error) {
+		return convertReleases(releases), nil
+	}))
+}
@@ -0,0 +1,114 @@
+package notgogithub
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-github/v45/github"
+)
+
+func TestIteratorLazyPages(t *testing.T) {
+	items := make([]int, 250)
+	for i := range items {
+		items[i] = i
+	}
+
+	var requested []github.ListOptions
+	it := newIterator(context.Background(), pagesOf(items, &requested))
+
+	if len(requested) != 0 {
+		t.Fatalf("expected no requests before Next, got %v", len(requested))
+	}
+
+	count := 0
+	for it.Next() {
+		if it.Value() != count {
+			t.Fatalf("expected item %v, got %v", count, it.Value())
+		}
+		count++
+
+		// Страница загружается только когда закончилась предыдущая
+		expectedRequests := (count-1)/perPage + 1
+		if len(requested) != expectedRequests {
+			t.Fatalf("after %v items expected %v requests, got %v", count, expectedRequests, len(requested))
+		}
+	}
+
+	if it.Err() != nil {
+		t.Errorf("unexpected error: %v", it.Err())
+	}
+	if count != len(items) {
+		t.Errorf("expected %v items, got %v", len(items), count)
+	}
+}
+
+func TestIteratorStopAndResume(t *testing.T) {
+	items := make([]int, 250)
+	for i := range items {
+		items[i] = i
+	}
+
+	var requested []github.ListOptions
+	it := newIterator(context.Background(), pagesOf(items, &requested))
+	for i := 0; i < 130; i++ {
+		it.Next()
+	}
+	it.Stop()
+
+	if it.Next() {
+		t.Fatalf("Next after Stop must return false")
+	}
+
+	cursor := it.Cursor()
+	if cursor != (Cursor{Page: 2, Offset: 30}) {
+		t.Fatalf("unexpected cursor %+v", cursor)
+	}
+
+	resumed := newIterator(context.Background(), pagesOf(items, &requested))
+	resumed.Seek(cursor)
+	if !resumed.Next() || resumed.Value() != 130 {
+		t.Errorf("expected to resume from item 130, got %v", resumed.Value())
+	}
+}
+
+func TestIteratorPageError(t *testing.T) {
+	items := make([]int, 250)
+	for i := range items {
+		items[i] = i
+	}
+
+	var requested []github.ListOptions
+	pages := pagesOf(items, &requested)
+	failPage := 2
+	fetch := func(opts github.ListOptions) ([]int, *github.Response, error) {
+		if opts.Page == failPage {
+			return nil, nil, errors.New("boom")
+		}
+		return pages(opts)
+	}
+
+	it := newIterator(context.Background(), fetch)
+	count := 0
+	for it.Next() {
+		count++
+	}
+
+	var pageErr *PageError
+	if !errors.As(it.Err(), &pageErr) || pageErr.Page != 2 {
+		t.Fatalf("expected error for page 2, got %v", it.Err())
+	}
+	if count != perPage {
+		t.Errorf("expected %v items before error, got %v", perPage, count)
+	}
+
+	// Повторяем загрузку неудавшейся страницы
+	failPage = 0
+	it.Seek(it.Cursor())
+	for it.Next() {
+		count++
+	}
+	if it.Err() != nil || count != len(items) {
+		t.Errorf("expected %v items after retry, got %v (err %v)", len(items), count, it.Err())
+	}
+}
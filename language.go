@@ -0,0 +1,67 @@
+package notgogithub
+
+import "sort"
+
+// Language хранит информацию об использовании языка программирования
+type Language struct {
+	Name       string  // Название языка программирования
+	Bytes      int     // Объем кода на этом языке в байтах
+	Percentage float64 // Доля кода на этом языке в процентах (от 0 до 100)
+}
+
+// newLanguages преобразует ответ GitHub (язык -> количество байт) в список языков,
+// отсортированный функцией sortLanguages
+func newLanguages(bytesByName map[string]int) []Language {
+	total := 0
+	for _, bytes := range bytesByName {
+		total += bytes
+	}
+
+	var Languages []Language
+	for name, bytes := range bytesByName {
+		l := Language{Name: name, Bytes: bytes}
+		if total > 0 {
+			l.Percentage = 100 * float64(bytes) / float64(total)
+		}
+		Languages = append(Languages, l)
+	}
+	sortLanguages(Languages)
+
+	return Languages
+}
+
+// sortLanguages упорядочивает языки по убыванию доли, а при равенстве - по названию,
+// чтобы результат не зависел от порядка обхода map
+func sortLanguages(languages []Language) {
+	sort.Slice(languages, func(i, j int) bool {
+		if languages[i].Bytes != languages[j].Bytes {
+			return languages[i].Bytes > languages[j].Bytes
+		}
+		return languages[i].Name < languages[j].Name
+	})
+}
+
+// PrimaryLanguage возвращает язык с наибольшей долей кода в репозитории.
+// Если языки неизвестны (не загружены или репозиторий пуст), ok равно false
+func (r *Repository) PrimaryLanguage() (language Language, ok bool) {
+	if len(r.Languages) == 0 {
+		return Language{}, false
+	}
+	return r.Languages[0], true
+}
+
+// AggregateLanguages суммирует объем кода на каждом языке по всем репозиториям repos,
+// например по результату GetUserRepositories для пользователя или организации,
+// и пересчитывает доли. Репозитории без загруженных языков не учитываются
+func AggregateLanguages(repos []*Repository) []Language {
+	bytesByName := make(map[string]int)
+	for _, repo := range repos {
+		if repo == nil {
+			continue
+		}
+		for _, l := range repo.Languages {
+			bytesByName[l.Name] += l.Bytes
+		}
+	}
+	return newLanguages(bytesByName)
+}
@@ -0,0 +1,93 @@
+package notgogithub
+
+import "testing"
+
+func TestNewLanguages(t *testing.T) {
+	testTable := []struct {
+		name     string
+		bytes    map[string]int
+		expected []Language
+	}{
+		{
+			name:     "empty",
+			bytes:    nil,
+			expected: nil,
+		},
+		{
+			name:  "sorted by share, then by name",
+			bytes: map[string]int{"Go": 25, "C": 25, "Python": 50},
+			expected: []Language{
+				{Name: "Python", Bytes: 50, Percentage: 50},
+				{Name: "C", Bytes: 25, Percentage: 25},
+				{Name: "Go", Bytes: 25, Percentage: 25},
+			},
+		},
+		{
+			name:  "zero bytes",
+			bytes: map[string]int{"Shell": 0},
+			expected: []Language{
+				{Name: "Shell", Bytes: 0, Percentage: 0},
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		// Act
+		languages := newLanguages(testCase.bytes)
+
+		// Assert
+		if len(languages) != len(testCase.expected) {
+			t.Errorf("Incorrect amount of languages for %s: expected %v, got %v", testCase.name, testCase.expected, languages)
+			continue
+		}
+		for i, exp := range testCase.expected {
+			if languages[i] != exp {
+				t.Errorf("Incorrect language for %s: expected %v, got %v", testCase.name, exp, languages[i])
+			}
+		}
+	}
+}
+
+func TestPrimaryLanguage(t *testing.T) {
+	// Arrange
+	repo := Repository{Languages: newLanguages(map[string]int{"Python": 8144, "Batchfile": 85})}
+
+	// Act
+	primary, ok := repo.PrimaryLanguage()
+
+	// Assert
+	if !ok || primary.Name != "Python" {
+		t.Errorf("Incorrect primary language: expected Python, got %v", primary)
+	}
+	if _, ok := (&Repository{}).PrimaryLanguage(); ok {
+		t.Errorf("expected no primary language for repository without languages")
+	}
+}
+
+func TestAggregateLanguages(t *testing.T) {
+	// Arrange
+	repos := []*Repository{
+		{Name: "a", Languages: newLanguages(map[string]int{"Go": 300, "Shell": 100})},
+		{Name: "b", Languages: newLanguages(map[string]int{"Python": 200, "Shell": 200})},
+		{Name: "c"}, // Языки не загружены
+		nil,
+	}
+	expected := []Language{
+		{Name: "Go", Bytes: 300, Percentage: 37.5},
+		{Name: "Shell", Bytes: 300, Percentage: 37.5},
+		{Name: "Python", Bytes: 200, Percentage: 25},
+	}
+
+	// Act
+	languages := AggregateLanguages(repos)
+
+	// Assert
+	if len(languages) != len(expected) {
+		t.Fatalf("Incorrect amount of languages: expected %v, got %v", expected, languages)
+	}
+	for i, exp := range expected {
+		if languages[i] != exp {
+			t.Errorf("Incorrect language: expected %v, got %v", exp, languages[i])
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package notgogithub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// mutateJSON случайно портит разобранный JSON: удаляет поля объектов, заменяет значения на null
+// и превращает элементы массивов в null. Ключи обходятся по порядку, чтобы результат зависел только от rnd
+func mutateJSON(rnd *rand.Rand, v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			switch rnd.Intn(4) {
+			case 0:
+				delete(x, k)
+			case 1:
+				x[k] = nil
+			default:
+				x[k] = mutateJSON(rnd, x[k])
+			}
+		}
+	case []interface{}:
+		for i := range x {
+			if rnd.Intn(4) == 0 {
+				x[i] = nil
+			} else {
+				x[i] = mutateJSON(rnd, x[i])
+			}
+		}
+	}
+	return v
+}
+
+// newMalformedProxy возвращает сервер, который перенаправляет запросы на target
+// и портит JSON успешных ответов функцией mutateJSON
+func newMalformedProxy(t *testing.T, target string, seed int64) *httptest.Server {
+	t.Helper()
+
+	u, err := url.Parse(target)
+	if err != nil {
+		t.Fatalf("parse target: %v", err)
+	}
+	var mu sync.Mutex
+	rnd := rand.New(rand.NewSource(seed))
+	proxy := httputil.NewSingleHostReverseProxy(u)
+	// Отмененные параллельные запросы - ожидаемая часть теста
+	proxy.ErrorLog = log.New(io.Discard, "", 0)
+	proxy.ModifyResponse = func(resp *http.Response) error {
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		var v interface{}
+		if resp.StatusCode < 300 && json.Unmarshal(body, &v) == nil {
+			// Запросы сервиса выполняются параллельно, а rnd не потокобезопасен
+			mu.Lock()
+			v = mutateJSON(rnd, v)
+			mu.Unlock()
+			if body, err = json.Marshal(v); err != nil {
+				return err
+			}
+		}
+
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		resp.ContentLength = int64(len(body))
+		resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+		return nil
+	}
+
+	srv := httptest.NewServer(proxy)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// TestServiceMalformedResponses проверяет, что ни один метод сервиса не паникует на неполных ответах:
+// поля, объекты и элементы списков в ответах симулятора случайно пропадают или становятся null
+func TestServiceMalformedResponses(t *testing.T) {
+	_, sim := newSimService(t)
+	ctx := context.Background()
+
+	for seed := int64(0); seed < 50; seed++ {
+		t.Run(fmt.Sprintf("seed=%d", seed), func(t *testing.T) {
+			proxy := newMalformedProxy(t, sim.URL, seed)
+			ghs, err := NewGitHubService(ctx, WithAuth(StaticToken("test")), WithEnterpriseURLs(proxy.URL, ""))
+			if err != nil {
+				t.Fatalf("NewGitHubService: %v", err)
+			}
+
+			// Act
+			// Ошибки разбора допустимы, паника - нет
+			ghs.GetUserInfo(ctx, "jostanise")
+			ghs.GetUserRepositories(ctx, "jostanise")
+			ghs.GetRepositoryByName(ctx, "jostanise", "rsa_encrypted_local_chat")
+			ghs.GetRepositoryBranches(ctx, "PeakIntegral", "cppLessons")
+			ghs.GetBranchCommits(ctx, "PeakIntegral", "cppLessons", "main")
+			ghs.GetRepositoryPullRequests(ctx, "PeakIntegral", "cppLessons")
+			ghs.GetThreadsInfo(ctx, "PeakIntegral", "cppLessons", 1)
+			ghs.GetIssues(ctx, "PeakIntegral", "cppLessons")
+			ghs.GetRepositoryContributors(ctx, "jostanise", "rsa_encrypted_local_chat")
+			ghs.GetRepositoryTags(ctx, "jostanise", "rsa_encrypted_local_chat")
+			ghs.CreateRepository(ctx, "malformed")
+			ghs.CreateBranch(ctx, "PeakIntegral", "cppLessons", "malformed", "d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0")
+			ghs.DeleteBranch(ctx, "PeakIntegral", "cppLessons", "malformed")
+			ghs.CreatePullRequest(ctx, "PeakIntegral", "cppLessons", "yura", "main", "malformed")
+			ghs.CreateTag(ctx, "jostanise", "rsa_encrypted_local_chat", "malformed", "0480a292df58ba0bb4851bf828ed25efc56da813")
+			ghs.DeleteTag(ctx, "jostanise", "rsa_encrypted_local_chat", "malformed")
+			ghs.GetRepositoryReleases(ctx, "jostanise", "rsa_encrypted_local_chat")
+			ghs.GetLatestRelease(ctx, "jostanise", "rsa_encrypted_local_chat")
+			if release, err := ghs.CreateRelease(ctx, "PeakIntegral", "cppLessons", "malformed", WithReleaseDraft(true)); err == nil {
+				ghs.UploadReleaseAsset(ctx, "PeakIntegral", "cppLessons", release.ID, "malformed.txt", "text/plain", strings.NewReader("malformed"))
+				ghs.DownloadReleaseAsset(ctx, "PeakIntegral", "cppLessons", release.ID, "malformed.txt")
+				ghs.PublishRelease(ctx, "PeakIntegral", "cppLessons", release.ID)
+				ghs.DeleteRelease(ctx, "PeakIntegral", "cppLessons", release.ID)
+			}
+			ghs.GetReviews(ctx, "PeakIntegral", "cppLessons", 3)
+			if review, err := ghs.CreateReview(ctx, "PeakIntegral", "cppLessons", 5, ReviewRequestChanges, "malformed", LineComment{Path: "main.cpp", Line: 1}); err == nil {
+				ghs.DismissReview(ctx, "PeakIntegral", "cppLessons", 5, review.ID, "malformed")
+			}
+			if c, err := ghs.CreateLineComment(ctx, "PeakIntegral", "cppLessons", 5, LineComment{Path: "main.cpp", StartLine: 1, Line: 2, Body: "malformed"}); err == nil {
+				ghs.ReplyToThread(ctx, "PeakIntegral", "cppLessons", 5, c.ID, "malformed")
+				ghs.EditComment(ctx, "PeakIntegral", "cppLessons", 5, c.ID, "malformed")
+				ghs.DeleteComment(ctx, "PeakIntegral", "cppLessons", 5, c.ID)
+			}
+			ghs.ReplyToThread(ctx, "PeakIntegral", "cppLessons", 5, 0, "malformed")
+			if pr, err := ghs.CreatePullRequest(ctx, "PeakIntegral", "cppLessons", "patch-1", "yura", "malformed",
+				WithPullRequestDraft(true), WithReviewers("PeakIntegral"), WithPullRequestLabels("malformed")); err == nil {
+				ghs.GetPullRequest(ctx, "PeakIntegral", "cppLessons", pr.Number)
+				ghs.MarkPullRequestReadyForReview(ctx, "PeakIntegral", "cppLessons", pr.Number)
+				ghs.ConvertPullRequestToDraft(ctx, "PeakIntegral", "cppLessons", pr.Number)
+				ghs.UpdatePullRequestTitleBody(ctx, "PeakIntegral", "cppLessons", pr.Number, "malformed", "malformed")
+				ghs.ChangePullRequestBase(ctx, "PeakIntegral", "cppLessons", pr.Number, "main")
+				ghs.ClosePullRequest(ctx, "PeakIntegral", "cppLessons", pr.Number)
+				ghs.ReopenPullRequest(ctx, "PeakIntegral", "cppLessons", pr.Number)
+			}
+			ghs.CreateIssue(ctx, "PeakIntegral", "cppLessons", "Bug", WithIssueBody("body"))
+			ghs.GetIssue(ctx, "PeakIntegral", "cppLessons", 4)
+			ghs.EditIssue(ctx, "PeakIntegral", "cppLessons", 4, WithIssueTitle("Bug"))
+			ghs.CloseIssue(ctx, "PeakIntegral", "cppLessons", 4, IssueNotPlanned)
+			ghs.ReopenIssue(ctx, "PeakIntegral", "cppLessons", 4)
+			ghs.LockIssue(ctx, "PeakIntegral", "cppLessons", 4, LockResolved)
+			ghs.UnlockIssue(ctx, "PeakIntegral", "cppLessons", 4)
+			ghs.GetIssueComments(ctx, "PeakIntegral", "cppLessons", 4)
+			ghs.CreateIssueComment(ctx, "PeakIntegral", "cppLessons", 4, "body")
+			ghs.EditIssueComment(ctx, "PeakIntegral", "cppLessons", 4, 1, "body")
+			ghs.DeleteIssueComment(ctx, "PeakIntegral", "cppLessons", 4, 1)
+			ghs.GetPullRequestFiles(ctx, "PeakIntegral", "cppLessons", 3)
+			ghs.GetPullRequestDiff(ctx, "PeakIntegral", "cppLessons", 3)
+			ghs.GetPullRequestPatch(ctx, "PeakIntegral", "cppLessons", 3)
+			ghs.GetPullRequestCommits(ctx, "PeakIntegral", "cppLessons", 3)
+			ghs.GetPullRequestMergeability(ctx, "PeakIntegral", "cppLessons", 3)
+			ghs.MergePullRequest(ctx, "PeakIntegral", "cppLessons", 3, MergeMethodRebase, "")
+			ghs.SetAccessToRepository(ctx, "PeakIntegral", "cppLessons", "jostanise")
+			ghs.DenyAccessToRepository(ctx, "PeakIntegral", "cppLessons", "jostanise")
+		})
+	}
+}
+
+// Ниже - fuzz-тесты преобразований ответов GitHub. Без флага -fuzz проверяются только начальные данные:
+// go test -run '^$' -fuzz FuzzConvertPullRequests .
+
+func FuzzConvertRepositoryCommits(f *testing.F) {
+	f.Add([]byte(`[null]`))
+	f.Add([]byte(`[{}]`))
+	f.Add([]byte(`[{"commit":null,"author":null,"parents":[null,{}]}]`))
+	f.Add([]byte(`[{"sha":"b","parents":[{"url":"https://api.github.com/repos/o/r/commits/a"}]},{"sha":"a","commit":{"author":{}}}]`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var commits []*github.RepositoryCommit
+		if json.Unmarshal(data, &commits) != nil {
+			return
+		}
+		linearizeHistory(convertRepositoryCommits(commits))
+	})
+}
+
+func FuzzConvertPullRequests(f *testing.F) {
+	f.Add([]byte(`[null]`))
+	f.Add([]byte(`[{}]`))
+	f.Add([]byte(`[{"head":null,"base":{},"user":null,"labels":[null],"assignees":[null,{}]}]`))
+	f.Add([]byte(`[{"state":"closed","merged_at":null,"draft":true}]`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var pullRequests []*github.PullRequest
+		if json.Unmarshal(data, &pullRequests) != nil {
+			return
+		}
+		for _, r := range pullRequests {
+			pullRequestState(r)
+		}
+		convertPullRequests(pullRequests)
+	})
+}
+
+func FuzzConvertIssues(f *testing.F) {
+	f.Add([]byte(`[null]`))
+	f.Add([]byte(`[{}]`))
+	f.Add([]byte(`[{"user":null,"labels":[null,{}],"assignees":[null],"pull_request":null}]`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var issues []*github.Issue
+		if json.Unmarshal(data, &issues) != nil {
+			return
+		}
+		convertIssues(issues)
+	})
+}
+
+func FuzzBuildThreads(f *testing.F) {
+	f.Add([]byte(`[null]`), []byte(`[null]`))
+	f.Add([]byte(`[{}]`), []byte(`[{}]`))
+	f.Add([]byte(`[{"id":1,"in_reply_to_id":2},{"id":2,"in_reply_to_id":1}]`), []byte(`[]`))
+	f.Add([]byte(`[{"id":2,"in_reply_to_id":1,"line":3},{"id":1,"path":"a","user":null}]`), []byte(`[{"user":null}]`))
+
+	f.Fuzz(func(t *testing.T, commentData, issueCommentData []byte) {
+		var comments []*github.PullRequestComment
+		var issueComments []*github.IssueComment
+		if json.Unmarshal(commentData, &comments) != nil || json.Unmarshal(issueCommentData, &issueComments) != nil {
+			return
+		}
+		buildThreads(comments, issueComments, map[int64]bool{1: true})
+	})
+}
+
+func FuzzNewRepository(f *testing.F) {
+	f.Add([]byte(`null`), []byte(`null`))
+	f.Add([]byte(`{}`), []byte(`{}`))
+	f.Add([]byte(`{"owner":null,"updated_at":null}`), []byte(`{"Go":0}`))
+	f.Add([]byte(`{"name":"r"}`), []byte(`{"Go":-1,"C":1}`))
+
+	f.Fuzz(func(t *testing.T, repoData, langData []byte) {
+		var r *github.Repository
+		var bytesByName map[string]int
+		if json.Unmarshal(repoData, &r) != nil || json.Unmarshal(langData, &bytesByName) != nil {
+			return
+		}
+		repo := newRepository(r, newLanguages(bytesByName))
+		repo.PrimaryLanguage()
+		AggregateLanguages([]*Repository{repo, nil})
+	})
+}
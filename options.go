@@ -0,0 +1,81 @@
+package notgogithub
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// Option настраивает gitHubService при создании через NewGitHubService
+type Option func(*options)
+
+// options хранит настройки gitHubService
+type options struct {
+	maxItems        int               // Максимальное количество элементов, которое возвращают списочные методы (0 - без ограничений)
+	rateLimitPolicy RateLimitPolicy   // Поведение при исчерпании лимита запросов
+	auth            Authenticator     // Источник учетных данных
+	baseURL         string            // Адрес API GitHub Enterprise Server ("" - api.github.com)
+	uploadURL       string            // Адрес для загрузки файлов GitHub Enterprise Server
+	transport       http.RoundTripper // Транспорт, через который уходят запросы (nil - http.DefaultTransport)
+	concurrency     int               // Максимальное количество одновременных дополнительных запросов
+}
+
+// defaultOptions возвращает настройки, с которыми работает сервис без опций
+func defaultOptions() options {
+	return options{auth: EnvToken(DefaultTokenEnv), concurrency: defaultConcurrency}
+}
+
+// WithMaxItems ограничивает количество элементов, которое списочные методы загружают со всех страниц.
+// Значение n <= 0 снимает ограничение
+func WithMaxItems(n int) Option {
+	return func(o *options) {
+		if n < 0 {
+			n = 0
+		}
+		o.maxItems = n
+	}
+}
+
+// WithEnterpriseURLs направляет запросы на GitHub Enterprise Server.
+// Суффиксы /api/v3/ и /api/uploads/ добавляются автоматически, если их нет.
+// Пустой uploadURL означает, что файлы загружаются на тот же хост, что и baseURL
+func WithEnterpriseURLs(baseURL, uploadURL string) Option {
+	return func(o *options) {
+		if uploadURL == "" {
+			uploadURL = baseURL
+		}
+		o.baseURL = baseURL
+		o.uploadURL = uploadURL
+	}
+}
+
+// WithTransport отправляет запросы к GitHub API через rt. Аутентификация выполняется поверх rt,
+// поэтому rt видит итоговые запросы. Используется, например, для записи и воспроизведения
+// ответов API пакетом cassette
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *options) {
+		o.transport = rt
+	}
+}
+
+// httpClient возвращает HTTP-клиент с транспортом из настроек (nil - клиент по умолчанию)
+func (o *options) httpClient() *http.Client {
+	if o.transport == nil {
+		return nil
+	}
+	return &http.Client{Transport: o.transport}
+}
+
+// newClient создает клиент go-github с адресами API из настроек
+func (o *options) newClient(httpClient *http.Client) (*github.Client, error) {
+	if o.baseURL == "" {
+		return github.NewClient(httpClient), nil
+	}
+
+	client, err := github.NewEnterpriseClient(o.baseURL, o.uploadURL, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("enterprise urls: %w", err)
+	}
+	return client, nil
+}
@@ -0,0 +1,47 @@
+package notgogithub
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithEnterpriseURLs(t *testing.T) {
+	// Arrange
+	testTable := []struct {
+		baseURL        string
+		uploadURL      string
+		expectedBase   string
+		expectedUpload string
+	}{
+		{
+			baseURL:        "https://ghe.example.com",
+			expectedBase:   "https://ghe.example.com/api/v3/",
+			expectedUpload: "https://ghe.example.com/api/uploads/",
+		},
+		{
+			baseURL:        "https://ghe.example.com/api/v3/",
+			uploadURL:      "https://uploads.ghe.example.com/api/uploads/",
+			expectedBase:   "https://ghe.example.com/api/v3/",
+			expectedUpload: "https://uploads.ghe.example.com/api/uploads/",
+		},
+	}
+
+	for _, testCase := range testTable {
+		// Act
+		ghs, err := NewGitHubService(context.Background(),
+			WithAuth(StaticToken("token")),
+			WithEnterpriseURLs(testCase.baseURL, testCase.uploadURL))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		client := ghs.(*gitHubService).client
+		if client.BaseURL.String() != testCase.expectedBase {
+			t.Errorf("Incorrect base URL: expected %v, got %v", testCase.expectedBase, client.BaseURL)
+		}
+		if client.UploadURL.String() != testCase.expectedUpload {
+			t.Errorf("Incorrect upload URL: expected %v, got %v", testCase.expectedUpload, client.UploadURL)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package notgogithub
+
+import (
+	"context"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// perPage - максимальный размер страницы, который допускает GitHub API
+const perPage = 100
+
+// pageFetcher загружает одну страницу списка с указанными параметрами пагинации
+type pageFetcher[T any] func(opts github.ListOptions) ([]T, *github.Response, error)
+
+// collectPages загружает страницы одну за другой, следуя за NextPage из ответа,
+// пока страницы не закончатся или не будет набрано limit элементов (limit <= 0 - без ограничений)
+func collectPages[T any](ctx context.Context, limit int, fetch pageFetcher[T]) ([]T, error) {
+	opts := github.ListOptions{PerPage: perPage}
+	if limit > 0 && limit < perPage {
+		// Не запрашиваем больше, чем нужно вызывающему
+		opts.PerPage = limit
+	}
+
+	var all []T
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		items, resp, err := fetch(opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+
+		if limit > 0 && len(all) >= limit {
+			return all[:limit], nil
+		}
+		if resp == nil || resp.NextPage == 0 {
+			return all, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// convertPages возвращает загрузчик, который применяет convert к каждой загруженной странице
+func convertPages[R, T any](fetch pageFetcher[R], convert func([]R) ([]T, error)) pageFetcher[T] {
+	return func(opts github.ListOptions) ([]T, *github.Response, error) {
+		raw, resp, err := fetch(opts)
+		if err != nil {
+			return nil, resp, err
+		}
+
+		items, err := convert(raw)
+		return items, resp, err
+	}
+}
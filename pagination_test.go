@@ -0,0 +1,90 @@
+package notgogithub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// pagesOf возвращает pageFetcher, отдающий элементы items страницами по размеру из ListOptions
+func pagesOf(items []int, requested *[]github.ListOptions) pageFetcher[int] {
+	return func(opts github.ListOptions) ([]int, *github.Response, error) {
+		*requested = append(*requested, opts)
+
+		page := opts.Page
+		if page == 0 {
+			page = 1
+		}
+		start := (page - 1) * opts.PerPage
+		end := start + opts.PerPage
+		if end > len(items) {
+			end = len(items)
+		}
+
+		resp := &github.Response{}
+		if end < len(items) {
+			resp.NextPage = page + 1
+		}
+		return items[start:end], resp, nil
+	}
+}
+
+func TestCollectPages(t *testing.T) {
+	items := make([]int, 250)
+	for i := range items {
+		items[i] = i
+	}
+
+	// Arrange
+	testTable := []struct {
+		name          string
+		limit         int
+		expectedLen   int
+		expectedCalls int
+	}{
+		{name: "all pages", limit: 0, expectedLen: 250, expectedCalls: 3},
+		{name: "limit inside first page", limit: 10, expectedLen: 10, expectedCalls: 1},
+		{name: "limit across pages", limit: 150, expectedLen: 150, expectedCalls: 2},
+		{name: "limit above total", limit: 1000, expectedLen: 250, expectedCalls: 3},
+	}
+
+	for _, testCase := range testTable {
+		var requested []github.ListOptions
+
+		// Act
+		result, err := collectPages(context.Background(), testCase.limit, pagesOf(items, &requested))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", testCase.name, err)
+		}
+		if len(result) != testCase.expectedLen {
+			t.Errorf("%s: expected %v items, got %v", testCase.name, testCase.expectedLen, len(result))
+		}
+		if len(requested) != testCase.expectedCalls {
+			t.Errorf("%s: expected %v requests, got %v", testCase.name, testCase.expectedCalls, len(requested))
+		}
+		for i, v := range result {
+			if v != i {
+				t.Errorf("%s: item %v out of order: got %v", testCase.name, i, v)
+				break
+			}
+		}
+	}
+}
+
+func TestCollectPagesCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var requested []github.ListOptions
+	_, err := collectPages(ctx, 0, pagesOf([]int{1, 2, 3}, &requested))
+
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if len(requested) != 0 {
+		t.Errorf("expected no requests after cancel, got %v", len(requested))
+	}
+}
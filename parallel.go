@@ -0,0 +1,86 @@
+package notgogithub
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultConcurrency - количество одновременных дополнительных запросов по умолчанию.
+// GitHub ограничивает частые параллельные запросы вторичным лимитом, поэтому значение небольшое
+const defaultConcurrency = 4
+
+// WithConcurrency задает, сколько дополнительных запросов (языки репозиториев, коммиты веток,
+// профили соавторов, релизы тегов, комментарии ревью) сервис выполняет одновременно.
+// Значение n <= 1 отключает параллельность
+func WithConcurrency(n int) Option {
+	return func(o *options) {
+		if n < 1 {
+			n = 1
+		}
+		o.concurrency = n
+	}
+}
+
+// parallel вызывает fn для каждого элемента items, выполняя не больше n вызовов одновременно,
+// и возвращает результаты в порядке items. Первая ошибка отменяет контекст остальных вызовов
+// и возвращается вызывающему. Лимит запросов соблюдает ghs.do внутри fn
+func parallel[T, R any](ctx context.Context, n int, items []T, fn func(ctx context.Context, item T) (R, error)) ([]R, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+	results := make([]R, len(items))
+	if n < 1 {
+		n = 1
+	}
+	if n > len(items) {
+		n = len(items)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	indexes := make(chan int)
+
+	for w := 0; w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				r, err := fn(ctx, items[i])
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				results[i] = r
+			}
+		}()
+	}
+
+feed:
+	for i := range items {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	// Родительский контекст могли отменить до того, как все элементы были обработаны
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
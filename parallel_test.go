@@ -0,0 +1,122 @@
+package notgogithub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParallel(t *testing.T) {
+	tests := []struct {
+		name  string
+		n     int
+		items []int
+	}{
+		{"sequential", 1, []int{1, 2, 3, 4, 5}},
+		{"bounded", 3, []int{5, 4, 3, 2, 1, 0, 9, 8, 7, 6}},
+		{"more workers than items", 16, []int{7, 8}},
+		{"empty", 4, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var (
+				mu       sync.Mutex
+				inFlight int
+				maxSeen  int
+			)
+
+			// Act
+			got, err := parallel(context.Background(), tt.n, tt.items, func(ctx context.Context, item int) (int, error) {
+				mu.Lock()
+				inFlight++
+				if inFlight > maxSeen {
+					maxSeen = inFlight
+				}
+				mu.Unlock()
+
+				// Элементы с меньшим значением обрабатываются дольше, чтобы результаты приходили не по порядку
+				time.Sleep(time.Duration(10-item) * time.Millisecond)
+
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+				return item * 10, nil
+			})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("parallel: %v", err)
+			}
+			if len(got) != len(tt.items) {
+				t.Fatalf("Incorrect number of results: expected %v, got %v", len(tt.items), len(got))
+			}
+			for i, item := range tt.items {
+				if got[i] != item*10 {
+					t.Errorf("Incorrect result %v: expected %v, got %v", i, item*10, got[i])
+				}
+			}
+			if maxSeen > tt.n {
+				t.Errorf("Incorrect concurrency: expected at most %v calls at once, got %v", tt.n, maxSeen)
+			}
+		})
+	}
+}
+
+func TestParallelFirstErrorCancels(t *testing.T) {
+	// Arrange
+	boom := errors.New("boom")
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+
+	var (
+		mu    sync.Mutex
+		calls int
+	)
+
+	// Act
+	_, err := parallel(context.Background(), 2, items, func(ctx context.Context, item int) (int, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+
+		if item == 3 {
+			return 0, boom
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(5 * time.Millisecond):
+			return item, nil
+		}
+	})
+
+	// Assert
+	if !errors.Is(err, boom) {
+		t.Errorf("Incorrect error: expected %v, got %v", boom, err)
+	}
+	if calls >= len(items) {
+		t.Errorf("expected remaining items to be skipped after error, got %v calls", calls)
+	}
+}
+
+func TestParallelCanceledContext(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	_, err := parallel(ctx, 4, []int{1, 2, 3}, func(ctx context.Context, item int) (int, error) {
+		return item, nil
+	})
+
+	// Assert
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Incorrect error: expected %v, got %v", context.Canceled, err)
+	}
+}
@@ -0,0 +1,185 @@
+package notgogithub
+
+import (
+	"context"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// MergeMethod - способ слияния запроса на слияние
+type MergeMethod string
+
+const (
+	MergeMethodMerge  MergeMethod = "merge"  // Коммит слияния с двумя родителями
+	MergeMethodSquash MergeMethod = "squash" // Все коммиты запроса одним коммитом
+	MergeMethodRebase MergeMethod = "rebase" // Коммиты запроса переносятся поверх ветки-назначения
+)
+
+// PullRequestOption задает необязательное поле запроса на слияние в CreatePullRequest
+type PullRequestOption func(*pullRequestRequest)
+
+// WithPullRequestBody задает описание запроса на слияние
+func WithPullRequestBody(body string) PullRequestOption {
+	return func(r *pullRequestRequest) {
+		r.body = body
+	}
+}
+
+// WithPullRequestDraft создает запрос на слияние черновиком
+func WithPullRequestDraft(draft bool) PullRequestOption {
+	return func(r *pullRequestRequest) {
+		r.draft = draft
+	}
+}
+
+// WithReviewers запрашивает ревью у пользователей с логинами logins
+func WithReviewers(logins ...string) PullRequestOption {
+	return func(r *pullRequestRequest) {
+		r.reviewers = append(r.reviewers, logins...)
+	}
+}
+
+// WithPullRequestLabels добавляет запросу на слияние метки. Отсутствующие в репозитории метки GitHub создаст
+func WithPullRequestLabels(labels ...string) PullRequestOption {
+	return func(r *pullRequestRequest) {
+		r.labels = append(r.labels, labels...)
+	}
+}
+
+// pullRequestRequest - необязательные поля нового запроса на слияние
+type pullRequestRequest struct {
+	body      string
+	draft     bool
+	reviewers []string
+	labels    []string
+}
+
+func newPullRequestRequest(opts []PullRequestOption) *pullRequestRequest {
+	var r pullRequestRequest
+	for _, opt := range opts {
+		opt(&r)
+	}
+	return &r
+}
+
+func (ghs *gitHubService) CreatePullRequest(ctx context.Context, owner, repositoryName, sourceBranch, destBranch, title string, opts ...PullRequestOption) (*PullRequest, error) {
+	req := newPullRequestRequest(opts)
+	pull := github.NewPullRequest{Title: &title, Head: &sourceBranch, Base: &destBranch, Draft: &req.draft}
+	if req.body != "" {
+		pull.Body = &req.body
+	}
+	pr, _, err := callAPI(ctx, ghs, "create pull request", func() (*github.PullRequest, *github.Response, error) {
+		return ghs.client.PullRequests.Create(ctx, owner, repositoryName, &pull)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Рецензенты и метки назначаются отдельными запросами уже созданному запросу на слияние
+	if len(req.reviewers) > 0 {
+		reviewers := github.ReviewersRequest{Reviewers: req.reviewers}
+		updated, _, err := callAPI(ctx, ghs, "request reviewers", func() (*github.PullRequest, *github.Response, error) {
+			return ghs.client.PullRequests.RequestReviewers(ctx, owner, repositoryName, pr.GetNumber(), reviewers)
+		})
+		if err != nil {
+			return newPullRequest(pr), err
+		}
+		pr = updated
+	}
+	if len(req.labels) > 0 {
+		labels, _, err := callAPI(ctx, ghs, "add labels", func() ([]*github.Label, *github.Response, error) {
+			return ghs.client.Issues.AddLabelsToIssue(ctx, owner, repositoryName, pr.GetNumber(), req.labels)
+		})
+		if err != nil {
+			return newPullRequest(pr), err
+		}
+		pr.Labels = labels
+	}
+
+	return newPullRequest(pr), nil
+}
+
+func (ghs *gitHubService) GetPullRequest(ctx context.Context, owner, repositoryName string, pullRequestID int) (*PullRequest, error) {
+	pr, _, err := callAPI(ctx, ghs, "get pull request", func() (*github.PullRequest, *github.Response, error) {
+		return ghs.client.PullRequests.Get(ctx, owner, repositoryName, pullRequestID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newPullRequest(pr), nil
+}
+
+func (ghs *gitHubService) MergePullRequest(ctx context.Context, owner, repositoryName string, pullRequestID int, method MergeMethod, headSHA string) (string, error) {
+	opts := github.PullRequestOptions{SHA: headSHA, MergeMethod: string(method)}
+	result, _, err := callAPI(ctx, ghs, "merge pull request", func() (*github.PullRequestMergeResult, *github.Response, error) {
+		return ghs.client.PullRequests.Merge(ctx, owner, repositoryName, pullRequestID, "", &opts)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return result.GetSHA(), nil
+}
+
+func (ghs *gitHubService) ClosePullRequest(ctx context.Context, owner, repositoryName string, pullRequestID int) (*PullRequest, error) {
+	return ghs.editPullRequest(ctx, owner, repositoryName, pullRequestID, &github.PullRequest{State: github.String("closed")})
+}
+
+func (ghs *gitHubService) ReopenPullRequest(ctx context.Context, owner, repositoryName string, pullRequestID int) (*PullRequest, error) {
+	return ghs.editPullRequest(ctx, owner, repositoryName, pullRequestID, &github.PullRequest{State: github.String("open")})
+}
+
+func (ghs *gitHubService) UpdatePullRequestTitleBody(ctx context.Context, owner, repositoryName string, pullRequestID int, title, body string) (*PullRequest, error) {
+	return ghs.editPullRequest(ctx, owner, repositoryName, pullRequestID, &github.PullRequest{Title: &title, Body: &body})
+}
+
+func (ghs *gitHubService) ChangePullRequestBase(ctx context.Context, owner, repositoryName string, pullRequestID int, destBranch string) (*PullRequest, error) {
+	return ghs.editPullRequest(ctx, owner, repositoryName, pullRequestID, &github.PullRequest{Base: &github.PullRequestBranch{Ref: &destBranch}})
+}
+
+// editPullRequest меняет поля запроса на слияние, заданные в pull
+func (ghs *gitHubService) editPullRequest(ctx context.Context, owner, repositoryName string, pullRequestID int, pull *github.PullRequest) (*PullRequest, error) {
+	pr, _, err := callAPI(ctx, ghs, "edit pull request", func() (*github.PullRequest, *github.Response, error) {
+		return ghs.client.PullRequests.Edit(ctx, owner, repositoryName, pullRequestID, pull)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newPullRequest(pr), nil
+}
+
+// Перевести запрос на слияние в черновик и обратно можно только через GraphQL API
+const (
+	convertToDraftMutation = `mutation($id: ID!) {
+  convertPullRequestToDraft(input: {pullRequestId: $id}) { pullRequest { isDraft } }
+}`
+	readyForReviewMutation = `mutation($id: ID!) {
+  markPullRequestReadyForReview(input: {pullRequestId: $id}) { pullRequest { isDraft } }
+}`
+)
+
+func (ghs *gitHubService) ConvertPullRequestToDraft(ctx context.Context, owner, repositoryName string, pullRequestID int) (*PullRequest, error) {
+	return ghs.setPullRequestDraft(ctx, owner, repositoryName, pullRequestID, "convert pull request to draft", convertToDraftMutation)
+}
+
+func (ghs *gitHubService) MarkPullRequestReadyForReview(ctx context.Context, owner, repositoryName string, pullRequestID int) (*PullRequest, error) {
+	return ghs.setPullRequestDraft(ctx, owner, repositoryName, pullRequestID, "mark pull request ready for review", readyForReviewMutation)
+}
+
+// setPullRequestDraft выполняет мутацию mutation над запросом на слияние: GraphQL API находит
+// его по глобальному идентификатору узла из REST API. Возвращает запрос после изменения
+func (ghs *gitHubService) setPullRequestDraft(ctx context.Context, owner, repositoryName string, pullRequestID int, op, mutation string) (*PullRequest, error) {
+	pr, _, err := callAPI(ctx, ghs, "get pull request", func() (*github.PullRequest, *github.Response, error) {
+		return ghs.client.PullRequests.Get(ctx, owner, repositoryName, pullRequestID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := graphQL[struct{}](ctx, ghs, op, mutation, map[string]interface{}{"id": pr.GetNodeID()}); err != nil {
+		return nil, err
+	}
+	return ghs.GetPullRequest(ctx, owner, repositoryName, pullRequestID)
+}
@@ -0,0 +1,156 @@
+package notgogithub
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// RateLimitPolicy определяет, как сервис ведет себя при исчерпании лимита запросов к GitHub API
+type RateLimitPolicy int
+
+const (
+	// RateLimitFailFast - сразу вернуть ошибку лимита вызывающему (поведение по умолчанию)
+	RateLimitFailFast RateLimitPolicy = iota
+
+	// RateLimitWait - дождаться сброса лимита и повторить запрос
+	RateLimitWait
+)
+
+// abuseRetryDelay - пауза перед повтором, если GitHub не сообщил Retry-After для вторичного лимита
+const abuseRetryDelay = time.Minute
+
+// RateLimit хранит состояние лимита запросов к GitHub API
+type RateLimit struct {
+	Limit     int       // Количество запросов в час
+	Remaining int       // Сколько запросов осталось до сброса
+	Reset     time.Time // Время сброса лимита
+}
+
+// WithRateLimitPolicy задает поведение сервиса при исчерпании лимита запросов
+func WithRateLimitPolicy(policy RateLimitPolicy) Option {
+	return func(o *options) {
+		o.rateLimitPolicy = policy
+	}
+}
+
+// RateLimitStatus возвращает текущий остаток лимита запросов.
+// Запрос к /rate_limit сам по себе лимит не расходует
+func (ghs *gitHubService) RateLimitStatus(ctx context.Context) (*RateLimit, error) {
+	limits, _, err := callAPI(ctx, ghs, "get rate limit", func() (*github.RateLimits, *github.Response, error) {
+		return ghs.client.RateLimits(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	core := limits.GetCore()
+	if core == nil {
+		return ghs.lastRate(), nil
+	}
+
+	ghs.updateRate(*core)
+	return ghs.lastRate(), nil
+}
+
+// callAPI выполняет запрос op к GitHub API через do
+func callAPI[T any](ctx context.Context, ghs *gitHubService, op string, call func() (T, *github.Response, error)) (T, *github.Response, error) {
+	var (
+		result T
+		resp   *github.Response
+	)
+	err := ghs.do(ctx, op, func() (*github.Response, error) {
+		var err error
+		result, resp, err = call()
+		return resp, err
+	})
+	return result, resp, err
+}
+
+// do выполняет запрос op к GitHub API, запоминает значения заголовков X-RateLimit-* из ответа
+// и при политике RateLimitWait ждет сброса лимита, прежде чем повторить запрос.
+// Ошибка запроса возвращается как *Error
+func (ghs *gitHubService) do(ctx context.Context, op string, call func() (*github.Response, error)) error {
+	for {
+		if ghs.opts.rateLimitPolicy == RateLimitWait {
+			// Лимит уже известен как исчерпанный: не тратим запрос впустую
+			if rate := ghs.lastRate(); rate.Limit > 0 && rate.Remaining == 0 {
+				if err := sleepContext(ctx, time.Until(rate.Reset)); err != nil {
+					return err
+				}
+			}
+		}
+
+		resp, err := call()
+		if resp != nil && resp.Rate.Limit > 0 {
+			ghs.updateRate(resp.Rate)
+		}
+		if err == nil {
+			return nil
+		}
+
+		wait, limited := rateLimitDelay(err)
+		if !limited || ghs.opts.rateLimitPolicy != RateLimitWait {
+			return newError(op, resp, err)
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// rateLimitDelay определяет, вызвана ли ошибка лимитом запросов, и сколько ждать до повтора
+func rateLimitDelay(err error) (time.Duration, bool) {
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		return time.Until(rateErr.Rate.Reset.Time), true
+	}
+
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		if abuseErr.RetryAfter != nil {
+			return *abuseErr.RetryAfter, true
+		}
+		return abuseRetryDelay, true
+	}
+
+	return 0, false
+}
+
+// sleepContext ждет d или отмены ctx
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (ghs *gitHubService) updateRate(rate github.Rate) {
+	ghs.rateMu.Lock()
+	defer ghs.rateMu.Unlock()
+
+	ghs.rate = RateLimit{
+		Limit:     rate.Limit,
+		Remaining: rate.Remaining,
+		Reset:     rate.Reset.Time,
+	}
+}
+
+func (ghs *gitHubService) lastRate() *RateLimit {
+	ghs.rateMu.Lock()
+	defer ghs.rateMu.Unlock()
+
+	rate := ghs.rate
+	return &rate
+}
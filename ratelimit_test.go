@@ -0,0 +1,85 @@
+package notgogithub
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// rateLimited возвращает ответ, в котором лимит исчерпан до момента reset
+func rateLimited(reset time.Time) (*github.Response, error) {
+	rate := github.Rate{Limit: 5000, Remaining: 0, Reset: github.Timestamp{Time: reset}}
+	resp := &github.Response{Response: &http.Response{StatusCode: http.StatusForbidden}, Rate: rate}
+	return resp, &github.RateLimitError{Rate: rate, Response: resp.Response}
+}
+
+func TestDoRateLimitFailFast(t *testing.T) {
+	ghs := &gitHubService{}
+
+	calls := 0
+	err := ghs.do(context.Background(), "test", func() (*github.Response, error) {
+		calls++
+		return rateLimited(time.Now().Add(time.Hour))
+	})
+
+	var rateErr *github.RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("expected RateLimitError, got %v", err)
+	}
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %v", calls)
+	}
+	if rate := ghs.lastRate(); rate.Limit != 5000 || rate.Remaining != 0 {
+		t.Errorf("rate headers were not recorded: %+v", rate)
+	}
+}
+
+func TestDoRateLimitWait(t *testing.T) {
+	ghs := &gitHubService{opts: options{rateLimitPolicy: RateLimitWait}}
+
+	retryAfter := 10 * time.Millisecond
+	calls := 0
+	err := ghs.do(context.Background(), "test", func() (*github.Response, error) {
+		calls++
+		switch calls {
+		case 1:
+			return rateLimited(time.Now().Add(20 * time.Millisecond))
+		case 2:
+			return nil, &github.AbuseRateLimitError{RetryAfter: &retryAfter}
+		}
+		rate := github.Rate{Limit: 5000, Remaining: 4999, Reset: github.Timestamp{Time: time.Now().Add(time.Hour)}}
+		return &github.Response{Rate: rate}, nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %v", calls)
+	}
+	if rate := ghs.lastRate(); rate.Remaining != 4999 {
+		t.Errorf("expected remaining 4999, got %v", rate.Remaining)
+	}
+}
+
+func TestDoRateLimitWaitCanceled(t *testing.T) {
+	ghs := &gitHubService{opts: options{rateLimitPolicy: RateLimitWait}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := ghs.do(ctx, "test", func() (*github.Response, error) {
+		return rateLimited(time.Now().Add(time.Hour))
+	})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,365 @@
+package notgogithub
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// Release хранит информацию о релизе репозитория
+type Release struct {
+	ID          int64          // Идентификатор релиза
+	TagName     string         // Название тега релиза
+	Target      string         // Ветка или SHA коммита, на котором создается тег при публикации
+	Name        string         // Название релиза
+	Body        string         // Описание релиза
+	Draft       bool           // Релиз - черновик, он виден только соавторам
+	Prerelease  bool           // Релиз отмечен как предварительный
+	Author      string         // Логин автора
+	Link        string         // Ссылка на страницу релиза
+	Assets      []ReleaseAsset // Файлы релиза
+	CreatedAt   time.Time      // Дата создания
+	PublishedAt time.Time      // Дата публикации (нулевая у черновика)
+}
+
+// ReleaseAsset хранит информацию о файле, прикрепленном к релизу
+type ReleaseAsset struct {
+	ID            int64     // Идентификатор файла
+	Name          string    // Имя файла
+	ContentType   string    // MIME-тип содержимого
+	Size          int       // Размер в байтах
+	DownloadCount int       // Количество скачиваний
+	DownloadLink  string    // Ссылка на скачивание из браузера
+	CreatedAt     time.Time // Дата загрузки
+}
+
+// ReleaseOption задает поле релиза в CreateRelease и EditRelease.
+// EditRelease меняет только поля, для которых передана опция
+type ReleaseOption func(*releaseRequest)
+
+// WithReleaseName задает название релиза
+func WithReleaseName(name string) ReleaseOption {
+	return func(r *releaseRequest) {
+		r.Name = &name
+	}
+}
+
+// WithReleaseNotes задает описание релиза
+func WithReleaseNotes(body string) ReleaseOption {
+	return func(r *releaseRequest) {
+		r.Body = &body
+	}
+}
+
+// WithTargetCommitish задает ветку или SHA коммита, на котором GitHub создаст тег релиза
+// при публикации, если тега еще нет. По умолчанию - ветка по умолчанию репозитория
+func WithTargetCommitish(commitish string) ReleaseOption {
+	return func(r *releaseRequest) {
+		r.TargetCommitish = &commitish
+	}
+}
+
+// WithReleaseDraft делает релиз черновиком (true) или публикует его (false)
+func WithReleaseDraft(draft bool) ReleaseOption {
+	return func(r *releaseRequest) {
+		r.Draft = &draft
+	}
+}
+
+// WithReleasePrerelease отмечает релиз как предварительный или снимает отметку
+func WithReleasePrerelease(prerelease bool) ReleaseOption {
+	return func(r *releaseRequest) {
+		r.Prerelease = &prerelease
+	}
+}
+
+// WithReleaseLatest делает релиз последним (latest) релизом репозитория или запрещает ему им быть.
+// Без опции последним становится самый новый опубликованный релиз, не отмеченный как предварительный
+func WithReleaseLatest(latest bool) ReleaseOption {
+	return func(r *releaseRequest) {
+		makeLatest := strconv.FormatBool(latest)
+		r.MakeLatest = &makeLatest
+	}
+}
+
+// releaseRequest - тело запроса создания или изменения релиза. В github.RepositoryRelease
+// из go-github v45 нет поля make_latest, поэтому запрос отправляется с собственным телом
+type releaseRequest struct {
+	TagName         *string `json:"tag_name,omitempty"`
+	TargetCommitish *string `json:"target_commitish,omitempty"`
+	Name            *string `json:"name,omitempty"`
+	Body            *string `json:"body,omitempty"`
+	Draft           *bool   `json:"draft,omitempty"`
+	Prerelease      *bool   `json:"prerelease,omitempty"`
+	MakeLatest      *string `json:"make_latest,omitempty"`
+}
+
+func newReleaseRequest(opts []ReleaseOption) *releaseRequest {
+	var r releaseRequest
+	for _, opt := range opts {
+		opt(&r)
+	}
+	return &r
+}
+
+// newRelease преобразует релиз GitHub в Release
+func newRelease(r *github.RepositoryRelease) *Release {
+	release := &Release{
+		ID:          r.GetID(),
+		TagName:     r.GetTagName(),
+		Target:      r.GetTargetCommitish(),
+		Name:        r.GetName(),
+		Body:        r.GetBody(),
+		Draft:       r.GetDraft(),
+		Prerelease:  r.GetPrerelease(),
+		Author:      r.GetAuthor().GetLogin(),
+		Link:        r.GetHTMLURL(),
+		CreatedAt:   r.GetCreatedAt().Time,
+		PublishedAt: r.GetPublishedAt().Time,
+	}
+	for _, a := range nonNil(r.Assets) {
+		release.Assets = append(release.Assets, newReleaseAsset(a))
+	}
+	return release
+}
+
+// newReleaseAsset преобразует файл релиза GitHub в ReleaseAsset
+func newReleaseAsset(a *github.ReleaseAsset) ReleaseAsset {
+	return ReleaseAsset{
+		ID:            a.GetID(),
+		Name:          a.GetName(),
+		ContentType:   a.GetContentType(),
+		Size:          a.GetSize(),
+		DownloadCount: a.GetDownloadCount(),
+		DownloadLink:  a.GetBrowserDownloadURL(),
+		CreatedAt:     a.GetCreatedAt().Time,
+	}
+}
+
+// convertReleases преобразует релизы GitHub в Release
+func convertReleases(releases []*github.RepositoryRelease) []*Release {
+	var Releases []*Release
+	for _, r := range nonNil(releases) {
+		Releases = append(Releases, newRelease(r))
+	}
+	return Releases
+}
+
+func (ghs *gitHubService) GetRepositoryReleases(ctx context.Context, owner, repositoryName string) ([]*Release, error) {
+	releases, err := collectPages(ctx, ghs.opts.maxItems, ghs.releasePages(ctx, owner, repositoryName))
+	if err != nil {
+		return nil, err
+	}
+
+	return convertReleases(releases), nil
+}
+
+// releasePages загружает страницы списка релизов репозитория
+func (ghs *gitHubService) releasePages(ctx context.Context, owner, repositoryName string) pageFetcher[*github.RepositoryRelease] {
+	return func(lo github.ListOptions) ([]*github.RepositoryRelease, *github.Response, error) {
+		return callAPI(ctx, ghs, "list releases", func() ([]*github.RepositoryRelease, *github.Response, error) {
+			return ghs.client.Repositories.ListReleases(ctx, owner, repositoryName, &lo)
+		})
+	}
+}
+
+func (ghs *gitHubService) GetReleaseByTag(ctx context.Context, owner, repositoryName, tagName string) (*Release, error) {
+	release, _, err := callAPI(ctx, ghs, "get release by tag", func() (*github.RepositoryRelease, *github.Response, error) {
+		return ghs.client.Repositories.GetReleaseByTag(ctx, owner, repositoryName, tagName)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newRelease(release), nil
+}
+
+func (ghs *gitHubService) GetRelease(ctx context.Context, owner, repositoryName string, releaseID int64) (*Release, error) {
+	release, _, err := callAPI(ctx, ghs, "get release", func() (*github.RepositoryRelease, *github.Response, error) {
+		return ghs.client.Repositories.GetRelease(ctx, owner, repositoryName, releaseID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newRelease(release), nil
+}
+
+func (ghs *gitHubService) GetLatestRelease(ctx context.Context, owner, repositoryName string) (*Release, error) {
+	release, _, err := callAPI(ctx, ghs, "get latest release", func() (*github.RepositoryRelease, *github.Response, error) {
+		return ghs.client.Repositories.GetLatestRelease(ctx, owner, repositoryName)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newRelease(release), nil
+}
+
+func (ghs *gitHubService) CreateRelease(ctx context.Context, owner, repositoryName, tagName string, opts ...ReleaseOption) (*Release, error) {
+	body := newReleaseRequest(opts)
+	body.TagName = &tagName
+	u := fmt.Sprintf("repos/%s/%s/releases", owner, repositoryName)
+	return ghs.sendRelease(ctx, "create release", http.MethodPost, u, body)
+}
+
+func (ghs *gitHubService) EditRelease(ctx context.Context, owner, repositoryName string, releaseID int64, opts ...ReleaseOption) (*Release, error) {
+	u := fmt.Sprintf("repos/%s/%s/releases/%d", owner, repositoryName, releaseID)
+	return ghs.sendRelease(ctx, "edit release", http.MethodPatch, u, newReleaseRequest(opts))
+}
+
+func (ghs *gitHubService) PublishRelease(ctx context.Context, owner, repositoryName string, releaseID int64) (*Release, error) {
+	return ghs.EditRelease(ctx, owner, repositoryName, releaseID, WithReleaseDraft(false))
+}
+
+// sendRelease отправляет запрос создания или изменения релиза
+func (ghs *gitHubService) sendRelease(ctx context.Context, op, method, u string, body *releaseRequest) (*Release, error) {
+	release, _, err := callAPI(ctx, ghs, op, func() (*github.RepositoryRelease, *github.Response, error) {
+		req, err := ghs.client.NewRequest(method, u, body)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		release := new(github.RepositoryRelease)
+		resp, err := ghs.client.Do(ctx, req, release)
+		return release, resp, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newRelease(release), nil
+}
+
+func (ghs *gitHubService) DeleteRelease(ctx context.Context, owner, repositoryName string, releaseID int64) error {
+	return ghs.do(ctx, "delete release", func() (*github.Response, error) {
+		return ghs.client.Repositories.DeleteRelease(ctx, owner, repositoryName, releaseID)
+	})
+}
+
+func (ghs *gitHubService) UploadReleaseAsset(ctx context.Context, owner, repositoryName string, releaseID int64, name, contentType string, content io.ReadSeeker) (*ReleaseAsset, error) {
+	u := fmt.Sprintf("repos/%s/%s/releases/%d/assets?%s", owner, repositoryName, releaseID, url.Values{"name": {name}}.Encode())
+	asset, _, err := callAPI(ctx, ghs, "upload release asset", func() (*github.ReleaseAsset, *github.Response, error) {
+		// При повторе после ожидания лимита содержимое отправляется с начала
+		size, err := content.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, err := content.Seek(0, io.SeekStart); err != nil {
+			return nil, nil, err
+		}
+
+		// NopCloser не дает HTTP-клиенту закрыть файл вызывающего
+		req, err := ghs.client.NewUploadRequest(u, io.NopCloser(content), size, contentType)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		asset := new(github.ReleaseAsset)
+		resp, err := ghs.client.Do(ctx, req, asset)
+		return asset, resp, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	a := newReleaseAsset(asset)
+	return &a, nil
+}
+
+func (ghs *gitHubService) DownloadReleaseAsset(ctx context.Context, owner, repositoryName string, releaseID int64, assetName string) (io.ReadCloser, error) {
+	release, err := ghs.GetRelease(ctx, owner, repositoryName, releaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var asset *ReleaseAsset
+	for i := range release.Assets {
+		if release.Assets[i].Name == assetName {
+			asset = &release.Assets[i]
+			break
+		}
+	}
+	if asset == nil {
+		return nil, &Error{
+			Op:   "download release asset",
+			Err:  fmt.Errorf("release %d has no asset %q", releaseID, assetName),
+			kind: ErrNotFound,
+		}
+	}
+
+	// GitHub перенаправляет на хранилище файлов. Запрос туда идет без токена, но через тот же транспорт
+	storage := ghs.opts.httpClient()
+	if storage == nil {
+		storage = http.DefaultClient
+	}
+
+	var rc io.ReadCloser
+	err = ghs.do(ctx, "download release asset", func() (*github.Response, error) {
+		var (
+			resp *github.Response
+			err  error
+		)
+		rc, resp, err = ghs.downloadReleaseAsset(ctx, owner, repositoryName, asset.ID, storage)
+		return resp, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rc, nil
+}
+
+// downloadReleaseAsset загружает содержимое файла релиза с идентификатором id. В отличие от
+// DownloadReleaseAsset из go-github, возвращает ответ GitHub API: по нему ошибка получает
+// HTTP-статус и идентификатор запроса, а сервис - остаток лимита запросов
+func (ghs *gitHubService) downloadReleaseAsset(ctx context.Context, owner, repositoryName string, id int64, storage *http.Client) (io.ReadCloser, *github.Response, error) {
+	// Клиент API не следует перенаправлению, чтобы токен не ушел в хранилище файлов
+	hc := ghs.client.Client()
+	hc.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	api, err := ghs.opts.newClient(hc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := api.NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s/releases/assets/%d", owner, repositoryName, id), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+
+	resp, err := api.BareDo(ctx, req)
+	location := ""
+	if resp != nil && resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		location = resp.Header.Get("Location")
+	}
+	switch {
+	case location == "" && err != nil:
+		return nil, resp, err
+	case location == "":
+		return resp.Body, resp, nil
+	}
+
+	download, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+	download.Header.Set("Accept", "*/*")
+	file, err := storage.Do(download)
+	if err != nil {
+		return nil, resp, err
+	}
+	// Ошибка хранилища описывается его ответом, а лимит запросов - ответом API
+	if err := github.CheckResponse(file); err != nil {
+		file.Body.Close()
+		return nil, &github.Response{Response: file, Rate: resp.Rate}, err
+	}
+	return file.Body, resp, nil
+}
@@ -0,0 +1,281 @@
+package notgogithub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// ReviewEvent - решение, с которым отправляется ревью
+type ReviewEvent string
+
+const (
+	ReviewApprove        ReviewEvent = "APPROVE"         // Одобрить изменения
+	ReviewRequestChanges ReviewEvent = "REQUEST_CHANGES" // Запросить исправления
+	ReviewComment        ReviewEvent = "COMMENT"         // Оставить комментарии без решения
+)
+
+// Состояния ревью в Review.State
+const (
+	ReviewStateApproved         = "APPROVED"
+	ReviewStateChangesRequested = "CHANGES_REQUESTED"
+	ReviewStateCommented        = "COMMENTED"
+	ReviewStateDismissed        = "DISMISSED"
+)
+
+// Review хранит информацию о ревью запроса на слияние
+type Review struct {
+	ID          int64     // Идентификатор ревью
+	Author      string    // Логин автора
+	Body        string    // Текст ревью
+	State       string    // Состояние: ReviewStateApproved, ReviewStateChangesRequested и т.д.
+	CommitSHA   string    // SHA коммита, к которому оставлено ревью
+	Link        string    // Ссылка на ревью
+	SubmittedAt time.Time // Дата отправки
+}
+
+// LineComment - новый комментарий к строке или диапазону строк кода
+type LineComment struct {
+	Path      string // Путь к файлу
+	Line      int    // Строка комментария, для диапазона - последняя
+	StartLine int    // Первая строка диапазона (0 - комментарий к одной строке Line)
+	Side      Side   // Сторона diff ("" - SideRight)
+	Body      string // Текст комментария
+}
+
+// Suggestion оформляет code как предложенное изменение: GitHub покажет его кнопкой, которая
+// заменяет строки комментария на code. Результат добавляется в LineComment.Body
+func Suggestion(code string) string {
+	return "```suggestion\n" + strings.TrimSuffix(code, "\n") + "\n```"
+}
+
+// side возвращает сторону diff комментария, по умолчанию SideRight
+func (c LineComment) side() string {
+	if c.Side == "" {
+		return string(SideRight)
+	}
+	return string(c.Side)
+}
+
+// draft преобразует комментарий в комментарий создаваемого ревью
+func (c LineComment) draft() *github.DraftReviewComment {
+	d := &github.DraftReviewComment{
+		Path: github.String(c.Path),
+		Body: github.String(c.Body),
+		Side: github.String(c.side()),
+		Line: github.Int(c.Line),
+	}
+	if c.StartLine != 0 && c.StartLine != c.Line {
+		d.StartLine = github.Int(c.StartLine)
+		d.StartSide = d.Side
+	}
+	return d
+}
+
+// newReview преобразует ревью GitHub в Review
+func newReview(r *github.PullRequestReview) *Review {
+	return &Review{
+		ID:          r.GetID(),
+		Author:      r.GetUser().GetLogin(),
+		Body:        r.GetBody(),
+		State:       r.GetState(),
+		CommitSHA:   r.GetCommitID(),
+		Link:        r.GetHTMLURL(),
+		SubmittedAt: r.GetSubmittedAt(),
+	}
+}
+
+func (ghs *gitHubService) GetReviews(ctx context.Context, owner, repositoryName string, pullRequestID int) ([]*Review, error) {
+	reviews, err := collectPages(ctx, 0, func(lo github.ListOptions) ([]*github.PullRequestReview, *github.Response, error) {
+		return callAPI(ctx, ghs, "list reviews", func() ([]*github.PullRequestReview, *github.Response, error) {
+			return ghs.client.PullRequests.ListReviews(ctx, owner, repositoryName, pullRequestID, &lo)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var Reviews []*Review
+	for _, r := range nonNil(reviews) {
+		Reviews = append(Reviews, newReview(r))
+	}
+	return Reviews, nil
+}
+
+func (ghs *gitHubService) CreateReview(ctx context.Context, owner, repositoryName string, pullRequestID int, event ReviewEvent, body string, comments ...LineComment) (*Review, error) {
+	req := &github.PullRequestReviewRequest{Event: github.String(string(event))}
+	if body != "" {
+		req.Body = github.String(body)
+	}
+	for _, c := range comments {
+		req.Comments = append(req.Comments, c.draft())
+	}
+
+	review, _, err := callAPI(ctx, ghs, "create review", func() (*github.PullRequestReview, *github.Response, error) {
+		return ghs.client.PullRequests.CreateReview(ctx, owner, repositoryName, pullRequestID, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newReview(review), nil
+}
+
+func (ghs *gitHubService) CreateLineComment(ctx context.Context, owner, repositoryName string, pullRequestID int, comment LineComment) (*Comment, error) {
+	// Отдельный комментарий GitHub принимает только с коммитом, к версии которого он относится
+	pr, _, err := callAPI(ctx, ghs, "get pull request", func() (*github.PullRequest, *github.Response, error) {
+		return ghs.client.PullRequests.Get(ctx, owner, repositoryName, pullRequestID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req := &github.PullRequestComment{
+		CommitID: github.String(pr.GetHead().GetSHA()),
+		Path:     github.String(comment.Path),
+		Body:     github.String(comment.Body),
+		Side:     github.String(comment.side()),
+		Line:     github.Int(comment.Line),
+	}
+	if comment.StartLine != 0 && comment.StartLine != comment.Line {
+		req.StartLine = github.Int(comment.StartLine)
+		req.StartSide = req.Side
+	}
+
+	c, _, err := callAPI(ctx, ghs, "create pull request comment", func() (*github.PullRequestComment, *github.Response, error) {
+		return ghs.client.PullRequests.CreateComment(ctx, owner, repositoryName, pullRequestID, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := newComment(c, false)
+	return &result, nil
+}
+
+func (ghs *gitHubService) ReplyToThread(ctx context.Context, owner, repositoryName string, pullRequestID int, threadID int64, body string) (*Comment, error) {
+	if threadID == 0 {
+		c, _, err := callAPI(ctx, ghs, "create issue comment", func() (*github.IssueComment, *github.Response, error) {
+			return ghs.client.Issues.CreateComment(ctx, owner, repositoryName, pullRequestID, &github.IssueComment{Body: &body})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result := newIssueComment(c)
+		return &result, nil
+	}
+
+	c, _, err := callAPI(ctx, ghs, "reply to review thread", func() (*github.PullRequestComment, *github.Response, error) {
+		return ghs.client.PullRequests.CreateCommentInReplyTo(ctx, owner, repositoryName, pullRequestID, body, threadID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := newComment(c, false)
+	return &result, nil
+}
+
+func (ghs *gitHubService) EditComment(ctx context.Context, owner, repositoryName string, pullRequestID int, commentID int64, body string) (*Comment, error) {
+	general, err := ghs.findComment(ctx, owner, repositoryName, pullRequestID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if general {
+		c, _, err := callAPI(ctx, ghs, "edit issue comment", func() (*github.IssueComment, *github.Response, error) {
+			return ghs.client.Issues.EditComment(ctx, owner, repositoryName, commentID, &github.IssueComment{Body: &body})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result := newIssueComment(c)
+		return &result, nil
+	}
+
+	c, _, err := callAPI(ctx, ghs, "edit pull request comment", func() (*github.PullRequestComment, *github.Response, error) {
+		return ghs.client.PullRequests.EditComment(ctx, owner, repositoryName, commentID, &github.PullRequestComment{Body: &body})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := newComment(c, false)
+	return &result, nil
+}
+
+func (ghs *gitHubService) DeleteComment(ctx context.Context, owner, repositoryName string, pullRequestID int, commentID int64) error {
+	general, err := ghs.findComment(ctx, owner, repositoryName, pullRequestID, commentID)
+	if err != nil {
+		return err
+	}
+
+	if general {
+		return ghs.do(ctx, "delete issue comment", func() (*github.Response, error) {
+			return ghs.client.Issues.DeleteComment(ctx, owner, repositoryName, commentID)
+		})
+	}
+	return ghs.do(ctx, "delete pull request comment", func() (*github.Response, error) {
+		return ghs.client.PullRequests.DeleteComment(ctx, owner, repositoryName, commentID)
+	})
+}
+
+// findComment проверяет, что комментарий commentID оставлен в запросе на слияние pullRequestID:
+// адреса комментариев в GitHub API не содержат номера запроса. Возвращает true для комментария
+// общего обсуждения и false для комментария к коду
+func (ghs *gitHubService) findComment(ctx context.Context, owner, repositoryName string, pullRequestID int, commentID int64) (bool, error) {
+	c, _, err := callAPI(ctx, ghs, "get pull request comment", func() (*github.PullRequestComment, *github.Response, error) {
+		return ghs.client.PullRequests.GetComment(ctx, owner, repositoryName, commentID)
+	})
+	if err == nil {
+		err = commentOf(c.GetPullRequestURL(), fmt.Sprintf("/pulls/%d", pullRequestID), commentID, fmt.Sprintf("pull request %d", pullRequestID))
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return false, err
+	}
+
+	// Комментария к коду с таким идентификатором в запросе нет, ищется комментарий общего обсуждения:
+	// идентификаторы комментариев к коду и комментариев обсуждения могут совпадать
+	ic, _, icErr := callAPI(ctx, ghs, "get issue comment", func() (*github.IssueComment, *github.Response, error) {
+		return ghs.client.Issues.GetComment(ctx, owner, repositoryName, commentID)
+	})
+	if icErr == nil {
+		icErr = commentOf(ic.GetIssueURL(), fmt.Sprintf("/issues/%d", pullRequestID), commentID, fmt.Sprintf("pull request %d", pullRequestID))
+	}
+	if errors.Is(icErr, ErrNotFound) {
+		// Ошибка поиска комментария к коду точнее: он мог найтись в другом запросе
+		return false, err
+	}
+	return icErr == nil, icErr
+}
+
+// commentOf возвращает ошибку ErrNotFound, если адрес u, к которому относится комментарий,
+// не оканчивается на suffix. where называет ожидаемое место комментария в тексте ошибки
+func commentOf(u, suffix string, commentID int64, where string) error {
+	if strings.HasSuffix(u, suffix) {
+		return nil
+	}
+	return &Error{
+		Op:   "find comment",
+		Err:  fmt.Errorf("comment %d is not in %s", commentID, where),
+		kind: ErrNotFound,
+	}
+}
+
+func (ghs *gitHubService) DismissReview(ctx context.Context, owner, repositoryName string, pullRequestID int, reviewID int64, message string) (*Review, error) {
+	req := &github.PullRequestReviewDismissalRequest{Message: &message}
+	review, _, err := callAPI(ctx, ghs, "dismiss review", func() (*github.PullRequestReview, *github.Response, error) {
+		return ghs.client.PullRequests.DismissReview(ctx, owner, repositoryName, pullRequestID, reviewID, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newReview(review), nil
+}
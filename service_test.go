@@ -0,0 +1,1415 @@
+package notgogithub
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+
+	"github.com/jellybebra/not-go-github/ghsim"
+)
+
+// newSimService запускает симулятор GitHub API с данными из testdata/fixtures.json
+// и возвращает направленный на него сервис
+func newSimService(t *testing.T, opts ...Option) (GitServiceIFace, *ghsim.Server) {
+	t.Helper()
+
+	f, err := ghsim.LoadFixtures("testdata/fixtures.json")
+	if err != nil {
+		t.Fatalf("load fixtures: %v", err)
+	}
+	return newSimServiceFrom(t, f, opts...)
+}
+
+func newSimServiceFrom(t *testing.T, f *ghsim.Fixtures, opts ...Option) (GitServiceIFace, *ghsim.Server) {
+	t.Helper()
+
+	sim := ghsim.New(f)
+	t.Cleanup(sim.Close)
+
+	opts = append([]Option{WithAuth(StaticToken("test")), WithEnterpriseURLs(sim.URL, "")}, opts...)
+	ghs, err := NewGitHubService(context.Background(), opts...)
+	if err != nil {
+		t.Fatalf("NewGitHubService: %v", err)
+	}
+	return ghs, sim
+}
+
+func TestServiceGetRepositoryByName(t *testing.T) {
+	ghs, _ := newSimService(t)
+
+	// Act
+	repo, err := ghs.GetRepositoryByName(context.Background(), "jostanise", "rsa_encrypted_local_chat")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("GetRepositoryByName: %v", err)
+	}
+	if repo.Link != "https://github.com/jostanise/rsa_encrypted_local_chat" {
+		t.Errorf("Incorrect Link: %v", repo.Link)
+	}
+	if !repo.LastUpdatedTime.Equal(stringToTime("2021-10-12 15:20:12 +0000 UTC")) {
+		t.Errorf("Incorrect LastUpdatedTime: %v", repo.LastUpdatedTime)
+	}
+	languages := repo.Languages
+	if len(languages) != 2 || languages[0].Name != "Python" || languages[0].Bytes != 8144 || languages[0].Percentage != 98.96706768744683 {
+		t.Errorf("Incorrect languages: %v", languages)
+	}
+}
+
+func TestServiceGetRepositoryBranches(t *testing.T) {
+	ghs, sim := newSimService(t)
+
+	// Arrange
+	expected := []Branch{
+		{Name: "create-sec-hero", UpdatedAt: stringToTime("2022-03-02 20:11:13 +0000 UTC")},
+		{Name: "main", UpdatedAt: stringToTime("2022-03-05 18:17:54 +0000 UTC")},
+		{Name: "patch-1", UpdatedAt: stringToTime("2022-03-05 22:52:20 +0000 UTC")},
+		{Name: "yura", UpdatedAt: stringToTime("2022-03-02 14:59:38 +0000 UTC")},
+	}
+
+	// Act
+	branches, err := ghs.GetRepositoryBranches(context.Background(), "PeakIntegral", "cppLessons")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("GetRepositoryBranches: %v", err)
+	}
+	if len(branches) != len(expected) {
+		t.Fatalf("Incorrect amount of branches: expected %v, got %v", len(expected), len(branches))
+	}
+	for i, exp := range expected {
+		if branches[i].Name != exp.Name || !branches[i].UpdatedAt.Equal(exp.UpdatedAt) {
+			t.Errorf("Incorrect branch: expected %v, got %v", exp, *branches[i])
+		}
+	}
+
+	// Дата берется из коммита git, SHA которого извлечен из ответа со списком веток
+	var gitCommits int
+	for _, r := range sim.Requests() {
+		if strings.HasPrefix(r, "GET /repos/PeakIntegral/cppLessons/git/commits/") {
+			gitCommits++
+		}
+	}
+	if gitCommits != len(expected) {
+		t.Errorf("Incorrect amount of commit requests: expected %v, got %v", len(expected), gitCommits)
+	}
+}
+
+func TestServiceConcurrency(t *testing.T) {
+	ctx := context.Background()
+	sequential, _ := newSimService(t, WithConcurrency(1))
+	concurrent, sim := newSimService(t, WithConcurrency(8))
+
+	// Arrange
+	expected, err := sequential.GetUserRepositories(ctx, "jostanise")
+	if err != nil {
+		t.Fatalf("GetUserRepositories: %v", err)
+	}
+
+	// Act
+	repos, err := concurrent.GetUserRepositories(ctx, "jostanise")
+
+	// Assert: порядок совпадает с последовательной загрузкой
+	if err != nil {
+		t.Fatalf("GetUserRepositories: %v", err)
+	}
+	if len(repos) != len(expected) {
+		t.Fatalf("Incorrect amount of repositories: expected %v, got %v", len(expected), len(repos))
+	}
+	for i, exp := range expected {
+		if repos[i].Name != exp.Name || len(repos[i].Languages) != len(exp.Languages) {
+			t.Errorf("Incorrect repository %v: expected %v, got %v", i, exp.Name, repos[i].Name)
+		}
+	}
+
+	// Ошибка одного из дополнительных запросов прерывает весь вызов
+	sim.InjectError("GET", "/repos/jostanise/jostanise/languages", http.StatusInternalServerError, "boom")
+	var ghErr *github.ErrorResponse
+	if _, err := concurrent.GetUserRepositories(ctx, "jostanise"); !errors.As(err, &ghErr) || ghErr.Response.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %v", err)
+	}
+}
+
+func TestServiceEnrichment(t *testing.T) {
+	ctx := context.Background()
+
+	testTable := []struct {
+		name     string
+		call     func(ghs GitServiceIFace, opts ...ListOption) ([]Enrichment, error)
+		flag     Enrichment
+		requests string // Префикс дополнительных запросов
+	}{
+		{
+			name: "repositories",
+			call: func(ghs GitServiceIFace, opts ...ListOption) ([]Enrichment, error) {
+				repos, err := ghs.GetUserRepositories(ctx, "jostanise", opts...)
+				var Enriched []Enrichment
+				for _, r := range repos {
+					Enriched = append(Enriched, r.Enriched)
+				}
+				return Enriched, err
+			},
+			flag:     EnrichLanguages,
+			requests: "GET /repos/jostanise/",
+		},
+		{
+			name: "repository",
+			call: func(ghs GitServiceIFace, opts ...ListOption) ([]Enrichment, error) {
+				repo, err := ghs.GetRepositoryByName(ctx, "jostanise", "rsa_encrypted_local_chat", opts...)
+				if err != nil {
+					return nil, err
+				}
+				return []Enrichment{repo.Enriched}, nil
+			},
+			flag:     EnrichLanguages,
+			requests: "GET /repos/jostanise/rsa_encrypted_local_chat/languages",
+		},
+		{
+			name: "branches",
+			call: func(ghs GitServiceIFace, opts ...ListOption) ([]Enrichment, error) {
+				branches, err := ghs.GetRepositoryBranches(ctx, "PeakIntegral", "cppLessons", opts...)
+				var Enriched []Enrichment
+				for _, b := range branches {
+					Enriched = append(Enriched, b.Enriched)
+				}
+				return Enriched, err
+			},
+			flag:     EnrichCommitDates,
+			requests: "GET /repos/PeakIntegral/cppLessons/git/commits/",
+		},
+		{
+			name: "contributors",
+			call: func(ghs GitServiceIFace, opts ...ListOption) ([]Enrichment, error) {
+				users, err := ghs.GetRepositoryContributors(ctx, "jostanise", "rsa_encrypted_local_chat", opts...)
+				var Enriched []Enrichment
+				for _, u := range users {
+					if u.UserName == "" {
+						t.Errorf("contributor without login")
+					}
+					Enriched = append(Enriched, u.Enriched)
+				}
+				return Enriched, err
+			},
+			flag:     EnrichProfiles,
+			requests: "GET /user/",
+		},
+		{
+			name: "tags",
+			call: func(ghs GitServiceIFace, opts ...ListOption) ([]Enrichment, error) {
+				tags, err := ghs.GetRepositoryTags(ctx, "jostanise", "rsa_encrypted_local_chat", opts...)
+				var Enriched []Enrichment
+				for _, tag := range tags {
+					Enriched = append(Enriched, tag.Enriched)
+				}
+				return Enriched, err
+			},
+			flag:     EnrichReleaseNotes,
+			requests: "GET /repos/jostanise/rsa_encrypted_local_chat/releases/tags/",
+		},
+		{
+			name: "tag annotations",
+			call: func(ghs GitServiceIFace, opts ...ListOption) ([]Enrichment, error) {
+				tags, err := ghs.GetRepositoryTags(ctx, "PeakIntegral", "cppLessons", opts...)
+				var Enriched []Enrichment
+				for _, tag := range tags {
+					Enriched = append(Enriched, tag.Enriched)
+				}
+				return Enriched, err
+			},
+			flag:     EnrichAnnotations,
+			requests: "GET /repos/PeakIntegral/cppLessons/git/",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			for _, enrich := range []Enrichment{EnrichAll, EnrichNone, EnrichAll &^ testCase.flag} {
+				ghs, sim := newSimService(t)
+
+				// Act
+				enriched, err := testCase.call(ghs, WithEnrichment(enrich))
+
+				// Assert
+				if err != nil {
+					t.Fatalf("call with %b: %v", enrich, err)
+				}
+				if len(enriched) == 0 {
+					t.Fatalf("no items returned")
+				}
+				expected := enrich & testCase.flag
+				for _, e := range enriched {
+					if e&testCase.flag != expected {
+						t.Errorf("Incorrect Enriched with %b: expected %b, got %b", enrich, expected, e)
+					}
+				}
+
+				var extra int
+				for _, r := range sim.Requests() {
+					if strings.HasPrefix(r, testCase.requests) {
+						extra++
+					}
+				}
+				if expected == 0 && extra != 0 {
+					t.Errorf("expected no enrichment requests with %b, got %v", enrich, extra)
+				}
+				if expected != 0 && extra == 0 {
+					t.Errorf("expected enrichment requests with %b", enrich)
+				}
+			}
+		})
+	}
+}
+
+func TestServiceGetRepositoryTags(t *testing.T) {
+	ghs, _ := newSimService(t)
+
+	// Act
+	tags, err := ghs.GetRepositoryTags(context.Background(), "PeakIntegral", "cppLessons")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("GetRepositoryTags: %v", err)
+	}
+	if len(tags) != 2 {
+		t.Fatalf("Incorrect amount of tags: expected 2, got %v", len(tags))
+	}
+	annotated, lightweight := tags[0], tags[1]
+	if !annotated.Annotated || annotated.Tagger != "PeakIntegral" || annotated.Message != "Первая версия уроков\n" {
+		t.Errorf("Incorrect annotated tag: %+v", annotated)
+	}
+	if !annotated.TaggedAt.Equal(stringToTime("2022-03-12 10:00:00 +0000 UTC")) {
+		t.Errorf("Incorrect TaggedAt: %v", annotated.TaggedAt)
+	}
+	if annotated.Hash != "d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0" {
+		t.Errorf("Incorrect Hash: expected the commit, got %v", annotated.Hash)
+	}
+	if lightweight.Annotated || lightweight.Message != "" || !lightweight.TaggedAt.IsZero() {
+		t.Errorf("Incorrect lightweight tag: %+v", lightweight)
+	}
+	for _, tag := range tags {
+		if tag.Release != nil {
+			t.Errorf("expected tag %v without release, got %+v", tag.Title, tag.Release)
+		}
+		if tag.Enriched != EnrichReleaseNotes|EnrichAnnotations {
+			t.Errorf("Incorrect Enriched: %b", tag.Enriched)
+		}
+	}
+}
+
+func TestServiceIterateRepositoryTags(t *testing.T) {
+	f, err := ghsim.LoadFixtures("testdata/fixtures.json")
+	if err != nil {
+		t.Fatalf("load fixtures: %v", err)
+	}
+	for i := range f.Repositories {
+		if r := &f.Repositories[i]; r.Name == "cppLessons" {
+			for n := 0; n < 150; n++ {
+				r.Tags = append(r.Tags, ghsim.Tag{Name: fmt.Sprintf("build-%d", n), SHA: "d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0"})
+			}
+		}
+	}
+	ghs, sim := newSimServiceFrom(t, f)
+
+	// Act
+	it := ghs.IterateRepositoryTags(context.Background(), "PeakIntegral", "cppLessons", WithEnrichment(EnrichAnnotations))
+	count, annotated := 0, 0
+	for it.Next() {
+		count++
+		if it.Value().Annotated {
+			annotated++
+		}
+	}
+
+	// Assert
+	if err := it.Err(); err != nil {
+		t.Fatalf("IterateRepositoryTags: %v", err)
+	}
+	if count != 152 || annotated != 1 {
+		t.Errorf("Incorrect tags: expected 152 with 1 annotated, got %v with %v annotated", count, annotated)
+	}
+	// Ссылки тегов (две страницы) перечисляются один раз на итератор, а не на каждой странице тегов
+	refRequests := 0
+	for _, r := range sim.Requests() {
+		if strings.HasPrefix(r, "GET /repos/PeakIntegral/cppLessons/git/matching-refs/tags") {
+			refRequests++
+		}
+	}
+	if refRequests != 2 {
+		t.Errorf("Incorrect amount of tag ref requests: expected 2, got %v", refRequests)
+	}
+}
+
+func TestServiceReleases(t *testing.T) {
+	ghs, _ := newSimService(t)
+	ctx := context.Background()
+
+	// Act
+	releases, err := ghs.GetRepositoryReleases(ctx, "jostanise", "rsa_encrypted_local_chat")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("GetRepositoryReleases: %v", err)
+	}
+	if len(releases) != 1 {
+		t.Fatalf("Incorrect amount of releases: expected 1, got %v", len(releases))
+	}
+	r := releases[0]
+	if r.TagName != "v1.0" || r.Author != "jostanise" || r.Draft {
+		t.Errorf("Incorrect release: %+v", r)
+	}
+	if r.Link != "https://github.com/jostanise/rsa_encrypted_local_chat/releases/tag/v1.0" {
+		t.Errorf("Incorrect Link: %v", r.Link)
+	}
+	if !r.PublishedAt.Equal(r.CreatedAt) {
+		t.Errorf("Incorrect PublishedAt: %v", r.PublishedAt)
+	}
+
+	byTag, err := ghs.GetReleaseByTag(ctx, "jostanise", "rsa_encrypted_local_chat", "v1.0")
+	if err != nil || byTag.ID != r.ID {
+		t.Errorf("GetReleaseByTag: %+v, %v", byTag, err)
+	}
+	if _, err := ghs.GetReleaseByTag(ctx, "PeakIntegral", "cppLessons", "v0.1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a tag without release, got %v", err)
+	}
+
+	tags, err := ghs.GetRepositoryTags(ctx, "jostanise", "rsa_encrypted_local_chat")
+	if err != nil || len(tags) != 1 || tags[0].Release == nil || tags[0].Release.ID != r.ID {
+		t.Errorf("expected tag with release %v, got %v, %v", r.ID, tags, err)
+	}
+}
+
+func TestServiceReleaseLifecycle(t *testing.T) {
+	ghs, _ := newSimService(t)
+	ctx := context.Background()
+	const owner, repo = "PeakIntegral", "cppLessons"
+
+	// Arrange
+	draft, err := ghs.CreateRelease(ctx, owner, repo, "v0.2", WithReleaseName("Уроки 0.2"), WithReleaseDraft(true))
+	if err != nil {
+		t.Fatalf("CreateRelease: %v", err)
+	}
+	if !draft.Draft || !draft.PublishedAt.IsZero() {
+		t.Errorf("Incorrect draft: %+v", draft)
+	}
+	if _, err := ghs.GetReleaseByTag(ctx, owner, repo, "v0.2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a draft, got %v", err)
+	}
+	if _, err := ghs.CreateRelease(ctx, owner, repo, "v0.2"); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrConflict for a second release of the tag, got %v", err)
+	}
+
+	edited, err := ghs.EditRelease(ctx, owner, repo, draft.ID, WithReleaseNotes("Добавлены уроки про указатели"))
+	if err != nil {
+		t.Fatalf("EditRelease: %v", err)
+	}
+	if edited.Name != "Уроки 0.2" || edited.Body != "Добавлены уроки про указатели" || !edited.Draft {
+		t.Errorf("Incorrect edited release: %+v", edited)
+	}
+
+	content := []byte("int main() { return 0; }\n")
+	asset, err := ghs.UploadReleaseAsset(ctx, owner, repo, draft.ID, "main.cpp", "text/x-c", bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("UploadReleaseAsset: %v", err)
+	}
+	if asset.Name != "main.cpp" || asset.Size != len(content) || asset.ContentType != "text/x-c" {
+		t.Errorf("Incorrect asset: %+v", asset)
+	}
+
+	// Act
+	published, err := ghs.PublishRelease(ctx, owner, repo, draft.ID)
+	if err != nil {
+		t.Fatalf("PublishRelease: %v", err)
+	}
+	latest, err := ghs.GetLatestRelease(ctx, owner, repo)
+	if err != nil {
+		t.Fatalf("GetLatestRelease: %v", err)
+	}
+	rc, err := ghs.DownloadReleaseAsset(ctx, owner, repo, draft.ID, "main.cpp")
+	if err != nil {
+		t.Fatalf("DownloadReleaseAsset: %v", err)
+	}
+	downloaded, err := io.ReadAll(rc)
+	rc.Close()
+
+	// Assert
+	if published.Draft || published.PublishedAt.IsZero() || len(published.Assets) != 1 {
+		t.Errorf("Incorrect published release: %+v", published)
+	}
+	if latest.ID != draft.ID {
+		t.Errorf("Incorrect latest release: expected %v, got %v", draft.ID, latest.ID)
+	}
+	if err != nil || !bytes.Equal(downloaded, content) {
+		t.Errorf("Incorrect downloaded content: %q, %v", downloaded, err)
+	}
+	if _, err := ghs.DownloadReleaseAsset(ctx, owner, repo, draft.ID, "missing.zip"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing asset, got %v", err)
+	}
+	// Публикация создает тег релиза на ветке по умолчанию
+	tags, err := ghs.GetRepositoryTags(ctx, owner, repo)
+	if err != nil {
+		t.Fatalf("GetRepositoryTags: %v", err)
+	}
+	var tag *Tag
+	for _, tg := range tags {
+		if tg.Title == "v0.2" {
+			tag = tg
+		}
+	}
+	if tag == nil || tag.Release == nil || tag.Release.ID != draft.ID {
+		t.Errorf("expected tag v0.2 with the published release, got %+v", tag)
+	}
+
+	if err := ghs.DeleteRelease(ctx, owner, repo, draft.ID); err != nil {
+		t.Fatalf("DeleteRelease: %v", err)
+	}
+	if _, err := ghs.GetRelease(ctx, owner, repo, draft.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after DeleteRelease, got %v", err)
+	}
+}
+
+func TestServiceDownloadReleaseAssetResponse(t *testing.T) {
+	ghs, sim := newSimService(t)
+	ctx := context.Background()
+	const owner, repo, releaseID = "jostanise", "rsa_encrypted_local_chat", 51384120
+
+	// Arrange
+	asset, err := ghs.UploadReleaseAsset(ctx, owner, repo, releaseID, "chat.zip", "application/zip", bytes.NewReader([]byte("zip")))
+	if err != nil {
+		t.Fatalf("UploadReleaseAsset: %v", err)
+	}
+	sim.SetRateLimit(5000, 100, time.Now().Add(time.Hour))
+	assetPath := fmt.Sprintf("/repos/%s/%s/releases/assets/%d", owner, repo, asset.ID)
+
+	// Act
+	rc, err := ghs.DownloadReleaseAsset(ctx, owner, repo, releaseID, "chat.zip")
+	if err != nil {
+		t.Fatalf("DownloadReleaseAsset: %v", err)
+	}
+	rc.Close()
+	rate := ghs.(*gitHubService).lastRate()
+	sim.InjectError("GET", assetPath, http.StatusForbidden, "Resource not accessible by integration")
+	_, forbiddenErr := ghs.DownloadReleaseAsset(ctx, owner, repo, releaseID, "chat.zip")
+
+	// Assert
+	// Лимит запросов берется из ответа API, а не хранилища файлов
+	if rate.Limit != 5000 || rate.Remaining != 98 {
+		t.Errorf("Incorrect rate after download: %+v", *rate)
+	}
+	var apiErr *Error
+	if !errors.As(forbiddenErr, &apiErr) || !errors.Is(forbiddenErr, ErrForbidden) ||
+		apiErr.StatusCode != http.StatusForbidden || apiErr.RequestID == "" {
+		t.Errorf("Incorrect download error: %+v", forbiddenErr)
+	}
+}
+
+func TestServiceGetBranchCommits(t *testing.T) {
+	ghs, sim := newSimService(t)
+
+	// Arrange
+	const (
+		initial = "e3b1a0c9d8f7e6a5b4c3d2e1f0a9b8c7d6e5f4a3"
+		first   = "b7f4c2e9a1d3f5b7c9e1a3d5f7b9c1e3a5d7f9b1"
+		second  = "c2d4e6f8a0b2c4d6e8f0a2b4c6d8e0f2a4b6c8d0"
+		merge   = "d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0"
+		readme  = "f1e2d3c4b5a69788796a5b4c3d2e1f0a9b8c7d6e"
+	)
+	testTable := []struct {
+		name     string
+		opts     []ListOption
+		expected []string
+	}{
+		{name: "all", expected: []string{readme, merge, second, first, initial}},
+		{name: "since", opts: []ListOption{WithSince(stringToTime("2022-03-02 15:00:00 +0000 UTC"))}, expected: []string{readme, merge, second}},
+		{name: "until", opts: []ListOption{WithUntil(stringToTime("2022-03-02 15:00:00 +0000 UTC"))}, expected: []string{first, initial}},
+		{name: "path", opts: []ListOption{WithPath("heroes")}, expected: []string{second, first}},
+		{name: "author login", opts: []ListOption{WithAuthor("jostanise")}, expected: []string{readme}},
+		{name: "author email", opts: []ListOption{WithAuthor("yura@example.com")}, expected: []string{first}},
+		{name: "max depth", opts: []ListOption{WithMaxDepth(2)}, expected: []string{readme, merge}},
+	}
+
+	for _, testCase := range testTable {
+		sim.ResetRequests()
+
+		// Act
+		commits, err := ghs.GetBranchCommits(context.Background(), "PeakIntegral", "cppLessons", "patch-1", testCase.opts...)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("GetBranchCommits(%s): %v", testCase.name, err)
+		}
+		var hashes []string
+		for _, c := range commits {
+			hashes = append(hashes, c.Hash)
+		}
+		if fmt.Sprint(hashes) != fmt.Sprint(testCase.expected) {
+			t.Errorf("Incorrect commits for %s: expected %v, got %v", testCase.name, testCase.expected, hashes)
+		}
+
+		// История загружается списком коммитов, без запроса на каждого предка
+		for _, r := range sim.Requests() {
+			if r != "GET /repos/PeakIntegral/cppLessons/commits" {
+				t.Errorf("Unexpected request for %s: %v", testCase.name, r)
+			}
+		}
+	}
+
+	commits, _ := ghs.GetBranchCommits(context.Background(), "PeakIntegral", "cppLessons", "main")
+	if c := commits[0]; c.Title != "Merge heroes" || c.Author != "PeakIntegral" || fmt.Sprint(c.Parents) != fmt.Sprint([]string{first, second}) ||
+		!c.CreatedAt.Equal(stringToTime("2022-03-05 18:17:54 +0000 UTC")) {
+		t.Errorf("Incorrect merge commit: %+v", *c)
+	}
+	if c := commits[2]; c.Hash != first || c.Author != "yura" {
+		t.Errorf("Incorrect commit without account: %+v", *c)
+	}
+}
+
+func TestServiceGetThreadsInfo(t *testing.T) {
+	ghs, _ := newSimService(t)
+
+	// Act
+	threads, err := ghs.GetThreadsInfo(context.Background(), "PeakIntegral", "cppLessons", 3)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("GetThreadsInfo: %v", err)
+	}
+	expected := []Thread{
+		{ID: 0, Comments: []string{"Готово к ревью"}},
+		{ID: 820011001, Filename: "README.md", LineOfCode: 3, Comments: []string{"Опечатка в заголовке", "Исправил"}},
+		{ID: 820011002, Filename: "main.cpp", LineOfCode: 12, Resolved: true, Comments: []string{"Лишний include"}},
+		{ID: 820011003, Filename: "README.md", LineOfCode: 1, Outdated: true, Comments: []string{"Заголовок слишком длинный"}},
+	}
+	if len(threads) != len(expected) {
+		t.Fatalf("Incorrect amount of threads: expected %v, got %v", len(expected), len(threads))
+	}
+	for i, exp := range expected {
+		res := threads[i]
+		if res.ID != exp.ID || res.Filename != exp.Filename || res.LineOfCode != exp.LineOfCode ||
+			res.Resolved != exp.Resolved || res.Outdated != exp.Outdated || fmt.Sprint(res.Comments) != fmt.Sprint(exp.Comments) {
+			t.Errorf("Incorrect thread: expected %v, got %v", exp, *res)
+		}
+		if len(res.Conversation) != len(exp.Comments) {
+			t.Errorf("Incorrect conversation of thread %v: %+v", exp.ID, res.Conversation)
+		}
+	}
+
+	// Ответ из ревью другого участника попадает в обсуждение первого комментария
+	reply := threads[1].Conversation[1]
+	if reply.Author != "jostanise" || reply.InReplyTo != 820011001 || reply.ReviewID != 903381230 || !reply.UpdatedAt.After(reply.CreatedAt) {
+		t.Errorf("Incorrect reply: %+v", reply)
+	}
+	if c := threads[2].Conversation[0]; c.StartLine != 10 || c.EndLine != 12 || c.Side != SideRight || c.CommitSHA == "" || !c.Resolved {
+		t.Errorf("Incorrect multi-line comment: %+v", c)
+	}
+	if c := threads[3].Conversation[0]; c.StartLine != 1 || c.EndLine != 1 || !c.Outdated {
+		t.Errorf("Incorrect outdated comment: %+v", c)
+	}
+	if c := threads[0].Conversation[0]; c.Author != "jostanise" || c.ReviewID != 0 || c.Link == "" {
+		t.Errorf("Incorrect conversation comment: %+v", c)
+	}
+}
+
+func TestServiceGetThreadsInfoUnauthenticated(t *testing.T) {
+	ghs, _ := newSimService(t, WithAuth(Unauthenticated()))
+
+	// Act
+	threads, err := ghs.GetThreadsInfo(context.Background(), "PeakIntegral", "cppLessons", 3)
+
+	// Assert
+	// Без токена GraphQL API недоступен: обсуждения приходят без признака решенности
+	if err != nil {
+		t.Fatalf("GetThreadsInfo: %v", err)
+	}
+	if len(threads) != 4 {
+		t.Fatalf("Incorrect amount of threads: expected 4, got %v", len(threads))
+	}
+	for _, thread := range threads {
+		if thread.Resolved || thread.Conversation[0].Resolved {
+			t.Errorf("Incorrect resolution of thread %v without GraphQL API", thread.ID)
+		}
+	}
+	if threads[2].ID != 820011002 || !threads[3].Outdated {
+		t.Errorf("Incorrect threads: %v, %v", *threads[2], *threads[3])
+	}
+}
+
+func TestServiceGetThreadsInfoFileComment(t *testing.T) {
+	f, err := ghsim.LoadFixtures("testdata/fixtures.json")
+	if err != nil {
+		t.Fatalf("load fixtures: %v", err)
+	}
+	for i := range f.Repositories {
+		if r := &f.Repositories[i]; r.Name == "cppLessons" {
+			r.Reviews[1].Comments = append(r.Reviews[1].Comments, ghsim.ReviewComment{
+				ID: 820011005, User: "jostanise", Path: "main.cpp", Body: "Разбить файл на модули",
+				CreatedAt: stringToTime("2022-03-06 12:00:00 +0000 UTC"),
+			})
+		}
+	}
+	ghs, _ := newSimServiceFrom(t, f)
+
+	// Act
+	threads, err := ghs.GetThreadsInfo(context.Background(), "PeakIntegral", "cppLessons", 3)
+
+	// Assert
+	// У комментария ко всему файлу нет номеров строк, но он не устаревший
+	if err != nil {
+		t.Fatalf("GetThreadsInfo: %v", err)
+	}
+	thread := threads[len(threads)-1]
+	if thread.ID != 820011005 || thread.Filename != "main.cpp" || thread.LineOfCode != 0 || thread.Outdated {
+		t.Errorf("Incorrect file thread: %v", *thread)
+	}
+	if c := thread.Conversation[0]; c.StartLine != 0 || c.EndLine != 0 || c.Outdated {
+		t.Errorf("Incorrect file comment: %+v", c)
+	}
+	if outdated := threads[len(threads)-2]; outdated.ID != 820011003 || !outdated.Outdated {
+		t.Errorf("Incorrect outdated thread: %v", *outdated)
+	}
+}
+
+func TestServiceGraphQLErrors(t *testing.T) {
+	ghs, _ := newSimService(t)
+	ctx := context.Background()
+
+	// Act
+	_, closedErr := ghs.ConvertPullRequestToDraft(ctx, "PeakIntegral", "cppLessons", 1)
+	_, missingErr := graphQL[reviewThreadsData](ctx, ghs.(*gitHubService), "list review threads", reviewThreadsQuery, map[string]interface{}{
+		"owner": "PeakIntegral", "repo": "missing", "number": 1, "cursor": nil,
+	})
+
+	// Assert
+	// GraphQL API отвечает на ошибки статусом 200, вид ошибки берется из поля type
+	if !errors.Is(closedErr, ErrValidation) {
+		t.Errorf("expected ErrValidation for closed pull request, got %v", closedErr)
+	}
+	if !errors.Is(missingErr, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for missing repository, got %v", missingErr)
+	}
+}
+
+func TestServiceReviewLifecycle(t *testing.T) {
+	ghs, _ := newSimService(t)
+	ctx := context.Background()
+	const owner, repo = "PeakIntegral", "cppLessons"
+
+	// Arrange
+	if _, err := ghs.CreateReview(ctx, owner, repo, 3, ReviewApprove, ""); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected ErrValidation for approving own pull request, got %v", err)
+	}
+	review, err := ghs.CreateReview(ctx, owner, repo, 5, ReviewRequestChanges, "Нужны правки",
+		LineComment{Path: "main.cpp", StartLine: 2, Line: 4, Body: "Упростить:\n" + Suggestion("return 0;\n")},
+		LineComment{Path: "README.md", Line: 1, Side: SideLeft, Body: "Верните заголовок"},
+	)
+	if err != nil {
+		t.Fatalf("CreateReview: %v", err)
+	}
+	if review.State != ReviewStateChangesRequested || review.Author != "jostanise" || review.CommitSHA == "" || review.SubmittedAt.IsZero() {
+		t.Errorf("Incorrect review: %+v", review)
+	}
+
+	comment, err := ghs.CreateLineComment(ctx, owner, repo, 5, LineComment{Path: "main.cpp", Line: 7, Body: "Нет проверки"})
+	if err != nil {
+		t.Fatalf("CreateLineComment: %v", err)
+	}
+	if comment.EndLine != 7 || comment.StartLine != 7 || comment.Side != SideRight || comment.CommitSHA == "" || comment.ReviewID == 0 {
+		t.Errorf("Incorrect line comment: %+v", comment)
+	}
+
+	// Act
+	reply, err := ghs.ReplyToThread(ctx, owner, repo, 5, comment.ID, "Добавлю")
+	if err != nil {
+		t.Fatalf("ReplyToThread: %v", err)
+	}
+	general, err := ghs.ReplyToThread(ctx, owner, repo, 5, 0, "Спасибо за ревью")
+	if err != nil {
+		t.Fatalf("ReplyToThread to the conversation: %v", err)
+	}
+	edited, err := ghs.EditComment(ctx, owner, repo, 5, reply.ID, "Добавил")
+	if err != nil {
+		t.Fatalf("EditComment: %v", err)
+	}
+	if err := ghs.DeleteComment(ctx, owner, repo, 5, general.ID); err != nil {
+		t.Fatalf("DeleteComment: %v", err)
+	}
+	dismissed, err := ghs.DismissReview(ctx, owner, repo, 5, review.ID, "Исправлено")
+	if err != nil {
+		t.Fatalf("DismissReview: %v", err)
+	}
+	threads, err := ghs.GetThreadsInfo(ctx, owner, repo, 5)
+	if err != nil {
+		t.Fatalf("GetThreadsInfo: %v", err)
+	}
+	reviews, err := ghs.GetReviews(ctx, owner, repo, 5)
+	if err != nil {
+		t.Fatalf("GetReviews: %v", err)
+	}
+
+	// Assert
+	if reply.InReplyTo != comment.ID || reply.EndLine != 7 {
+		t.Errorf("Incorrect reply: %+v", reply)
+	}
+	if general.ReviewID != 0 || general.Body != "Спасибо за ревью" {
+		t.Errorf("Incorrect conversation comment: %+v", general)
+	}
+	if edited.ID != reply.ID || edited.Body != "Добавил" {
+		t.Errorf("Incorrect edited comment: %+v", edited)
+	}
+	if dismissed.State != ReviewStateDismissed {
+		t.Errorf("Incorrect dismissed review state: %v", dismissed.State)
+	}
+	expected := []Thread{
+		{Filename: "main.cpp", LineOfCode: 4, Comments: []string{"Упростить:\n```suggestion\nreturn 0;\n```"}},
+		{Filename: "README.md", LineOfCode: 1, Comments: []string{"Верните заголовок"}},
+		{ID: comment.ID, Filename: "main.cpp", LineOfCode: 7, Comments: []string{"Нет проверки", "Добавил"}},
+	}
+	if len(threads) != len(expected) {
+		t.Fatalf("Incorrect amount of threads: expected %v, got %v", len(expected), len(threads))
+	}
+	for i, exp := range expected {
+		res := threads[i]
+		if (exp.ID != 0 && res.ID != exp.ID) || res.Filename != exp.Filename || res.LineOfCode != exp.LineOfCode ||
+			fmt.Sprint(res.Comments) != fmt.Sprint(exp.Comments) {
+			t.Errorf("Incorrect thread: expected %v, got %v", exp, *res)
+		}
+	}
+	if c := threads[0].Conversation[0]; c.StartLine != 2 || c.ReviewID != review.ID {
+		t.Errorf("Incorrect multi-line comment: %+v", c)
+	}
+	if c := threads[1].Conversation[0]; c.Side != SideLeft {
+		t.Errorf("Incorrect side of comment: %+v", c)
+	}
+	if len(reviews) != 3 || reviews[0].State != ReviewStateDismissed {
+		t.Errorf("Incorrect reviews: %+v", reviews)
+	}
+
+	// Ошибки
+	if _, err := ghs.EditComment(ctx, owner, repo, 3, reply.ID, "x"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a comment of another pull request, got %v", err)
+	}
+	if _, err := ghs.EditComment(ctx, owner, repo, 3, 820011001, "x"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("expected ErrForbidden for a comment of another user, got %v", err)
+	}
+	if _, err := ghs.ReplyToThread(ctx, owner, repo, 5, 1, "x"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing thread, got %v", err)
+	}
+	if _, err := ghs.DismissReview(ctx, owner, repo, 5, review.ID, "x"); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected ErrValidation for dismissing a dismissed review, got %v", err)
+	}
+}
+
+func TestServiceConversationCommentSharingReviewCommentID(t *testing.T) {
+	ctx := context.Background()
+	const owner, repo = "PeakIntegral", "cppLessons"
+
+	// Arrange: идентификатор комментария обсуждения запроса 5 совпадает с комментарием к коду запроса 3
+	f, err := ghsim.LoadFixtures("testdata/fixtures.json")
+	if err != nil {
+		t.Fatalf("load fixtures: %v", err)
+	}
+	for i := range f.Repositories {
+		if r := &f.Repositories[i]; r.Name == repo {
+			r.IssueComments = append(r.IssueComments, ghsim.IssueComment{
+				ID: 820011004, IssueNumber: 5, User: "jostanise", Body: "Посмотрю", CreatedAt: stringToTime("2022-03-08 12:00:00 +0000 UTC"),
+			})
+		}
+	}
+	ghs, _ := newSimServiceFrom(t, f)
+
+	// Act
+	edited, err := ghs.EditComment(ctx, owner, repo, 5, 820011004, "Посмотрел")
+	if err != nil {
+		t.Fatalf("EditComment: %v", err)
+	}
+	if err := ghs.DeleteComment(ctx, owner, repo, 5, 820011004); err != nil {
+		t.Fatalf("DeleteComment: %v", err)
+	}
+	comments, err := ghs.GetIssueComments(ctx, owner, repo, 5)
+	if err != nil {
+		t.Fatalf("GetIssueComments: %v", err)
+	}
+	threads, err := ghs.GetThreadsInfo(ctx, owner, repo, 3)
+	if err != nil {
+		t.Fatalf("GetThreadsInfo: %v", err)
+	}
+
+	// Assert
+	if edited.ID != 820011004 || edited.Body != "Посмотрел" || edited.ReviewID != 0 {
+		t.Errorf("Incorrect edited comment: %+v", *edited)
+	}
+	if len(comments) != 0 {
+		t.Errorf("Incorrect comments after delete: %+v", comments)
+	}
+	var reply *Comment
+	for _, th := range threads {
+		for i := range th.Conversation {
+			if th.Conversation[i].ID == 820011004 {
+				reply = &th.Conversation[i]
+			}
+		}
+	}
+	if reply == nil || reply.Body != "Исправил" {
+		t.Errorf("Incorrect review comment of another pull request: %+v", reply)
+	}
+	if _, err := ghs.EditComment(ctx, owner, repo, 5, 820011004, "x"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a deleted comment, got %v", err)
+	}
+}
+
+func TestServicePullRequestLifecycle(t *testing.T) {
+	ghs, _ := newSimService(t)
+	ctx := context.Background()
+	const owner, repo = "PeakIntegral", "cppLessons"
+
+	// Arrange
+	pr, err := ghs.CreatePullRequest(ctx, owner, repo, "patch-1", "create-sec-hero", "Fix typo",
+		WithPullRequestBody("Исправлена опечатка"), WithPullRequestDraft(true),
+		WithReviewers("PeakIntegral"), WithPullRequestLabels("documentation"))
+	if err != nil {
+		t.Fatalf("CreatePullRequest: %v", err)
+	}
+	if pr.Number != 8 || pr.State != StateDraft || pr.Body != "Исправлена опечатка" || pr.Author != "jostanise" ||
+		fmt.Sprint(pr.Reviewers) != "[PeakIntegral]" || fmt.Sprint(pr.Labels) != "[documentation]" ||
+		pr.HeadSHA != "f1e2d3c4b5a69788796a5b4c3d2e1f0a9b8c7d6e" || !strings.HasSuffix(pr.Link, "/PeakIntegral/cppLessons/pull/8") {
+		t.Errorf("Incorrect opened pull request: %+v", *pr)
+	}
+
+	// Act
+	ready, err := ghs.MarkPullRequestReadyForReview(ctx, owner, repo, pr.Number)
+	if err != nil {
+		t.Fatalf("MarkPullRequestReadyForReview: %v", err)
+	}
+	draft, err := ghs.ConvertPullRequestToDraft(ctx, owner, repo, pr.Number)
+	if err != nil {
+		t.Fatalf("ConvertPullRequestToDraft: %v", err)
+	}
+	if _, err := ghs.MarkPullRequestReadyForReview(ctx, owner, repo, pr.Number); err != nil {
+		t.Fatalf("MarkPullRequestReadyForReview: %v", err)
+	}
+	edited, err := ghs.UpdatePullRequestTitleBody(ctx, owner, repo, pr.Number, "Fix typos", "")
+	if err != nil {
+		t.Fatalf("UpdatePullRequestTitleBody: %v", err)
+	}
+	rebased, err := ghs.ChangePullRequestBase(ctx, owner, repo, pr.Number, "yura")
+	if err != nil {
+		t.Fatalf("ChangePullRequestBase: %v", err)
+	}
+	_, staleErr := ghs.MergePullRequest(ctx, owner, repo, pr.Number, MergeMethodSquash, "d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0")
+	sha, err := ghs.MergePullRequest(ctx, owner, repo, pr.Number, MergeMethodSquash, pr.HeadSHA)
+	if err != nil {
+		t.Fatalf("MergePullRequest: %v", err)
+	}
+	merged, err := ghs.GetPullRequest(ctx, owner, repo, pr.Number)
+	if err != nil {
+		t.Fatalf("GetPullRequest: %v", err)
+	}
+	commits, err := ghs.GetBranchCommits(ctx, owner, repo, "yura", WithMaxDepth(1))
+	if err != nil {
+		t.Fatalf("GetBranchCommits: %v", err)
+	}
+	closed, err := ghs.ClosePullRequest(ctx, owner, repo, 3)
+	if err != nil {
+		t.Fatalf("ClosePullRequest: %v", err)
+	}
+	reopened, err := ghs.ReopenPullRequest(ctx, owner, repo, 3)
+	if err != nil {
+		t.Fatalf("ReopenPullRequest: %v", err)
+	}
+
+	// Assert
+	if ready.State != StateOpen || draft.State != StateDraft {
+		t.Errorf("Incorrect draft states: %v, %v", ready.State, draft.State)
+	}
+	if edited.Title != "Fix typos" || edited.Body != "" {
+		t.Errorf("Incorrect edited pull request: %+v", *edited)
+	}
+	if rebased.TargetBranch != "yura" {
+		t.Errorf("Incorrect target branch: %v", rebased.TargetBranch)
+	}
+	if !errors.Is(staleErr, ErrConflict) {
+		t.Errorf("expected ErrConflict for a stale head SHA, got %v", staleErr)
+	}
+	if merged.State != StateMerged || merged.MergedAt.IsZero() {
+		t.Errorf("Incorrect merged pull request: %+v", *merged)
+	}
+	if len(commits) != 1 || commits[0].Hash != sha || fmt.Sprint(commits[0].Parents) != "[b7f4c2e9a1d3f5b7c9e1a3d5f7b9c1e3a5d7f9b1]" {
+		t.Errorf("Incorrect merge commit %v: %+v", sha, commits)
+	}
+	if closed.State != StateClosed || closed.ClosedAt.IsZero() || reopened.State != StateOpen || !reopened.ClosedAt.IsZero() {
+		t.Errorf("Incorrect closed and reopened pull requests: %+v, %+v", *closed, *reopened)
+	}
+
+	// Ошибки
+	if pr, err := ghs.CreatePullRequest(ctx, owner, repo, "patch-1", "main", "x", WithReviewers("jostanise")); pr == nil || !errors.Is(err, ErrValidation) {
+		t.Errorf("expected the pull request and ErrValidation for a review from the author, got %v, %v", pr, err)
+	}
+	if _, err := ghs.MergePullRequest(ctx, owner, repo, 5, MergeMethodMerge, ""); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrConflict for merging a draft, got %v", err)
+	}
+	if _, err := ghs.ReopenPullRequest(ctx, owner, repo, pr.Number); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected ErrValidation for reopening a merged pull request, got %v", err)
+	}
+	if _, err := ghs.ChangePullRequestBase(ctx, owner, repo, 3, "missing"); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected ErrValidation for a missing base branch, got %v", err)
+	}
+}
+
+func TestServicePullRequestChanges(t *testing.T) {
+	ctx := context.Background()
+	const owner, repo = "PeakIntegral", "cppLessons"
+
+	// Arrange: ветка readme-title меняет README.md, как и ветка patch-1
+	f, err := ghsim.LoadFixtures("testdata/fixtures.json")
+	if err != nil {
+		t.Fatalf("load fixtures: %v", err)
+	}
+	for i := range f.Repositories {
+		if r := &f.Repositories[i]; r.Name == repo {
+			r.Commits = append(r.Commits, ghsim.Commit{
+				SHA: "aa11bb22cc33dd44ee55ff6600112233445566aa", Message: "Shorter title", AuthorLogin: "PeakIntegral",
+				Date: stringToTime("2022-03-06 10:00:00 +0000 UTC"), Parents: []string{"d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0"}, Files: []string{"README.md"},
+			})
+			r.Branches["readme-title"] = "aa11bb22cc33dd44ee55ff6600112233445566aa"
+			r.PullRequests = append(r.PullRequests, ghsim.PullRequest{Number: 9, Title: "Shorter title", State: "open", User: "PeakIntegral", Head: "readme-title", Base: "patch-1"})
+		}
+	}
+	ghs, _ := newSimServiceFrom(t, f)
+
+	// Act
+	files, err := ghs.GetPullRequestFiles(ctx, owner, repo, 3)
+	if err != nil {
+		t.Fatalf("GetPullRequestFiles: %v", err)
+	}
+	diff, err := ghs.GetPullRequestDiff(ctx, owner, repo, 3)
+	if err != nil {
+		t.Fatalf("GetPullRequestDiff: %v", err)
+	}
+	patch, err := ghs.GetPullRequestPatch(ctx, owner, repo, 3)
+	if err != nil {
+		t.Fatalf("GetPullRequestPatch: %v", err)
+	}
+	commits, err := ghs.GetPullRequestCommits(ctx, owner, repo, 3)
+	if err != nil {
+		t.Fatalf("GetPullRequestCommits: %v", err)
+	}
+	mergedCommits, err := ghs.GetPullRequestCommits(ctx, owner, repo, 1)
+	if err != nil {
+		t.Fatalf("GetPullRequestCommits: %v", err)
+	}
+	clean, err := ghs.GetPullRequestMergeability(ctx, owner, repo, 3)
+	if err != nil {
+		t.Fatalf("GetPullRequestMergeability: %v", err)
+	}
+	dirty, err := ghs.GetPullRequestMergeability(ctx, owner, repo, 9)
+	if err != nil {
+		t.Fatalf("GetPullRequestMergeability: %v", err)
+	}
+	closed, err := ghs.GetPullRequestMergeability(ctx, owner, repo, 6)
+	if err != nil {
+		t.Fatalf("GetPullRequestMergeability: %v", err)
+	}
+
+	// Assert
+	if len(files) != 1 || files[0].Filename != "README.md" || files[0].Status != FileModified ||
+		files[0].Additions != 2 || files[0].Deletions != 1 || files[0].Changes != 3 || !strings.HasPrefix(files[0].Patch, "@@ -1,3 +1,4 @@") {
+		t.Errorf("Incorrect files: %+v", files)
+	}
+	if !strings.HasPrefix(diff, "diff --git a/README.md b/README.md\n--- a/README.md\n+++ b/README.md\n@@ -1,3 +1,4 @@\n") {
+		t.Errorf("Incorrect diff:\n%s", diff)
+	}
+	if !strings.HasPrefix(patch, "From f1e2d3c4b5a69788796a5b4c3d2e1f0a9b8c7d6e ") || !strings.Contains(patch, "Subject: [PATCH 1/1] Update README.md") {
+		t.Errorf("Incorrect patch:\n%s", patch)
+	}
+	if len(commits) != 1 || commits[0].Hash != "f1e2d3c4b5a69788796a5b4c3d2e1f0a9b8c7d6e" {
+		t.Errorf("Incorrect commits: %+v", commits)
+	}
+	if len(mergedCommits) != 1 || mergedCommits[0].Hash != "b7f4c2e9a1d3f5b7c9e1a3d5f7b9c1e3a5d7f9b1" {
+		t.Errorf("Incorrect commits of merged pull request: %+v", mergedCommits)
+	}
+	if !clean.Mergeable || clean.Conflicts || clean.State != MergeableClean || clean.HeadSHA != commits[0].Hash {
+		t.Errorf("Incorrect mergeability of clean pull request: %+v", *clean)
+	}
+	if dirty.Mergeable || !dirty.Conflicts || dirty.State != MergeableDirty {
+		t.Errorf("Incorrect mergeability of conflicting pull request: %+v", *dirty)
+	}
+	if closed.Mergeable || closed.State != MergeableUnknown {
+		t.Errorf("Incorrect mergeability of closed pull request: %+v", *closed)
+	}
+	if _, err := ghs.MergePullRequest(ctx, owner, repo, 9, MergeMethodMerge, ""); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrConflict for merging a conflicting pull request, got %v", err)
+	}
+}
+
+func TestServicePullRequestChangesIgnoreMaxItems(t *testing.T) {
+	ctx := context.Background()
+	const owner, repo = "PeakIntegral", "cppLessons"
+
+	// Arrange: запрос 9 из двух коммитов меняет два файла, а списки ограничены одним элементом
+	f, err := ghsim.LoadFixtures("testdata/fixtures.json")
+	if err != nil {
+		t.Fatalf("load fixtures: %v", err)
+	}
+	for i := range f.Repositories {
+		if r := &f.Repositories[i]; r.Name == repo {
+			r.Commits = append(r.Commits,
+				ghsim.Commit{
+					SHA: "aa11bb22cc33dd44ee55ff6600112233445566aa", Message: "Add third hero", AuthorLogin: "PeakIntegral",
+					Date: stringToTime("2022-03-06 10:00:00 +0000 UTC"), Parents: []string{"d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0"}, Files: []string{"heroes/third.cpp"},
+				},
+				ghsim.Commit{
+					SHA: "bb22cc33dd44ee55ff6600112233445566aa11bb", Message: "Call third hero", AuthorLogin: "PeakIntegral",
+					Date: stringToTime("2022-03-06 11:00:00 +0000 UTC"), Parents: []string{"aa11bb22cc33dd44ee55ff6600112233445566aa"}, Files: []string{"main.cpp"},
+				})
+			r.Branches["third-hero"] = "bb22cc33dd44ee55ff6600112233445566aa11bb"
+			r.PullRequests = append(r.PullRequests, ghsim.PullRequest{
+				Number: 9, Title: "Third hero", State: "open", User: "PeakIntegral", Head: "third-hero", Base: "main",
+				Files: []ghsim.PullFile{{Filename: "heroes/third.cpp", Status: "added", Additions: 1}, {Filename: "main.cpp", Additions: 1}},
+			})
+		}
+	}
+	ghs, _ := newSimServiceFrom(t, f, WithMaxItems(1))
+
+	// Act
+	files, err := ghs.GetPullRequestFiles(ctx, owner, repo, 9)
+	if err != nil {
+		t.Fatalf("GetPullRequestFiles: %v", err)
+	}
+	commits, err := ghs.GetPullRequestCommits(ctx, owner, repo, 9)
+	if err != nil {
+		t.Fatalf("GetPullRequestCommits: %v", err)
+	}
+
+	// Assert
+	if len(files) != 2 {
+		t.Errorf("Incorrect files: %+v", files)
+	}
+	if len(commits) != 2 {
+		t.Errorf("Incorrect commits: %+v", commits)
+	}
+}
+
+func TestServicePullRequestStates(t *testing.T) {
+	ghs, _ := newSimService(t)
+
+	// Arrange
+	testTable := []struct {
+		states   []State
+		expected []int
+	}{
+		{states: nil, expected: []int{6, 5, 3, 2, 1}},
+		{states: []State{StateOpen}, expected: []int{3}},
+		{states: []State{StateDraft}, expected: []int{5}},
+		{states: []State{StateOpen, StateDraft}, expected: []int{5, 3}},
+		{states: []State{StateMerged}, expected: []int{2, 1}},
+		{states: []State{StateClosed}, expected: []int{6}},
+		{states: []State{StateMerged, StateDraft}, expected: []int{5, 2, 1}},
+	}
+
+	for _, testCase := range testTable {
+		// Act
+		prs, err := ghs.GetRepositoryPullRequests(context.Background(), "PeakIntegral", "cppLessons", WithState(testCase.states...))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("GetRepositoryPullRequests: %v", err)
+		}
+		var numbers []int
+		for _, pr := range prs {
+			numbers = append(numbers, pr.Number)
+		}
+		if fmt.Sprint(numbers) != fmt.Sprint(testCase.expected) {
+			t.Errorf("Incorrect pull requests for %v: expected %v, got %v", testCase.states, testCase.expected, numbers)
+		}
+	}
+
+	prs, _ := ghs.GetRepositoryPullRequests(context.Background(), "PeakIntegral", "cppLessons", WithState(StateMerged))
+	merged := prs[len(prs)-1]
+	if merged.ID != 872634511 || merged.Author != "PeakIntegral" || merged.SourceBranch != "yura" ||
+		!merged.MergedAt.Equal(stringToTime("2022-03-05 18:17:54 +0000 UTC")) || !merged.ClosedAt.Equal(merged.MergedAt) {
+		t.Errorf("Incorrect merged pull request: %+v", *merged)
+	}
+
+	prs, _ = ghs.GetRepositoryPullRequests(context.Background(), "PeakIntegral", "cppLessons", WithState(StateOpen))
+	if open := prs[0]; fmt.Sprint(open.Labels) != "[documentation]" || fmt.Sprint(open.Assignees) != "[PeakIntegral]" ||
+		!open.ClosedAt.IsZero() || !open.MergedAt.IsZero() {
+		t.Errorf("Incorrect open pull request: %+v", *open)
+	}
+}
+
+func TestServiceIssueStates(t *testing.T) {
+	ghs, _ := newSimService(t)
+
+	// Arrange
+	testTable := []struct {
+		states   []State
+		expected []int
+	}{
+		{states: nil, expected: []int{7, 6, 5, 4, 3, 2, 1}},
+		{states: []State{StateOpen}, expected: []int{5, 4, 3}},
+		{states: []State{StateClosed}, expected: []int{7, 6, 2, 1}},
+	}
+
+	for _, testCase := range testTable {
+		// Act
+		issues, err := ghs.GetIssues(context.Background(), "PeakIntegral", "cppLessons", WithState(testCase.states...))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("GetIssues: %v", err)
+		}
+		var numbers []int
+		for _, issue := range issues {
+			numbers = append(numbers, issue.Number)
+		}
+		if fmt.Sprint(numbers) != fmt.Sprint(testCase.expected) {
+			t.Errorf("Incorrect issues for %v: expected %v, got %v", testCase.states, testCase.expected, numbers)
+		}
+	}
+
+	issues, _ := ghs.GetIssues(context.Background(), "PeakIntegral", "cppLessons", WithState(StateOpen))
+	bug := issues[1]
+	if bug.Title != "Heroes do not compile on Windows" || bug.PullRequestLink != "" || bug.Author != "jostanise" ||
+		fmt.Sprint(bug.Labels) != "[bug]" || fmt.Sprint(bug.Assignees) != "[PeakIntegral]" ||
+		bug.Body != "MSVC не находит hero.h" || bug.Milestone != 1 || bug.Comments != 1 {
+		t.Errorf("Incorrect issue: %+v", *bug)
+	}
+	if issues[0].PullRequestLink == "" {
+		t.Errorf("Issue %v is a pull request, but has no link", issues[0].Number)
+	}
+}
+
+func TestServiceIssueManagement(t *testing.T) {
+	ghs, _ := newSimService(t)
+	ctx := context.Background()
+	const owner, repo = "PeakIntegral", "cppLessons"
+
+	// Arrange
+	issue, err := ghs.CreateIssue(ctx, owner, repo, "Add Linux build",
+		WithIssueBody("Нужен Makefile"), WithIssueLabels("build"), WithIssueAssignees("PeakIntegral"), WithIssueMilestone(1))
+	if err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	if issue.Number != 8 || issue.State != StateOpen || issue.Author != "jostanise" || issue.Body != "Нужен Makefile" ||
+		fmt.Sprint(issue.Labels) != "[build]" || fmt.Sprint(issue.Assignees) != "[PeakIntegral]" || issue.Milestone != 1 {
+		t.Errorf("Incorrect created issue: %+v", *issue)
+	}
+
+	// Act
+	_, missingMilestoneErr := ghs.CreateIssue(ctx, owner, repo, "Add macOS build", WithIssueMilestone(9))
+	edited, err := ghs.EditIssue(ctx, owner, repo, issue.Number, WithIssueTitle("Add Linux and macOS builds"), WithIssueLabels())
+	if err != nil {
+		t.Fatalf("EditIssue: %v", err)
+	}
+	comment, err := ghs.CreateIssueComment(ctx, owner, repo, 4, "Собирается с /permissive-")
+	if err != nil {
+		t.Fatalf("CreateIssueComment: %v", err)
+	}
+	if _, err := ghs.EditIssueComment(ctx, owner, repo, 4, comment.ID, "Собирается с флагом /permissive-"); err != nil {
+		t.Fatalf("EditIssueComment: %v", err)
+	}
+	_, otherIssueErr := ghs.EditIssueComment(ctx, owner, repo, 7, comment.ID, "Не туда")
+	_, foreignErr := ghs.EditIssueComment(ctx, owner, repo, 4, 1060000002, "Чужой")
+	comments, err := ghs.GetIssueComments(ctx, owner, repo, 4)
+	if err != nil {
+		t.Fatalf("GetIssueComments: %v", err)
+	}
+	if err := ghs.DeleteIssueComment(ctx, owner, repo, 4, comment.ID); err != nil {
+		t.Fatalf("DeleteIssueComment: %v", err)
+	}
+	closed, err := ghs.CloseIssue(ctx, owner, repo, issue.Number, IssueNotPlanned)
+	if err != nil {
+		t.Fatalf("CloseIssue: %v", err)
+	}
+	badLockErr := ghs.LockIssue(ctx, owner, repo, issue.Number, "boring")
+	if err := ghs.LockIssue(ctx, owner, repo, issue.Number, LockResolved); err != nil {
+		t.Fatalf("LockIssue: %v", err)
+	}
+	locked, err := ghs.GetIssue(ctx, owner, repo, issue.Number)
+	if err != nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+	if err := ghs.UnlockIssue(ctx, owner, repo, issue.Number); err != nil {
+		t.Fatalf("UnlockIssue: %v", err)
+	}
+	reopened, err := ghs.ReopenIssue(ctx, owner, repo, issue.Number)
+	if err != nil {
+		t.Fatalf("ReopenIssue: %v", err)
+	}
+	bug, err := ghs.GetIssue(ctx, owner, repo, 4)
+	if err != nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+
+	// Assert
+	if !errors.Is(missingMilestoneErr, ErrValidation) {
+		t.Errorf("Incorrect error for missing milestone: %v", missingMilestoneErr)
+	}
+	if edited.Title != "Add Linux and macOS builds" || len(edited.Labels) != 0 || edited.Body != "Нужен Makefile" {
+		t.Errorf("Incorrect edited issue: %+v", *edited)
+	}
+	if !errors.Is(otherIssueErr, ErrNotFound) || !errors.Is(foreignErr, ErrForbidden) {
+		t.Errorf("Incorrect errors for comments: %v, %v", otherIssueErr, foreignErr)
+	}
+	if len(comments) != 2 || comments[1].ID != comment.ID || comments[1].Body != "Собирается с флагом /permissive-" || comments[1].Author != "jostanise" {
+		t.Errorf("Incorrect issue comments: %+v", comments)
+	}
+	if closed.State != StateClosed || closed.ClosedAt.IsZero() {
+		t.Errorf("Incorrect closed issue: %+v", *closed)
+	}
+	if !errors.Is(badLockErr, ErrValidation) || !locked.Locked || locked.LockReason != LockResolved {
+		t.Errorf("Incorrect locked issue: %v, %+v", badLockErr, *locked)
+	}
+	if reopened.State != StateOpen || !reopened.ClosedAt.IsZero() || reopened.Locked {
+		t.Errorf("Incorrect reopened issue: %+v", *reopened)
+	}
+	if bug.Comments != 1 {
+		t.Errorf("Incorrect comment count after delete: %v", bug.Comments)
+	}
+}
+
+func TestServicePagination(t *testing.T) {
+	// Arrange
+	f := &ghsim.Fixtures{Users: []ghsim.User{{Login: "octocat"}}}
+	for i := 0; i < 250; i++ {
+		f.Repositories = append(f.Repositories, ghsim.Repository{Owner: "octocat", Name: fmt.Sprintf("repo-%03d", i)})
+	}
+
+	testTable := []struct {
+		maxItems int
+		expected int
+		pages    int
+	}{
+		{maxItems: 0, expected: 250, pages: 3},
+		{maxItems: 120, expected: 120, pages: 2},
+		{maxItems: 5, expected: 5, pages: 1},
+	}
+
+	for _, testCase := range testTable {
+		ghs, sim := newSimServiceFrom(t, f, WithMaxItems(testCase.maxItems))
+
+		// Act
+		repos, err := ghs.GetUserRepositories(context.Background(), "octocat")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("GetUserRepositories: %v", err)
+		}
+		if len(repos) != testCase.expected {
+			t.Errorf("Incorrect amount of repositories for max %v: expected %v, got %v",
+				testCase.maxItems, testCase.expected, len(repos))
+		}
+
+		var pages int
+		for _, r := range sim.Requests() {
+			if r == "GET /users/octocat/repos" {
+				pages++
+			}
+		}
+		if pages != testCase.pages {
+			t.Errorf("Incorrect amount of pages for max %v: expected %v, got %v", testCase.maxItems, testCase.pages, pages)
+		}
+	}
+}
+
+func TestServiceRateLimit(t *testing.T) {
+	t.Run("fail fast", func(t *testing.T) {
+		ghs, sim := newSimService(t)
+		sim.SetRateLimit(60, 0, time.Now().Add(time.Hour))
+
+		_, err := ghs.GetUserInfo(context.Background(), "jostanise")
+
+		var rateErr *github.RateLimitError
+		if !errors.As(err, &rateErr) {
+			t.Fatalf("expected RateLimitError, got %v", err)
+		}
+		status, err := ghs.RateLimitStatus(context.Background())
+		if err != nil {
+			t.Fatalf("RateLimitStatus: %v", err)
+		}
+		if status.Limit != 60 || status.Remaining != 0 {
+			t.Errorf("Incorrect rate limit: %+v", *status)
+		}
+	})
+
+	t.Run("wait", func(t *testing.T) {
+		ghs, sim := newSimService(t, WithRateLimitPolicy(RateLimitWait))
+		sim.SetRateLimit(60, 0, time.Now().Add(500*time.Millisecond))
+
+		user, err := ghs.GetUserInfo(context.Background(), "jostanise")
+
+		if err != nil {
+			t.Fatalf("GetUserInfo: %v", err)
+		}
+		if user.UserName != "jostanise" {
+			t.Errorf("Incorrect user: %v", user.UserName)
+		}
+	})
+
+	t.Run("abuse", func(t *testing.T) {
+		ghs, sim := newSimService(t, WithRateLimitPolicy(RateLimitWait))
+		sim.InjectAbuseLimit("GET", "/users/jostanise", 0)
+
+		if _, err := ghs.GetUserInfo(context.Background(), "jostanise"); err != nil {
+			t.Fatalf("GetUserInfo: %v", err)
+		}
+		if n := len(sim.Requests()); n != 2 {
+			t.Errorf("expected 2 requests, got %v", n)
+		}
+	})
+}
+
+func TestServiceErrors(t *testing.T) {
+	ghs, sim := newSimService(t)
+	ctx := context.Background()
+
+	// Ошибка, заданная заранее, срабатывает один раз
+	sim.InjectError("GET", "/users/jostanise", http.StatusInternalServerError, "boom")
+	_, err := ghs.GetUserInfo(ctx, "jostanise")
+	var ghErr *github.ErrorResponse
+	if !errors.As(err, &ghErr) || ghErr.Response.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %v", err)
+	}
+	if _, err := ghs.GetUserInfo(ctx, "jostanise"); err != nil {
+		t.Errorf("GetUserInfo after injected error: %v", err)
+	}
+
+	// Несуществующий репозиторий
+	_, err = ghs.GetRepositoryByName(ctx, "jostanise", "missing")
+	if !errors.As(err, &ghErr) || ghErr.Response.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404, got %v", err)
+	}
+	var apiErr *Error
+	if !errors.Is(err, ErrNotFound) || !errors.As(err, &apiErr) || apiErr.Op != "get repo" || apiErr.RequestID == "" {
+		t.Errorf("expected ErrNotFound with operation and request ID, got %+v", err)
+	}
+
+	// Повторное создание ветки
+	sha := "0480a292df58ba0bb4851bf828ed25efc56da813"
+	if err := ghs.CreateBranch(ctx, "jostanise", "rsa_encrypted_local_chat", "feature", sha); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	err = ghs.CreateBranch(ctx, "jostanise", "rsa_encrypted_local_chat", "feature", sha)
+	if !errors.As(err, &ghErr) || ghErr.Response.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422, got %v", err)
+	}
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+
+	// Запрос на слияние из несуществующей ветки
+	_, err = ghs.CreatePullRequest(ctx, "jostanise", "rsa_encrypted_local_chat", "missing", "main", "title")
+	if !errors.As(err, &ghErr) || ghErr.Response.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422, got %v", err)
+	}
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("expected ErrValidation, got %v", err)
+	}
+}
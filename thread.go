@@ -0,0 +1,237 @@
+package notgogithub
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// Side - сторона diff, к которой относится комментарий
+type Side string
+
+const (
+	SideLeft  Side = "LEFT"  // Старая версия файла (удаленные строки)
+	SideRight Side = "RIGHT" // Новая версия файла (добавленные и неизмененные строки)
+)
+
+// Comment хранит комментарий в обсуждении запроса на слияние
+type Comment struct {
+	ID        int64     // Идентификатор комментария
+	ReviewID  int64     // Идентификатор ревью комментария (0 - комментарий в общем обсуждении)
+	InReplyTo int64     // Идентификатор комментария, на который дан ответ (0 - первый комментарий)
+	Author    string    // Логин автора
+	Body      string    // Текст комментария
+	Side      Side      // Сторона diff ("" в общем обсуждении)
+	StartLine int       // Первая строка комментируемого диапазона (равна EndLine для одной строки)
+	EndLine   int       // Последняя строка комментируемого диапазона (0 - комментарий ко всему файлу)
+	CommitSHA string    // SHA коммита, к версии которого оставлен комментарий
+	DiffHunk  string    // Фрагмент diff вокруг комментируемых строк
+	Outdated  bool      // Код под комментарием изменился, комментарий относится к старой версии
+	Resolved  bool      // Обсуждение комментария отмечено решенным
+	Link      string    // Ссылка на комментарий
+	CreatedAt time.Time // Дата создания
+	UpdatedAt time.Time // Дата изменения
+}
+
+func (ghs *gitHubService) GetThreadsInfo(ctx context.Context, userName, repositoryName string, pullRequestID int) ([]*Thread, error) {
+	comments, err := collectPages(ctx, 0, func(lo github.ListOptions) ([]*github.PullRequestComment, *github.Response, error) {
+		opts := github.PullRequestListCommentsOptions{Sort: "created", Direction: "asc", ListOptions: lo}
+		return callAPI(ctx, ghs, "list pull request comments", func() ([]*github.PullRequestComment, *github.Response, error) {
+			return ghs.client.PullRequests.ListComments(ctx, userName, repositoryName, pullRequestID, &opts)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	issueComments, err := collectPages(ctx, 0, func(lo github.ListOptions) ([]*github.IssueComment, *github.Response, error) {
+		opts := github.IssueListCommentsOptions{ListOptions: lo}
+		return callAPI(ctx, ghs, "list issue comments", func() ([]*github.IssueComment, *github.Response, error) {
+			return ghs.client.Issues.ListComments(ctx, userName, repositoryName, pullRequestID, &opts)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// GraphQL API недоступен без токена и не всем токенам, поэтому без него обсуждения
+	// возвращаются с неизвестной решенностью (Resolved false)
+	resolved, err := ghs.resolvedThreads(ctx, userName, repositoryName, pullRequestID)
+	if err != nil && !errors.Is(err, ErrUnauthorized) && !errors.Is(err, ErrForbidden) {
+		return nil, err
+	}
+
+	return buildThreads(comments, issueComments, resolved), nil
+}
+
+// buildThreads собирает обсуждения из комментариев к коду по цепочкам in_reply_to_id, независимо
+// от того, в каком ревью оставлен ответ. Комментарии общего обсуждения идут первым Thread с ID 0.
+// resolved - решенные обсуждения по идентификатору их первого комментария
+func buildThreads(comments []*github.PullRequestComment, issueComments []*github.IssueComment, resolved map[int64]bool) []*Thread {
+	var Threads []*Thread
+
+	if issueComments = nonNil(issueComments); len(issueComments) > 0 {
+		general := &Thread{}
+		for _, c := range issueComments {
+			general.Conversation = append(general.Conversation, newIssueComment(c))
+		}
+		Threads = append(Threads, general)
+	}
+
+	comments = nonNil(comments)
+	inReplyTo := make(map[int64]int64, len(comments))
+	for _, c := range comments {
+		inReplyTo[c.GetID()] = c.GetInReplyTo()
+	}
+	// GitHub указывает в ответе первый комментарий обсуждения, но на случай цепочки поднимаемся до него.
+	// Число шагов ограничено, чтобы зацикленный ответ не подвесил разбор
+	root := func(id int64) int64 {
+		for i := 0; i < len(inReplyTo); i++ {
+			parent, ok := inReplyTo[id]
+			if !ok || parent == 0 {
+				break
+			}
+			id = parent
+		}
+		return id
+	}
+
+	byRoot := make(map[int64]*Thread)
+	for _, c := range comments {
+		id := root(c.GetID())
+		thread, ok := byRoot[id]
+		if !ok {
+			thread = &Thread{ID: id, Resolved: resolved[id]}
+			byRoot[id] = thread
+			Threads = append(Threads, thread)
+		}
+		thread.Conversation = append(thread.Conversation, newComment(c, thread.Resolved))
+	}
+
+	for _, thread := range Threads {
+		sort.SliceStable(thread.Conversation, func(i, j int) bool {
+			return thread.Conversation[i].CreatedAt.Before(thread.Conversation[j].CreatedAt)
+		})
+		for _, c := range thread.Conversation {
+			thread.Comments = append(thread.Comments, c.Body)
+		}
+		if thread.ID != 0 {
+			first := thread.Conversation[0]
+			thread.Outdated = first.Outdated
+			thread.LineOfCode = uint64(first.EndLine)
+		}
+	}
+	// Пути файлов берутся из всех комментариев: в неполном ответе у первого пути может не быть
+	for _, c := range comments {
+		if thread := byRoot[root(c.GetID())]; thread.Filename == "" {
+			thread.Filename = c.GetPath()
+		}
+	}
+	return Threads
+}
+
+// newComment преобразует комментарий GitHub к коду в Comment. У устаревшего комментария
+// нет текущей позиции в diff, поэтому берутся строки и коммит, к которым он был оставлен.
+// У комментария ко всему файлу нет ни текущей, ни исходной позиции, и он не устаревает
+func newComment(c *github.PullRequestComment, resolved bool) Comment {
+	comment := Comment{
+		ID:        c.GetID(),
+		ReviewID:  c.GetPullRequestReviewID(),
+		InReplyTo: c.GetInReplyTo(),
+		Author:    c.GetUser().GetLogin(),
+		Body:      c.GetBody(),
+		Side:      Side(c.GetSide()),
+		StartLine: c.GetStartLine(),
+		EndLine:   c.GetLine(),
+		CommitSHA: c.GetCommitID(),
+		DiffHunk:  c.GetDiffHunk(),
+		Outdated:  c.Position == nil && c.OriginalPosition != nil,
+		Resolved:  resolved,
+		Link:      c.GetHTMLURL(),
+		CreatedAt: c.GetCreatedAt(),
+		UpdatedAt: c.GetUpdatedAt(),
+	}
+	if comment.Outdated {
+		comment.StartLine = c.GetOriginalStartLine()
+		comment.EndLine = c.GetOriginalLine()
+		comment.CommitSHA = c.GetOriginalCommitID()
+	}
+	if comment.StartLine == 0 {
+		comment.StartLine = comment.EndLine
+	}
+	return comment
+}
+
+// newIssueComment преобразует комментарий GitHub из общего обсуждения в Comment
+func newIssueComment(c *github.IssueComment) Comment {
+	return Comment{
+		ID:        c.GetID(),
+		Author:    c.GetUser().GetLogin(),
+		Body:      c.GetBody(),
+		Link:      c.GetHTMLURL(),
+		CreatedAt: c.GetCreatedAt(),
+		UpdatedAt: c.GetUpdatedAt(),
+	}
+}
+
+// reviewThreadsQuery запрашивает решенность обсуждений запроса на слияние: в REST API ее нет
+const reviewThreadsQuery = `query($owner: String!, $repo: String!, $number: Int!, $cursor: String) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100, after: $cursor) {
+        pageInfo { hasNextPage endCursor }
+        nodes { isResolved comments(first: 1) { nodes { databaseId } } }
+      }
+    }
+  }
+}`
+
+// reviewThreadsData - данные ответа GraphQL API на reviewThreadsQuery
+type reviewThreadsData struct {
+	Repository struct {
+		PullRequest struct {
+			ReviewThreads struct {
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				Nodes []struct {
+					IsResolved bool `json:"isResolved"`
+					Comments   struct {
+						Nodes []struct {
+							DatabaseID int64 `json:"databaseId"`
+						} `json:"nodes"`
+					} `json:"comments"`
+				} `json:"nodes"`
+			} `json:"reviewThreads"`
+		} `json:"pullRequest"`
+	} `json:"repository"`
+}
+
+// resolvedThreads возвращает решенные обсуждения запроса на слияние по идентификатору первого комментария
+func (ghs *gitHubService) resolvedThreads(ctx context.Context, userName, repositoryName string, pullRequestID int) (map[int64]bool, error) {
+	resolved := make(map[int64]bool)
+	var cursor *string
+	for {
+		page, err := graphQL[reviewThreadsData](ctx, ghs, "list review threads", reviewThreadsQuery, map[string]interface{}{
+			"owner": userName, "repo": repositoryName, "number": pullRequestID, "cursor": cursor,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		threads := page.Repository.PullRequest.ReviewThreads
+		for _, node := range threads.Nodes {
+			if node.IsResolved && len(node.Comments.Nodes) > 0 {
+				resolved[node.Comments.Nodes[0].DatabaseID] = true
+			}
+		}
+		if !threads.PageInfo.HasNextPage || threads.PageInfo.EndCursor == "" {
+			return resolved, nil
+		}
+		cursor = &threads.PageInfo.EndCursor
+	}
+}